@@ -0,0 +1,349 @@
+// Package client is a typed Go client for the /v1 API, shared by the
+// frontends. It depends only on the standard library so frontends can use it
+// without pulling in the server's dependencies.
+//
+//	api := client.New("http://localhost:8080")
+//	list, err := api.ListProperties(ctx, client.PropertyListParams{CityID: "-2601889"})
+//
+// Failed requests return an *Error carrying the problem+json details. Reads
+// and other idempotent requests are retried on transport errors, 429 and 5xx
+// gateway statuses. WithRevalidation keeps the ETag of each read so unchanged
+// catalogue responses come back as 304 without a body.
+package client
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "reflect"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+const (
+    DefaultMaxRetries = 2
+    DefaultBackoff    = 200 * time.Millisecond
+    DefaultTimeout    = 15 * time.Second
+)
+
+// Client calls the API at a base URL; copies made with Guest, Admin and
+// Language share its HTTP client
+type Client struct {
+    baseURL    string
+    httpClient *http.Client
+    maxRetries int
+    backoff    time.Duration
+    header     http.Header
+    validators *validatorCache
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient replaces the default HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+    return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithRetries sets how often an idempotent request is retried and the backoff
+// before the first retry, doubled for each further one
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+    return func(c *Client) { c.maxRetries, c.backoff = maxRetries, backoff }
+}
+
+// WithRevalidation remembers the ETag and body of up to size GET responses
+// and revalidates them with If-None-Match, reusing the body on 304
+func WithRevalidation(size int) Option {
+    return func(c *Client) { c.validators = &validatorCache{size: size, entries: map[string]validated{}} }
+}
+
+// New creates a client for the API at baseURL, e.g. http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+    c := &Client{
+        baseURL:    strings.TrimRight(baseURL, "/"),
+        httpClient: &http.Client{Timeout: DefaultTimeout},
+        maxRetries: DefaultMaxRetries,
+        backoff:    DefaultBackoff,
+        header:     http.Header{},
+    }
+    for _, opt := range opts {
+        opt(c)
+    }
+    return c
+}
+
+func (c *Client) with(name, value string) *Client {
+    copied := *c
+    copied.header = c.header.Clone()
+    copied.header.Set(name, value)
+    return &copied
+}
+
+// Guest returns a copy of the client acting for a guest, as required by the
+// wishlist and saved search methods
+func (c *Client) Guest(guestID string) *Client {
+    return c.with("X-Guest-ID", guestID)
+}
+
+// Host returns a copy of the client acting for a host, as required by the
+// host photo methods
+func (c *Client) Host(hostID string) *Client {
+    return c.with("X-Host-ID", hostID)
+}
+
+// Admin returns a copy of the client authenticated for the admin methods
+func (c *Client) Admin(token string) *Client {
+    return c.with("X-Admin-Token", token)
+}
+
+// Language returns a copy of the client asking for content in the given
+// Accept-Language preference, e.g. "fr-FR, fr;q=0.9"
+func (c *Client) Language(acceptLanguage string) *Client {
+    return c.with("Accept-Language", acceptLanguage)
+}
+
+// Error is a failed API request, decoded from its problem+json body
+type Error struct {
+    StatusCode int
+    Type       string       `json:"type"`
+    Title      string       `json:"title"`
+    Detail     string       `json:"detail"`
+    RequestID  string       `json:"request_id"`
+    Errors     []FieldError `json:"errors"`
+}
+
+// FieldError is the problem with one request parameter
+type FieldError struct {
+    Field   string `json:"field"`
+    Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+    message := e.Detail
+    if message == "" {
+        message = e.Title
+    }
+    if message == "" {
+        message = http.StatusText(e.StatusCode)
+    }
+    return fmt.Sprintf("api: %d %s", e.StatusCode, message)
+}
+
+// IsNotFound reports whether err is an API 404
+func IsNotFound(err error) bool {
+    var apiErr *Error
+    return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+func retryable(status int) bool {
+    switch status {
+    case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+        return true
+    }
+    return false
+}
+
+func idempotent(method string) bool {
+    return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+}
+
+// do sends a request and decodes a successful JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+    target := c.baseURL + path
+    if len(query) > 0 {
+        target += "?" + query.Encode()
+    }
+
+    var payload []byte
+    if body != nil {
+        var err error
+        if payload, err = json.Marshal(body); err != nil {
+            return fmt.Errorf("api: encoding %s %s: %w", method, path, err)
+        }
+    }
+
+    retries := 0
+    if idempotent(method) {
+        retries = c.maxRetries
+    }
+    backoff := c.backoff
+
+    var key string
+    var cached validated
+    if method == http.MethodGet && c.validators != nil {
+        key = target + "\x00" + c.header.Get("Accept-Language")
+        cached, _ = c.validators.get(key)
+    }
+
+    for attempt := 0; ; attempt++ {
+        resp, err := c.send(ctx, method, target, payload, cached.etag)
+        if err == nil && (attempt == retries || !retryable(resp.StatusCode)) {
+            defer resp.Body.Close()
+            if key != "" {
+                return c.revalidate(resp, key, cached, out)
+            }
+            return decode(resp, out)
+        }
+        if attempt == retries {
+            return fmt.Errorf("api: %s %s: %w", method, path, err)
+        }
+
+        wait := backoff
+        if err == nil {
+            if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+                wait = time.Duration(seconds) * time.Second
+            }
+            io.Copy(io.Discard, resp.Body)
+            resp.Body.Close()
+        }
+        backoff *= 2
+
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case <-time.After(wait):
+        }
+    }
+}
+
+func (c *Client) send(ctx context.Context, method, target string, payload []byte, etag string) (*http.Response, error) {
+    var body io.Reader
+    if payload != nil {
+        body = bytes.NewReader(payload)
+    }
+    req, err := http.NewRequestWithContext(ctx, method, target, body)
+    if err != nil {
+        return nil, err
+    }
+    for name, values := range c.header {
+        req.Header[name] = values
+    }
+    req.Header.Set("Accept", "application/json")
+    if payload != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    if etag != "" {
+        req.Header.Set("If-None-Match", etag)
+    }
+    return c.httpClient.Do(req)
+}
+
+// revalidate decodes the remembered body on 304 and remembers successful
+// responses that carry an ETag
+func (c *Client) revalidate(resp *http.Response, key string, cached validated, out interface{}) error {
+    if resp.StatusCode == http.StatusNotModified && cached.etag != "" {
+        return decodeBody(resp, cached.body, out)
+    }
+    etag := resp.Header.Get("ETag")
+    if resp.StatusCode != http.StatusOK || etag == "" {
+        return decode(resp, out)
+    }
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        return fmt.Errorf("api: reading %s response: %w", resp.Request.URL.Path, err)
+    }
+    c.validators.put(key, validated{etag: etag, body: body})
+    return decodeBody(resp, body, out)
+}
+
+func decodeBody(resp *http.Response, body []byte, out interface{}) error {
+    if out == nil {
+        return nil
+    }
+    if err := json.Unmarshal(body, out); err != nil {
+        return fmt.Errorf("api: decoding %s response: %w", resp.Request.URL.Path, err)
+    }
+    return nil
+}
+
+// validatorCache holds the last ETag and body of GET responses, shared by
+// the copies of a client
+type validatorCache struct {
+    mu      sync.Mutex
+    size    int
+    entries map[string]validated
+}
+
+type validated struct {
+    etag string
+    body []byte
+}
+
+func (v *validatorCache) get(key string) (validated, bool) {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    entry, ok := v.entries[key]
+    return entry, ok
+}
+
+// put stores an entry, dropping an arbitrary one when the cache is full
+func (v *validatorCache) put(key string, entry validated) {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+    if _, ok := v.entries[key]; !ok && len(v.entries) >= v.size {
+        for old := range v.entries {
+            delete(v.entries, old)
+            break
+        }
+    }
+    v.entries[key] = entry
+}
+
+func decode(resp *http.Response, out interface{}) error {
+    if resp.StatusCode >= 300 {
+        apiErr := &Error{StatusCode: resp.StatusCode}
+        // Bodies that are not problem+json still yield the status
+        json.NewDecoder(resp.Body).Decode(apiErr)
+        if apiErr.RequestID == "" {
+            apiErr.RequestID = resp.Header.Get("X-Request-ID")
+        }
+        return apiErr
+    }
+    if out == nil {
+        return nil
+    }
+    if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+        return fmt.Errorf("api: decoding %s response: %w", resp.Request.URL.Path, err)
+    }
+    return nil
+}
+
+// values encodes the `form` tagged fields of a parameter struct, and of the
+// structs embedded in it, as query parameters; zero values are left out
+func values(params interface{}) url.Values {
+    query := url.Values{}
+    addValues(query, reflect.ValueOf(params))
+    return query
+}
+
+func addValues(query url.Values, v reflect.Value) {
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        field, value := t.Field(i), v.Field(i)
+        if field.Anonymous && value.Kind() == reflect.Struct {
+            addValues(query, value)
+            continue
+        }
+        name := field.Tag.Get("form")
+        if name == "" || value.IsZero() {
+            continue
+        }
+        switch value.Kind() {
+        case reflect.Slice:
+            items := make([]string, value.Len())
+            for j := range items {
+                items[j] = fmt.Sprint(value.Index(j).Interface())
+            }
+            query.Set(name, strings.Join(items, ","))
+        default:
+            query.Set(name, fmt.Sprint(value.Interface()))
+        }
+    }
+}
@@ -0,0 +1,154 @@
+package client
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "sort"
+    "testing"
+    "time"
+
+    "backend_rental/controllers"
+    "backend_rental/models"
+    "backend_rental/services"
+    "backend_rental/utils/apiclient"
+    "backend_rental/utils/pagination"
+)
+
+func TestListPropertiesRetriesAndDecodes(t *testing.T) {
+    calls := 0
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        calls++
+        if calls == 1 {
+            w.WriteHeader(http.StatusServiceUnavailable)
+            return
+        }
+        if got := r.URL.Query().Encode(); got != "amenities=wifi%2Cpool&city_id=42&limit=5" {
+            t.Errorf("query = %s", got)
+        }
+        if r.Header.Get("Accept-Language") != "fr" {
+            t.Errorf("Accept-Language = %q", r.Header.Get("Accept-Language"))
+        }
+        w.Write([]byte(`{"data":[{"dest_id":"7","name":"Loft"}],"pagination":{"limit":5,"next_cursor":"abc"},"facets":{"types":[{"value":"Apartment","count":1}]}}`))
+    }))
+    defer server.Close()
+
+    api := New(server.URL, WithRetries(2, time.Millisecond)).Language("fr")
+    list, err := api.ListProperties(context.Background(), PropertyListParams{
+        PageParams: PageParams{Limit: 5},
+        CityID:     "42",
+        Amenities:  []string{"wifi", "pool"},
+    })
+    if err != nil {
+        t.Fatalf("ListProperties: %v", err)
+    }
+    if calls != 2 || len(list.Data) != 1 || list.Data[0].DestID != "7" || list.Pagination.NextCursor != "abc" || list.Facets.Types[0].Count != 1 {
+        t.Errorf("calls = %d, list = %+v", calls, list)
+    }
+}
+
+func TestErrorsCarryProblemDetails(t *testing.T) {
+    calls := 0
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        calls++
+        w.Header().Set("Content-Type", "application/problem+json")
+        w.WriteHeader(http.StatusBadRequest)
+        w.Write([]byte(`{"title":"Bad Request","status":400,"detail":"invalid","request_id":"r1","errors":[{"field":"name","message":"is required"}]}`))
+    }))
+    defer server.Close()
+
+    _, err := New(server.URL).Guest("g1").CreateWishlist(context.Background(), "")
+    var apiErr *Error
+    if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 || apiErr.RequestID != "r1" || apiErr.Errors[0].Field != "name" {
+        t.Fatalf("error = %#v", err)
+    }
+    if calls != 1 {
+        t.Errorf("a POST was sent %d times", calls)
+    }
+}
+
+func TestRevalidationReusesBodyOnNotModified(t *testing.T) {
+    calls := 0
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        calls++
+        w.Header().Set("ETag", `"v1"`)
+        if r.Header.Get("If-None-Match") == `"v1"` {
+            w.WriteHeader(http.StatusNotModified)
+            return
+        }
+        w.Write([]byte(`{"France":["Paris"]}`))
+    }))
+    defer server.Close()
+
+    api := New(server.URL, WithRevalidation(10))
+    for i := 0; i < 2; i++ {
+        cities, err := api.CountriesAndCities(context.Background())
+        if err != nil {
+            t.Fatalf("CountriesAndCities: %v", err)
+        }
+        if len(cities["France"]) != 1 {
+            t.Errorf("request %d: cities = %v", i+1, cities)
+        }
+    }
+    if calls != 2 {
+        t.Errorf("calls = %d", calls)
+    }
+}
+
+// jsonKeys returns the top-level keys a value encodes to
+func jsonKeys(t *testing.T, v interface{}) []string {
+    t.Helper()
+    data, err := json.Marshal(v)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var fields map[string]json.RawMessage
+    if err := json.Unmarshal(data, &fields); err != nil {
+        t.Fatal(err)
+    }
+    keys := make([]string, 0, len(fields))
+    for key := range fields {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    return keys
+}
+
+func TestTypesMatchModels(t *testing.T) {
+    money := &models.Money{}
+    token := "t"
+    pairs := []struct{ server, client interface{} }{
+        {pagination.Page{}, Pagination{}},
+        {models.Location{}, Location{}},
+        {models.BookingSummary{}, LocationSummary{}},
+        {models.Property{DisplayPrice: money}, Property{DisplayPrice: &Money{}}},
+        {models.PropertyFacets{}, PropertyFacets{}},
+        {models.PropertyDetails{DisplayPrice: money}, PropertyDetails{DisplayPrice: &Money{}}},
+        {models.PropertyDescription{}, PropertyDescription{}},
+        {models.CategorizedImages{}, CategorizedImages{}},
+        {models.PropertyPhoto{Caption: "c"}, PropertyPhoto{Caption: "c"}},
+        {models.PropertyPhotos{}, PropertyPhotos{}},
+        {models.Review{}, Review{}},
+        {models.ReviewAggregate{}, ReviewAggregate{}},
+        {models.PropertySearchResult{}, SearchResult{}},
+        {models.Language{}, Language{}},
+        {models.ExchangeRate{}, ExchangeRate{}},
+        {models.Wishlist{ShareToken: &token}, Wishlist{ShareToken: "t"}},
+        {models.WishlistItem{Property: &models.Property{}}, WishlistItem{Property: &Property{}}},
+        {controllers.WishlistResponse{}, WishlistDetail{}},
+        {controllers.ShareResponse{}, Share{}},
+        {models.SavedSearch{}, SavedSearch{}},
+        {services.SavedSearchRun{}, SavedSearchRun{}},
+        {models.CrawlRun{Error: "e"}, CrawlRun{Error: "e"}},
+        {apiclient.CacheStats{}, CacheStats{}},
+    }
+    for _, pair := range pairs {
+        server, client := jsonKeys(t, pair.server), jsonKeys(t, pair.client)
+        if !reflect.DeepEqual(server, client) {
+            t.Errorf("%T encodes %v, client %T has %v", pair.server, server, pair.client, client)
+        }
+    }
+}
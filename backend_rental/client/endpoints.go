@@ -0,0 +1,380 @@
+package client
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/url"
+    "strconv"
+)
+
+// The deprecated /v1/booking aliases are not exposed; use the location and
+// crawl methods they forward to.
+
+// PageParams selects a page of a list endpoint
+type PageParams struct {
+    Limit        int    `form:"limit"`
+    Cursor       string `form:"cursor"`
+    IncludeTotal bool   `form:"include_total"`
+}
+
+// LocationListParams filters the location list
+type LocationListParams struct {
+    PageParams
+    Country  string `form:"country"`
+    CityName string `form:"city_name"`
+}
+
+// PropertyListParams filters, sorts and prices the property list
+type PropertyListParams struct {
+    PageParams
+    CityID       string   `form:"city_id"`
+    CityName     string   `form:"city_name"`
+    Name         string   `form:"name"`
+    Country      string   `form:"country"`
+    Type         string   `form:"type"`
+    MinBedrooms  int      `form:"min_bedrooms"`
+    MaxBedrooms  int      `form:"max_bedrooms"`
+    MinBathrooms int      `form:"min_bathrooms"`
+    MaxBathrooms int      `form:"max_bathrooms"`
+    MinRating    float64  `form:"rating"`
+    Amenities    []string `form:"amenities"`
+    // Sort is one of newest, name, price_asc, price_desc, rating or bedrooms
+    Sort     string `form:"sort"`
+    Currency string `form:"currency"`
+}
+
+// SearchParams is a free-text property search
+type SearchParams struct {
+    PageParams
+    Q string `form:"q"`
+}
+
+// ReviewListParams pages the approved reviews of a property
+type ReviewListParams struct {
+    PageParams
+    DestID string `form:"dest_id"`
+    // Sort is one of newest, oldest, highest or lowest
+    Sort string `form:"sort"`
+}
+
+// ReviewStatusParams pages the reviews in one moderation status
+type ReviewStatusParams struct {
+    PageParams
+    // Status is one of pending, approved or rejected
+    Status string `form:"status"`
+}
+
+// NewReview is a review submitted by a guest; the API averages the scores
+// into the overall score
+type NewReview struct {
+    DestID        string  `json:"dest_id"`
+    AuthorName    string  `json:"author_name"`
+    StayReference string  `json:"stay_reference,omitempty"`
+    Cleanliness   float64 `json:"cleanliness"`
+    Location      float64 `json:"location"`
+    Value         float64 `json:"value"`
+    Text          string  `json:"text,omitempty"`
+    Language      string  `json:"language,omitempty"`
+}
+
+func pathID(id int64) string {
+    return strconv.FormatInt(id, 10)
+}
+
+// OpenAPI returns the OpenAPI 3 document of the API
+func (c *Client) OpenAPI(ctx context.Context) (json.RawMessage, error) {
+    var doc json.RawMessage
+    err := c.do(ctx, http.MethodGet, "/v1/openapi.json", nil, nil, &doc)
+    return doc, err
+}
+
+// ListLocations pages the crawled locations
+func (c *Client) ListLocations(ctx context.Context, params LocationListParams) (*List[Location], error) {
+    var list List[Location]
+    err := c.do(ctx, http.MethodGet, "/v1/locations", values(params), nil, &list)
+    return &list, err
+}
+
+// GetLocation returns a location by ID
+func (c *Client) GetLocation(ctx context.Context, id string) (*Location, error) {
+    var location Location
+    err := c.do(ctx, http.MethodGet, "/v1/locations/"+url.PathEscape(id), nil, nil, &location)
+    return &location, err
+}
+
+// LocationSummary indexes the stored locations and properties by country and city
+func (c *Client) LocationSummary(ctx context.Context) (*LocationSummary, error) {
+    var summary LocationSummary
+    err := c.do(ctx, http.MethodGet, "/v1/locations/summary", nil, nil, &summary)
+    return &summary, err
+}
+
+// CountriesAndCities lists the cities of each country
+func (c *Client) CountriesAndCities(ctx context.Context) (map[string][]string, error) {
+    var cities map[string][]string
+    err := c.do(ctx, http.MethodGet, "/v1/property/countries-cities", nil, nil, &cities)
+    return cities, err
+}
+
+// ListProperties filters, sorts and pages the properties
+func (c *Client) ListProperties(ctx context.Context, params PropertyListParams) (*PropertyList, error) {
+    var list PropertyList
+    err := c.do(ctx, http.MethodGet, "/v1/property/list", values(params), nil, &list)
+    return &list, err
+}
+
+// PropertyDetails returns the stored details of properties keyed by ID,
+// priced in currency when it is not empty
+func (c *Client) PropertyDetails(ctx context.Context, propertyIDs []string, currency string) (map[string]*PropertyDetails, error) {
+    query := url.Values{}
+    if currency != "" {
+        query.Set("currency", currency)
+    }
+    body := map[string][]string{"property_ids": propertyIDs}
+
+    var details map[string]*PropertyDetails
+    err := c.do(ctx, http.MethodPost, "/v1/property/details", query, body, &details)
+    return details, err
+}
+
+// PropertyDescription returns the localized description of a property
+func (c *Client) PropertyDescription(ctx context.Context, destID string) (*PropertyDescription, error) {
+    var description PropertyDescription
+    err := c.do(ctx, http.MethodGet, "/v1/property/description", url.Values{"dest_id": {destID}}, nil, &description)
+    return &description, err
+}
+
+// PropertyImages returns the description of a property with its categorized images
+func (c *Client) PropertyImages(ctx context.Context, destID string) (*PropertyDescription, error) {
+    var description PropertyDescription
+    err := c.do(ctx, http.MethodGet, "/v1/property/images", url.Values{"dest_id": {destID}}, nil, &description)
+    return &description, err
+}
+
+// PropertyPhotos returns every photo of a property grouped by tag; category
+// keeps only the photos of one tag when it is not empty
+func (c *Client) PropertyPhotos(ctx context.Context, destID, category string) (*PropertyPhotos, error) {
+    query := url.Values{"dest_id": {destID}}
+    if category != "" {
+        query.Set("category", category)
+    }
+    var photos PropertyPhotos
+    err := c.do(ctx, http.MethodGet, "/v1/property/photos", query, nil, &photos)
+    return &photos, err
+}
+
+// UpdatePhoto sets the tag and caption of one of the host's photos; photos
+// are uploaded as multipart/form-data, which this client does not send
+func (c *Client) UpdatePhoto(ctx context.Context, photoID int64, tag, caption string) (*PropertyPhoto, error) {
+    var photo PropertyPhoto
+    body := map[string]string{"tag": tag, "caption": caption}
+    err := c.do(ctx, http.MethodPut, "/v1/property/photos/"+pathID(photoID), nil, body, &photo)
+    return &photo, err
+}
+
+// ReorderPhotos sets the order of the host photos of a property; photoIDs
+// must list each of them once
+func (c *Client) ReorderPhotos(ctx context.Context, destID string, photoIDs []int64) ([]PropertyPhoto, error) {
+    var photos []PropertyPhoto
+    body := map[string]interface{}{"dest_id": destID, "photo_ids": photoIDs}
+    err := c.do(ctx, http.MethodPut, "/v1/property/photos/order", nil, body, &photos)
+    return photos, err
+}
+
+// DeletePhoto deletes one of the host's photos
+func (c *Client) DeletePhoto(ctx context.Context, photoID int64) error {
+    return c.do(ctx, http.MethodDelete, "/v1/property/photos/"+pathID(photoID), nil, nil, nil)
+}
+
+// SearchProperties ranks properties against a free-text query
+func (c *Client) SearchProperties(ctx context.Context, params SearchParams) (*List[SearchResult], error) {
+    var list List[SearchResult]
+    err := c.do(ctx, http.MethodGet, "/v1/property/search", values(params), nil, &list)
+    return &list, err
+}
+
+// ListReviews pages the approved reviews of a property
+func (c *Client) ListReviews(ctx context.Context, params ReviewListParams) (*ReviewList, error) {
+    var list ReviewList
+    err := c.do(ctx, http.MethodGet, "/v1/property/reviews", values(params), nil, &list)
+    return &list, err
+}
+
+// SubmitReview submits a review for moderation
+func (c *Client) SubmitReview(ctx context.Context, review NewReview) (*Review, error) {
+    var submitted Review
+    err := c.do(ctx, http.MethodPost, "/v1/property/reviews", nil, review, &submitted)
+    return &submitted, err
+}
+
+// Languages lists the supported content languages
+func (c *Client) Languages(ctx context.Context) ([]Language, error) {
+    var languages []Language
+    err := c.do(ctx, http.MethodGet, "/v1/languages", nil, nil, &languages)
+    return languages, err
+}
+
+// Currencies lists the FX rates
+func (c *Client) Currencies(ctx context.Context) ([]ExchangeRate, error) {
+    var rates []ExchangeRate
+    err := c.do(ctx, http.MethodGet, "/v1/currencies", nil, nil, &rates)
+    return rates, err
+}
+
+// GraphQL runs a query against /v1/graphql. Queries rejected before running
+// (syntax, validation, cost limits) fail with a 400 *Error; field errors of a
+// query that ran are returned in the response next to the data.
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+    var response GraphQLResponse
+    body := map[string]interface{}{"query": query, "variables": variables}
+    err := c.do(ctx, http.MethodPost, "/v1/graphql", nil, body, &response)
+    return &response, err
+}
+
+// ListWishlists lists the guest's wishlists
+func (c *Client) ListWishlists(ctx context.Context) ([]Wishlist, error) {
+    var wishlists []Wishlist
+    err := c.do(ctx, http.MethodGet, "/v1/wishlists", nil, nil, &wishlists)
+    return wishlists, err
+}
+
+// CreateWishlist creates a wishlist for the guest
+func (c *Client) CreateWishlist(ctx context.Context, name string) (*Wishlist, error) {
+    var wishlist Wishlist
+    err := c.do(ctx, http.MethodPost, "/v1/wishlists", nil, map[string]string{"name": name}, &wishlist)
+    return &wishlist, err
+}
+
+// GetWishlist returns one of the guest's wishlists with its items
+func (c *Client) GetWishlist(ctx context.Context, id int64) (*WishlistDetail, error) {
+    var detail WishlistDetail
+    err := c.do(ctx, http.MethodGet, "/v1/wishlists/"+pathID(id), nil, nil, &detail)
+    return &detail, err
+}
+
+// GetSharedWishlist returns a wishlist by its share token; no guest is needed
+func (c *Client) GetSharedWishlist(ctx context.Context, token string) (*WishlistDetail, error) {
+    var detail WishlistDetail
+    err := c.do(ctx, http.MethodGet, "/v1/wishlists/shared/"+url.PathEscape(token), nil, nil, &detail)
+    return &detail, err
+}
+
+// RenameWishlist renames one of the guest's wishlists
+func (c *Client) RenameWishlist(ctx context.Context, id int64, name string) (*Wishlist, error) {
+    var wishlist Wishlist
+    err := c.do(ctx, http.MethodPut, "/v1/wishlists/"+pathID(id), nil, map[string]string{"name": name}, &wishlist)
+    return &wishlist, err
+}
+
+// DeleteWishlist deletes one of the guest's wishlists
+func (c *Client) DeleteWishlist(ctx context.Context, id int64) error {
+    return c.do(ctx, http.MethodDelete, "/v1/wishlists/"+pathID(id), nil, nil, nil)
+}
+
+// AddWishlistItem adds a property to a wishlist and returns the updated wishlist
+func (c *Client) AddWishlistItem(ctx context.Context, id int64, destID string) (*WishlistDetail, error) {
+    var detail WishlistDetail
+    err := c.do(ctx, http.MethodPost, "/v1/wishlists/"+pathID(id)+"/items", nil, map[string]string{"dest_id": destID}, &detail)
+    return &detail, err
+}
+
+// RemoveWishlistItem removes a property from a wishlist and returns the updated wishlist
+func (c *Client) RemoveWishlistItem(ctx context.Context, id int64, destID string) (*WishlistDetail, error) {
+    var detail WishlistDetail
+    err := c.do(ctx, http.MethodDelete, "/v1/wishlists/"+pathID(id)+"/items/"+url.PathEscape(destID), nil, nil, &detail)
+    return &detail, err
+}
+
+// ShareWishlist makes a wishlist readable by its share token
+func (c *Client) ShareWishlist(ctx context.Context, id int64) (*Share, error) {
+    var share Share
+    err := c.do(ctx, http.MethodPost, "/v1/wishlists/"+pathID(id)+"/share", nil, nil, &share)
+    return &share, err
+}
+
+// ListSavedSearches lists the guest's saved searches
+func (c *Client) ListSavedSearches(ctx context.Context) ([]SavedSearch, error) {
+    var searches []SavedSearch
+    err := c.do(ctx, http.MethodGet, "/v1/saved-searches", nil, nil, &searches)
+    return searches, err
+}
+
+// CreateSavedSearch saves a named property search; params are the
+// /v1/property/list query parameters
+func (c *Client) CreateSavedSearch(ctx context.Context, name string, params PropertyListParams) (*SavedSearch, error) {
+    query := map[string]string{}
+    for key, value := range values(params) {
+        query[key] = value[0]
+    }
+    body := map[string]interface{}{"name": name, "params": query}
+
+    var search SavedSearch
+    err := c.do(ctx, http.MethodPost, "/v1/saved-searches", nil, body, &search)
+    return &search, err
+}
+
+// DeleteSavedSearch deletes one of the guest's saved searches
+func (c *Client) DeleteSavedSearch(ctx context.Context, id int64) error {
+    return c.do(ctx, http.MethodDelete, "/v1/saved-searches/"+pathID(id), nil, nil, nil)
+}
+
+// RunSavedSearch runs a saved search and reports the properties new since its last run
+func (c *Client) RunSavedSearch(ctx context.Context, id int64) (*SavedSearchRun, error) {
+    var run SavedSearchRun
+    err := c.do(ctx, http.MethodPost, "/v1/saved-searches/"+pathID(id)+"/run", nil, nil, &run)
+    return &run, err
+}
+
+// UpdateRates replaces the FX rate table; requires Admin
+func (c *Client) UpdateRates(ctx context.Context, base string, rates map[string]float64) ([]ExchangeRate, error) {
+    body := map[string]interface{}{"base": base, "rates": rates}
+
+    var updated []ExchangeRate
+    err := c.do(ctx, http.MethodPut, "/v1/admin/fx-rates", nil, body, &updated)
+    return updated, err
+}
+
+// ListReviewsByStatus pages the reviews in one moderation status; requires Admin
+func (c *Client) ListReviewsByStatus(ctx context.Context, params ReviewStatusParams) (*List[Review], error) {
+    var list List[Review]
+    err := c.do(ctx, http.MethodGet, "/v1/admin/reviews", values(params), nil, &list)
+    return &list, err
+}
+
+// ModerateReview sets the moderation status of a review; requires Admin
+func (c *Client) ModerateReview(ctx context.Context, id int64, status string) (*Review, error) {
+    var review Review
+    err := c.do(ctx, http.MethodPut, "/v1/admin/reviews/"+pathID(id)+"/moderation", nil, map[string]string{"status": status}, &review)
+    return &review, err
+}
+
+// Reindex rebuilds the search index and returns how many properties were indexed; requires Admin
+func (c *Client) Reindex(ctx context.Context) (int, error) {
+    var result struct {
+        Indexed int `json:"indexed"`
+    }
+    err := c.do(ctx, http.MethodPost, "/v1/admin/search/reindex", nil, nil, &result)
+    return result.Indexed, err
+}
+
+// StartCrawl starts a location crawl; requires Admin
+func (c *Client) StartCrawl(ctx context.Context) (*CrawlRun, error) {
+    var run CrawlRun
+    err := c.do(ctx, http.MethodPost, "/v1/admin/crawls", nil, nil, &run)
+    return &run, err
+}
+
+// GetCrawl returns a crawl run; requires Admin
+func (c *Client) GetCrawl(ctx context.Context, id int64) (*CrawlRun, error) {
+    var run CrawlRun
+    err := c.do(ctx, http.MethodGet, "/v1/admin/crawls/"+pathID(id), nil, nil, &run)
+    return &run, err
+}
+
+// UpstreamCacheStats returns the upstream cache hit and miss counts per endpoint; requires Admin
+func (c *Client) UpstreamCacheStats(ctx context.Context) (map[string]CacheStats, error) {
+    var stats map[string]CacheStats
+    err := c.do(ctx, http.MethodGet, "/v1/admin/upstream-cache", nil, nil, &stats)
+    return stats, err
+}
@@ -0,0 +1,313 @@
+package client
+
+import (
+    "encoding/json"
+    "time"
+)
+
+// Response types mirror the JSON the API writes; TestTypesMatchModels keeps
+// them in step with the server models.
+
+// Pagination is the cursor state of a list response
+type Pagination struct {
+    NextCursor string `json:"next_cursor,omitempty"`
+    PrevCursor string `json:"prev_cursor,omitempty"`
+    Limit      int    `json:"limit"`
+    TotalCount *int64 `json:"total_count,omitempty"`
+}
+
+// List is a page of a list endpoint
+type List[T any] struct {
+    Data       []T        `json:"data"`
+    Pagination Pagination `json:"pagination"`
+}
+
+// Location is a crawled city; the API encodes it with Go field names
+type Location struct {
+    ID          string
+    CityName    string
+    Country     string
+    CountryCode string
+    Latitude    float64
+    Longitude   float64
+    CreatedAt   time.Time
+    UpdatedAt   time.Time
+}
+
+// LocationSummary indexes the stored locations and properties by country and city
+type LocationSummary struct {
+    Countries      map[string]bool     `json:"countries"`
+    Cities         map[string]bool     `json:"cities"`
+    CountryCities  map[string][]string `json:"country_cities"`
+    CityProperties map[string][]string `json:"city_properties"`
+}
+
+// Money is an amount in a currency
+type Money struct {
+    Amount   float64 `json:"amount"`
+    Currency string  `json:"currency"`
+}
+
+// Property is a stored listing
+type Property struct {
+    ID           uint       `json:"ID"`
+    CreatedAt    time.Time  `json:"CreatedAt"`
+    UpdatedAt    time.Time  `json:"UpdatedAt"`
+    DeletedAt    *time.Time `json:"DeletedAt"`
+    DestID       string     `json:"dest_id"`
+    Name         string     `json:"name"`
+    CityID       string     `json:"city_id"`
+    CityName     string     `json:"city_name"`
+    Country      string     `json:"country"`
+    Type         string     `json:"type"`
+    Bedrooms     int        `json:"bedrooms"`
+    Bathrooms    int        `json:"bathrooms"`
+    Rating       float64    `json:"rating"`
+    Price        float64    `json:"price"`
+    Currency     string     `json:"currency"`
+    DisplayPrice *Money     `json:"display_price,omitempty"`
+}
+
+// FacetCount is the number of matching properties with one value of a facet
+type FacetCount struct {
+    Value string `json:"value"`
+    Count int64  `json:"count"`
+}
+
+// PropertyFacets counts the filtered properties per type, amenity and bedroom count
+type PropertyFacets struct {
+    Types     []FacetCount `json:"types"`
+    Amenities []FacetCount `json:"amenities"`
+    Bedrooms  []FacetCount `json:"bedrooms"`
+}
+
+// PropertyList is a page of properties with the facet counts of the filtered set
+type PropertyList struct {
+    List[Property]
+    Facets PropertyFacets `json:"facets"`
+}
+
+// Facility is an amenity of a property
+type Facility struct {
+    Name string `json:"name"`
+}
+
+// PropertyDetails is the stored stay detail of a property
+type PropertyDetails struct {
+    PropertyID   string     `json:"property_id"`
+    PropertyName string     `json:"property_name"`
+    Type         string     `json:"type"`
+    Bedrooms     int        `json:"bedrooms"`
+    Bathroom     int        `json:"bathroom"`
+    Amenities    []Facility `json:"amenities"`
+    CityID       *int       `json:"city_id"`
+    Price        float64    `json:"price"`
+    Currency     string     `json:"currency"`
+    DisplayPrice *Money     `json:"display_price,omitempty"`
+}
+
+// PropertyDescription is the localized description, images and upstream
+// review summary of a property; the API encodes it with Go field names
+type PropertyDescription struct {
+    DestID      string
+    // Images holds the CategorizedImages as a JSON string
+    Images      string
+    Description string
+    Rating      float64
+    Review      string
+    ReviewCount int
+    // Highlights holds the highlight names as a JSON array string
+    Highlights           string
+    Language             string
+    ImagesFetchedAt      time.Time
+    ImagesHash           string
+    DescriptionFetchedAt time.Time
+    DescriptionHash      string
+    ReviewsFetchedAt     time.Time
+    GuestReviews         *ReviewAggregate
+}
+
+// CategorizedImages are the image URLs of a property by category
+type CategorizedImages struct {
+    PropertyBuilding []string `json:"property_building"`
+    Property         []string `json:"property"`
+    Room             []string `json:"room"`
+}
+
+// PropertyPhoto is one photo of a property with its URL per upstream size,
+// e.g. max1280x900
+type PropertyPhoto struct {
+    ID       int64             `json:"id"`
+    // Source is upstream or host
+    Source   string            `json:"source"`
+    Tag      string            `json:"tag"`
+    Position int               `json:"position"`
+    Caption  string            `json:"caption,omitempty"`
+    URLs     map[string]string `json:"urls"`
+    // ProxyURL is the path of the photo on the API's image proxy; add ?w= for
+    // a narrower variant
+    ProxyURL string            `json:"proxy_url"`
+}
+
+// PhotoCategory holds the photos of one tag in upstream order
+type PhotoCategory struct {
+    Tag    string          `json:"tag"`
+    Photos []PropertyPhoto `json:"photos"`
+}
+
+// PropertyPhotos are the photos of a property grouped by tag, with the photo
+// chosen to represent the property
+type PropertyPhotos struct {
+    DestID     string          `json:"dest_id"`
+    Cover      *PropertyPhoto  `json:"cover"`
+    Categories []PhotoCategory `json:"categories"`
+}
+
+// CategorizedImages decodes the Images field
+func (d *PropertyDescription) CategorizedImages() (*CategorizedImages, error) {
+    images := &CategorizedImages{}
+    if d.Images == "" {
+        return images, nil
+    }
+    if err := json.Unmarshal([]byte(d.Images), images); err != nil {
+        return nil, err
+    }
+    return images, nil
+}
+
+// Review is a guest review; scores are on a 1-10 scale
+type Review struct {
+    Id            int64     `json:"id"`
+    DestID        string    `json:"dest_id"`
+    AuthorName    string    `json:"author_name"`
+    StayReference string    `json:"stay_reference"`
+    Cleanliness   float64   `json:"cleanliness"`
+    Location      float64   `json:"location"`
+    Value         float64   `json:"value"`
+    Overall       float64   `json:"overall"`
+    Text          string    `json:"text"`
+    Language      string    `json:"language"`
+    Status        string    `json:"status"`
+    CreatedAt     time.Time `json:"created_at"`
+    ModeratedAt   time.Time `json:"moderated_at"`
+}
+
+// ReviewAggregate averages the approved reviews of a property
+type ReviewAggregate struct {
+    DestID       string    `json:"dest_id"`
+    ReviewCount  int       `json:"review_count"`
+    AverageScore float64   `json:"average_score"`
+    Cleanliness  float64   `json:"cleanliness"`
+    Location     float64   `json:"location"`
+    Value        float64   `json:"value"`
+    UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ReviewList is a page of reviews with the aggregate of the property
+type ReviewList struct {
+    List[Review]
+    Aggregate *ReviewAggregate `json:"aggregate"`
+}
+
+// SearchResult is one ranked full-text search hit
+type SearchResult struct {
+    Property *Property `json:"property"`
+    Rank     float64   `json:"rank"`
+    // Snippet is an excerpt with the matched terms wrapped in <mark> tags
+    Snippet string `json:"snippet"`
+}
+
+// GraphQLResponse is the result of a GraphQL query; decode Data into a type
+// shaped like the query
+type GraphQLResponse struct {
+    Data   json.RawMessage `json:"data"`
+    Errors []GraphQLError  `json:"errors,omitempty"`
+}
+
+// GraphQLError is an error of a GraphQL field
+type GraphQLError struct {
+    Message    string                 `json:"message"`
+    Path       []interface{}          `json:"path,omitempty"`
+    Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Language is a supported content language
+type Language struct {
+    Code        string    `json:"code"`
+    Name        string    `json:"name"`
+    CountryFlag string    `json:"country_flag"`
+    UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ExchangeRate converts one unit of Base into Rate units of Currency
+type ExchangeRate struct {
+    Currency  string    `json:"currency"`
+    Base      string    `json:"base"`
+    Rate      float64   `json:"rate"`
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Wishlist is a named list of properties kept by a guest
+type Wishlist struct {
+    Id         int64     `json:"id"`
+    Name       string    `json:"name"`
+    ShareToken string    `json:"share_token,omitempty"`
+    CreatedAt  time.Time `json:"created_at"`
+    UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WishlistItem is a property on a wishlist
+type WishlistItem struct {
+    DestID   string    `json:"dest_id"`
+    AddedAt  time.Time `json:"added_at"`
+    Property *Property `json:"property,omitempty"`
+}
+
+// WishlistDetail is a wishlist with its items
+type WishlistDetail struct {
+    Wishlist *Wishlist     `json:"wishlist"`
+    Items    []WishlistItem `json:"items"`
+}
+
+// Share is the share token of a wishlist and the URL it opens
+type Share struct {
+    ShareToken string `json:"share_token"`
+    ShareURL   string `json:"share_url"`
+}
+
+// SavedSearch is a named property search kept by a guest
+type SavedSearch struct {
+    Id        int64     `json:"id"`
+    Name      string    `json:"name"`
+    Query     string    `json:"query"`
+    LastRunAt time.Time `json:"last_run_at"`
+    CreatedAt time.Time `json:"created_at"`
+}
+
+// SavedSearchRun is the result of running a saved search
+type SavedSearchRun struct {
+    Search         *SavedSearch `json:"search"`
+    PreviousRunAt  *time.Time   `json:"previous_run_at"`
+    PropertyIDs    []string     `json:"property_ids"`
+    NewPropertyIDs []string     `json:"new_property_ids"`
+    NewProperties  []Property   `json:"new_properties"`
+}
+
+// CrawlRun is one run of the location crawl
+type CrawlRun struct {
+    Id          int64     `json:"id"`
+    TriggeredBy string    `json:"triggered_by"`
+    Status      string    `json:"status"`
+    Error       string    `json:"error,omitempty"`
+    StartedAt   time.Time `json:"started_at"`
+    FinishedAt  time.Time `json:"finished_at"`
+}
+
+// CacheStats counts the upstream cache lookups of one upstream endpoint
+type CacheStats struct {
+    MemoryHits   int64 `json:"memory_hits"`
+    PostgresHits int64 `json:"postgres_hits"`
+    StaleHits    int64 `json:"stale_hits"`
+    Misses       int64 `json:"misses"`
+    Errors       int64 `json:"errors"`
+}
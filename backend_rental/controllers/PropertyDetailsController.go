@@ -1,29 +1,25 @@
 package controllers
 
 import (
-	"encoding/json"
-	"net/http"
+	"strings"
+	"backend_rental/middleware"
 	"backend_rental/services"
 	// "backend_rental/models"
-
-	beego "github.com/beego/beego/v2/server/web"
 )
 
 type PropertyDetailsController struct {
-	beego.Controller
+	BaseController
 }
 func (c *PropertyDetailsController) GetPropertyDetails() {
-	var request struct {
-		PropertyIDs []string `json:"property_ids"`
-	}
-
-	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &request); err != nil {
-		c.CustomAbort(http.StatusBadRequest, "Invalid request payload")
+	var request PropertyDetailsRequest
+	if !c.BindRequest(&request) {
 		return
 	}
 
-	if len(request.PropertyIDs) == 0 {
-		c.CustomAbort(http.StatusBadRequest, "No property IDs provided")
+	currency := strings.ToUpper(request.Currency)
+	currencyService := services.NewCurrencyService()
+	if currency != "" && !currencyService.IsSupportedCurrency(currency) {
+		c.RespondValidationError("unsupported currency: %s", currency)
 		return
 	}
 
@@ -31,10 +27,21 @@ func (c *PropertyDetailsController) GetPropertyDetails() {
 	service := services.NewPropertyDetailsService()
 	propertyDetails, err := service.FetchStoredPropertyDetails(request.PropertyIDs)
 	if err != nil {
-		c.CustomAbort(http.StatusInternalServerError, err.Error())
+		c.RespondError(err)
+		return
+	}
+
+	if err := service.LocalizeAmenities(propertyDetails, middleware.Languages(c.Ctx)); err != nil {
+		c.RespondError(err)
 		return
 	}
 
+	if currency != "" {
+		for _, detail := range propertyDetails {
+			detail.DisplayPrice = currencyService.DisplayPrice(detail.PropertyID, detail.Price, detail.Currency, currency)
+		}
+	}
+
 	c.Data["json"] = propertyDetails
 	c.ServeJSON()
 }
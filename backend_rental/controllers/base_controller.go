@@ -0,0 +1,94 @@
+package controllers
+
+import (
+    "encoding/json"
+    "net/http"
+    "time"
+
+    "backend_rental/middleware"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/binding"
+    "backend_rental/utils/httpcache"
+    "backend_rental/utils/pagination"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+// BaseController gives API controllers the shared problem+json error response
+type BaseController struct {
+    beego.Controller
+}
+
+// RespondError answers with err as a problem+json body, its status taken from the error kind
+func (c *BaseController) RespondError(err error) {
+    middleware.WriteProblem(c.Ctx, err)
+}
+
+// RespondValidationError answers 400 with a client-facing message
+func (c *BaseController) RespondValidationError(format string, args ...interface{}) {
+    c.RespondError(apperrors.Validation(format, args...))
+}
+
+// BindRequest fills and validates a request struct from the query string, route
+// parameters and JSON body, answering 400 with field errors when it is invalid
+func (c *BaseController) BindRequest(dst interface{}) bool {
+    if err := binding.Bind(c.Ctx, dst); err != nil {
+        c.RespondError(err)
+        return false
+    }
+    return true
+}
+
+// PageParams decodes the cursor of a bound pagination query, answering 400 when it is invalid
+func (c *BaseController) PageParams(query pagination.Query) (pagination.Params, bool) {
+    params, err := query.Params()
+    if err != nil {
+        c.RespondError(err)
+        return params, false
+    }
+    return params, true
+}
+
+// RequireAdmin answers 401 unless the request carries the admin token, for
+// admin-only options of public endpoints
+func (c *BaseController) RequireAdmin(option string) bool {
+    if middleware.IsAdmin(c.Ctx) {
+        return true
+    }
+    c.RespondError(apperrors.Unauthorized("%s requires the admin token", option))
+    return false
+}
+
+// Deprecated marks the response as served by a deprecated endpoint and links its replacement
+func (c *BaseController) Deprecated(successor string) {
+    c.Ctx.Output.Header("Deprecation", "true")
+    c.Ctx.Output.Header("Link", "<"+successor+">; rel=\"successor-version\"")
+}
+
+// ServeCachedJSON answers with data as JSON under an ETag of its content and
+// the Cache-Control of policy, or 304 when the request already holds it.
+// lastModified is optional; pass the zero time when it is not known.
+func (c *BaseController) ServeCachedJSON(data interface{}, lastModified time.Time, policy httpcache.Policy) {
+    body, err := json.Marshal(data)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+    c.ServeCached(body, "application/json; charset=utf-8", lastModified, policy)
+}
+
+// ServeCached answers with body like ServeCachedJSON, for any content type
+func (c *BaseController) ServeCached(body []byte, contentType string, lastModified time.Time, policy httpcache.Policy) {
+    etag := httpcache.ETag(body)
+    c.Ctx.Output.Header("ETag", etag)
+    c.Ctx.Output.Header("Cache-Control", policy.Header())
+    if !lastModified.IsZero() {
+        c.Ctx.Output.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+    }
+
+    if httpcache.NotModified(c.Ctx.Request, etag, lastModified) {
+        c.Ctx.ResponseWriter.WriteHeader(http.StatusNotModified)
+        return
+    }
+    c.Ctx.Output.Header("Content-Type", contentType)
+    c.Ctx.Output.Body(body)
+}
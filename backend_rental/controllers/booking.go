@@ -1,196 +1,97 @@
 package controllers
 
 import (
-    beego "github.com/beego/beego/v2/server/web"
-    "context"
-    "encoding/json"
-    "fmt"
-    "log"
-    "sync"
-
-    "backend_rental/models"
+    "net/url"
+
+    "backend_rental/middleware"
     "backend_rental/services"
-    "backend_rental/utils"
-    "backend_rental/utils/apiclient"
+    "backend_rental/utils/pagination"
 )
 
+// BookingController serves the deprecated action-based /v1/booking endpoints.
+// Each action is an alias of a /v1/locations or crawl route and its responses
+// carry a Deprecation header linking the replacement.
 type BookingController struct {
-    beego.Controller
-    uniqueCountries map[string]bool
-    uniqueCities    map[string]bool
-    countryCities   map[string][]string
-    cityProperties  map[string][]string
-    mutex           sync.Mutex
-    rapidAPIKey     string
-    fetchedCities   []models.City
-    apiClient       *apiclient.APIClient
-    locationService *services.LocationService
+    BaseController
+    locationService services.LocationService
 }
 
-func NewBookingController() *BookingController {
-    // Get RapidAPI key from app.conf
-    rapidAPIKey, _ := beego.AppConfig.String("rapidapikey")
-    if rapidAPIKey == "" {
-        log.Fatal("rapidapikey is not set in app.conf")
-    }
-
-    return &BookingController{
-        uniqueCountries: make(map[string]bool),
-        uniqueCities:    make(map[string]bool),
-        countryCities:   make(map[string][]string),
-        cityProperties:  make(map[string][]string),
-        rapidAPIKey:     rapidAPIKey,
-        apiClient:       apiclient.NewAPIClient(rapidAPIKey),
-        locationService: &services.LocationService{},
+// Get handles GET requests to /v1/booking?action=summary|process|city|list
+func (c *BookingController) Get() {
+    var request BookingRequest
+    if !c.BindRequest(&request) {
+        return
     }
-}
 
-func (c *BookingController) Prepare() {
-    // Optional: Add any pre-request preparation logic
-}
-
-func init() {
-    // Automated city processing on application startup
-    go func() {
-        controller := NewBookingController()
-        err := controller.processAllCities()
-        if err != nil {
-            log.Printf("Automated city processing error: %v", err)
-        }
-    }()
-}
+    switch request.Action {
+    case "summary":
+        c.Summary()
 
-func (c *BookingController) Get() {
-    action := c.GetString("action")
-    
-    var result interface{}
-    var err error
+    case "process":
+        c.Process()
 
-    switch action {
-    case "summary":
-        result, err = c.getSummary()
-        if err != nil {
-            c.handleError(err)
+    case "city":
+        if request.CityID == "" {
+            c.RespondValidationError("id is required for the city action")
             return
         }
+        c.Deprecated("/v1/locations/" + url.PathEscape(request.CityID))
 
-    case "process":
-        err = c.processAllCities()
+        location, err := c.locationService.GetLocalizedLocation(c.Ctx.Request.Context(), request.CityID, middleware.Languages(c.Ctx))
         if err != nil {
-            c.handleError(err)
+            c.RespondError(err)
             return
         }
-        result = map[string]string{"status": "success"}
+        c.Data["json"] = location
+        c.ServeJSON()
 
-    case "city":
-        cityID := c.GetString("id")
-        if cityID == "" {
-            c.handleError(fmt.Errorf("city ID is required"))
+    case "list":
+        c.Deprecated("/v1/locations")
+
+        params, ok := c.PageParams(request.Query)
+        if !ok {
             return
         }
-        
-        result, err = c.getCityDetails(cityID)
+        locations, page, err := c.locationService.GetLocations(c.Ctx.Request.Context(), params, request.Country, request.CityName)
         if err != nil {
-            c.handleError(err)
+            c.RespondError(err)
             return
         }
-
-    case "list":
-        // Get pagination parameters
-        page, _ := c.GetInt("page", 1)
-        pageSize, _ := c.GetInt("page_size", 20)
-        country := c.GetString("country")
-        cityName := c.GetString("city_name")
-
-        result, err = c.listLocations(page, pageSize, country, cityName)
+        locations, err = c.locationService.LocalizeLocations(c.Ctx.Request.Context(), locations, middleware.Languages(c.Ctx))
         if err != nil {
-            c.handleError(err)
+            c.RespondError(err)
             return
         }
-
-    default:
-        c.handleError(fmt.Errorf("invalid action: %s", action))
-        return
+        c.Data["json"] = pagination.Envelope{Data: locations, Pagination: page}
+        c.ServeJSON()
     }
-
-    c.Data["json"] = result
-    c.ServeJSON()
 }
 
-func (c *BookingController) getSummary() (*models.BookingSummary, error) {
-    countryCities, err := c.locationService.GetUniqueCountriesAndCities()
-    if err != nil {
-        return nil, err
-    }
-    
-    return &models.BookingSummary{
-        Countries:      utils.ConvertToCountryMap(countryCities),
-        Cities:         utils.ConvertToCityMap(countryCities),
-        CountryCities:  countryCities,
-        CityProperties: c.cityProperties,
-    }, nil
-}
-
-func (c *BookingController) getCityDetails(cityID string) (*models.Location, error) {
-    return c.locationService.GetLocationByID(cityID)
-}
+// Summary handles GET requests to /v1/booking/summary
+func (c *BookingController) Summary() {
+    c.Deprecated("/v1/locations/summary")
 
-func (c *BookingController) listLocations(
-    page, 
-    pageSize int, 
-    country, 
-    cityName string,
-) (map[string]interface{}, error) {
-    locations, totalCount, err := c.locationService.GetLocations(page, pageSize, country, cityName)
+    summary, err := c.locationService.Summary(c.Ctx.Request.Context(), middleware.Languages(c.Ctx))
     if err != nil {
-        return nil, err
+        c.RespondError(err)
+        return
     }
 
-    return map[string]interface{}{
-        "locations": locations,
-        "pagination": map[string]interface{}{
-            "total_count": totalCount,
-            "page":        page,
-            "page_size":   pageSize,
-        },
-    }, nil
-}
-
-func (c *BookingController) processAllCities() error {
-    apiClient := apiclient.NewAPIClient(c.rapidAPIKey)
-    locationProcessingService := services.NewLocationProcessingService(apiClient)
-    queries := utils.GenerateLocationQueries()
-    return locationProcessingService.ProcessLocationsFromQueries(queries)
+    c.Data["json"] = summary
+    c.ServeJSON()
 }
 
-func (c *BookingController) fetchCities(query string) ([]models.City, error) {
-    apiURL := fmt.Sprintf("https://booking-com18.p.rapidapi.com/stays/auto-complete?query=%s", query)
-    
-    // Make API request using the APIClient which already handles rate limiting
-    body, err := c.apiClient.MakeRequest(context.Background(), apiURL)
-    if err != nil {
-        return nil, err
-    }
-
-    // Parse response
-    var citiesResp models.CityResponse
-    if err := json.Unmarshal(body, &citiesResp); err != nil {
-        return nil, err
-    }
-
-    return citiesResp.Data, nil
-}
-  
-func (c *BookingController) handleError(err error) {
-    log.Printf("Controller error: %v", err)
-    
-    c.Data["json"] = map[string]string{
-        "error":   err.Error(),
-        "message": "An error occurred while processing your request",
+// Process handles GET requests to /v1/booking/process; it starts a crawl
+// like POST /v1/admin/crawls instead of crawling within the request, and
+// requires the admin token as that route does
+func (c *BookingController) Process() {
+    c.Deprecated("/v1/admin/crawls")
+    if !c.RequireAdmin("process") {
+        return
     }
-    c.Ctx.Output.SetStatus(400)
-    c.ServeJSON()
+    c.startCrawl(services.CrawlTriggerLegacyAPI)
 }
+
 // package controllers
 
 // import (
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+    "fmt"
+    "net/http"
+
+    "backend_rental/services"
+)
+
+// CrawlController starts and reports the upstream location crawls
+type CrawlController struct {
+    BaseController
+}
+
+// Start handles POST requests to /v1/admin/crawls; the crawl runs in the
+// background and the response points at its run record
+func (c *CrawlController) Start() {
+    c.startCrawl(services.CrawlTriggerAdmin)
+}
+
+// startCrawl starts a background crawl and answers 202 with its run record
+func (c *BaseController) startCrawl(triggeredBy string) {
+    run, err := services.NewCrawlService().Start(triggeredBy)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Ctx.Output.Header("Location", fmt.Sprintf("/v1/admin/crawls/%d", run.Id))
+    c.Ctx.Output.SetStatus(http.StatusAccepted)
+    c.Data["json"] = run
+    c.ServeJSON()
+}
+
+// Get handles GET requests to /v1/admin/crawls/:id
+func (c *CrawlController) Get() {
+    var request CrawlRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    run, err := services.NewCrawlService().GetRun(request.ID)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = run
+    c.ServeJSON()
+}
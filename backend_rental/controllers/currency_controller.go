@@ -0,0 +1,39 @@
+package controllers
+
+import (
+    "backend_rental/services"
+)
+
+// CurrencyController exposes the FX rate table used for price presentation
+type CurrencyController struct {
+    BaseController
+}
+
+// GetRates handles GET requests to /v1/currencies
+func (c *CurrencyController) GetRates() {
+    rates, err := services.NewCurrencyService().ListRates()
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = rates
+    c.ServeJSON()
+}
+
+// UpdateRates handles PUT requests to /v1/admin/fx-rates and replaces the whole rate table
+func (c *CurrencyController) UpdateRates() {
+    var request UpdateRatesRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    currencyService := services.NewCurrencyService()
+    rates := services.FXRates{Base: request.Base, Rates: request.Rates}
+    if err := currencyService.ReplaceRates(rates); err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.GetRates()
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+    "errors"
+
+    "backend_rental/middleware"
+    "backend_rental/utils/apperrors"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+// ErrorController renders the framework's own errors (unknown routes,
+// panics) as problem+json like every other API error
+type ErrorController struct {
+    beego.Controller
+}
+
+func (c *ErrorController) Error404() {
+    c.respond(apperrors.NotFound("no route matches %s %s", c.Ctx.Request.Method, c.Ctx.Request.URL.Path))
+}
+
+func (c *ErrorController) Error500() {
+    c.respond(errors.New("unhandled server error"))
+}
+
+func (c *ErrorController) respond(err error) {
+    c.EnableRender = false
+    middleware.WriteProblem(c.Ctx, err)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+    "net/http"
+    "sync"
+
+    "backend_rental/graph"
+    "backend_rental/middleware"
+    "backend_rental/services"
+    "backend_rental/utils"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+var (
+    graphqlOnce     sync.Once
+    graphqlExecutor *graph.Executor
+    graphqlErr      error
+)
+
+// GraphQLController serves the GraphQL API over locations and properties
+type GraphQLController struct {
+    BaseController
+}
+
+// Query handles POST requests to /v1/graphql. Requests that fail to parse,
+// validate or stay within the cost limits are answered 400; once a query
+// runs the response is 200 with any field errors listed next to the data.
+func (c *GraphQLController) Query() {
+    var request GraphQLRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    graphqlOnce.Do(func() {
+        rapidAPIKey, _ := beego.AppConfig.String("rapidapikey")
+        graphqlExecutor, graphqlErr = graph.NewExecutor(services.NewPropertyService(utils.GetDB(), rapidAPIKey), graph.LimitsFromConfig())
+    })
+    if graphqlErr != nil {
+        c.RespondError(graphqlErr)
+        return
+    }
+
+    result, ok := graphqlExecutor.Execute(c.Ctx.Request.Context(), graph.Request{
+        Query:         request.Query,
+        OperationName: request.OperationName,
+        Variables:     request.Variables,
+    }, middleware.Languages(c.Ctx), middleware.RequestID(c.Ctx))
+    if !ok {
+        c.Ctx.Output.SetStatus(http.StatusBadRequest)
+    }
+
+    c.Data["json"] = result
+    c.ServeJSON()
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+    "log/slog"
+    "net/http"
+
+    "backend_rental/services"
+)
+
+// HealthController answers the liveness and readiness probes and the admin
+// dependency status
+type HealthController struct {
+    BaseController
+}
+
+// Live handles GET requests to /healthz; it answers as long as the process
+// serves HTTP and checks no dependency, so a database outage does not get the
+// service restarted
+func (c *HealthController) Live() {
+    c.Data["json"] = StatusResponse{Status: "ok"}
+    c.ServeJSON()
+}
+
+// Ready handles GET requests to /readyz, answering 503 while a check fails so
+// the service is taken out of rotation. The probe is unauthenticated, so the
+// check errors are logged and shown on /v1/admin/status instead.
+func (c *HealthController) Ready() {
+    readiness := services.NewHealthService().Readiness(c.Ctx.Request.Context())
+    if !readiness.Ready {
+        for name, check := range readiness.Checks {
+            if check.Status == services.CheckFailed {
+                slog.WarnContext(c.Ctx.Request.Context(), "readiness check failed", "check", name, "error", check.Error)
+            }
+        }
+        c.Ctx.Output.SetStatus(http.StatusServiceUnavailable)
+    }
+    c.Data["json"] = readiness.WithoutErrors()
+    c.ServeJSON()
+}
+
+// Status handles GET requests to /v1/admin/status with the upstream error
+// rates and quota, the last runs of the background tasks and the queue depth
+func (c *HealthController) Status() {
+    status, err := services.NewHealthService().Status(c.Ctx.Request.Context())
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+    c.Data["json"] = status
+    c.ServeJSON()
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+    "io"
+    "net/http"
+
+    "backend_rental/middleware"
+    "backend_rental/services"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/binding"
+)
+
+// HostPhotoController manages the photos hosts upload for their listings,
+// identified by X-Host-ID; the admin token manages every host's photos
+type HostPhotoController struct {
+    BaseController
+    hostID string
+}
+
+func (c *HostPhotoController) Prepare() {
+    c.hostID = middleware.HostID(c.Ctx)
+}
+
+// UploadPhotos handles multipart POST requests to /v1/property/photos
+func (c *HostPhotoController) UploadPhotos() {
+    if !c.requireHost() {
+        return
+    }
+
+    // beego has parsed the multipart form, so its fields are in Request.Form
+    var request PhotoUploadRequest
+    if err := binding.BindValues(c.Ctx.Request.Form, &request); err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    files, err := c.GetFiles("photo")
+    if err != nil {
+        c.RespondValidationError("photo: at least one file is required")
+        return
+    }
+    uploads := make([]services.PhotoUpload, 0, len(files))
+    for _, file := range files {
+        f, err := file.Open()
+        if err != nil {
+            c.RespondError(err)
+            return
+        }
+        data, err := io.ReadAll(f)
+        f.Close()
+        if err != nil {
+            c.RespondError(err)
+            return
+        }
+        uploads = append(uploads, services.PhotoUpload{Filename: file.Filename, Data: data})
+    }
+
+    service, err := services.NewHostPhotoService()
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+    photos, err := service.Upload(c.Ctx.Request.Context(), c.owner(), request.DestID, request.Tag, request.Caption, uploads)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Ctx.Output.SetStatus(http.StatusCreated)
+    c.Data["json"] = photos
+    c.ServeJSON()
+}
+
+// UpdatePhoto handles PUT requests to /v1/property/photos/:photo_id
+func (c *HostPhotoController) UpdatePhoto() {
+    if !c.requireHost() {
+        return
+    }
+
+    var request UpdatePhotoRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    service, err := services.NewHostPhotoService()
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+    photo, err := service.Update(c.owner(), request.PhotoID, request.Tag, request.Caption)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = photo
+    c.ServeJSON()
+}
+
+// ReorderPhotos handles PUT requests to /v1/property/photos/order
+func (c *HostPhotoController) ReorderPhotos() {
+    if !c.requireHost() {
+        return
+    }
+
+    var request ReorderPhotosRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    service, err := services.NewHostPhotoService()
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+    photos, err := service.Reorder(c.owner(), request.DestID, request.PhotoIDs)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = photos
+    c.ServeJSON()
+}
+
+// DeletePhoto handles DELETE requests to /v1/property/photos/:photo_id
+func (c *HostPhotoController) DeletePhoto() {
+    if !c.requireHost() {
+        return
+    }
+
+    var request HostPhotoRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    service, err := services.NewHostPhotoService()
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+    if err := service.Delete(c.Ctx.Request.Context(), c.owner(), request.PhotoID); err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = StatusResponse{Status: "deleted"}
+    c.ServeJSON()
+}
+
+func (c *HostPhotoController) requireHost() bool {
+    if c.hostID == "" && !middleware.IsAdmin(c.Ctx) {
+        c.RespondError(apperrors.Unauthorized("X-Host-ID header is required"))
+        return false
+    }
+    return true
+}
+
+// owner is the host whose photos the request manages; empty for an admin
+func (c *HostPhotoController) owner() string {
+    if middleware.IsAdmin(c.Ctx) {
+        return ""
+    }
+    return c.hostID
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+    "strings"
+    "time"
+
+    "backend_rental/services"
+    "backend_rental/utils/httpcache"
+    "backend_rental/utils/imageproc"
+)
+
+// ImageProxyController serves property photos from the blob store
+type ImageProxyController struct {
+    BaseController
+}
+
+// proxiedImageCache lets browsers and CDNs keep a variant for a year; the
+// bytes behind a photo ID and variant never change
+var proxiedImageCache = httpcache.Policy{MaxAge: 365 * 24 * time.Hour, Immutable: true}
+
+// Get handles GET requests to /img/:photo_id
+func (c *ImageProxyController) Get() {
+    var request ImageProxyRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    proxy, err := services.DefaultImageProxy()
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    format := request.Format
+    if format == "" {
+        // The response depends on Accept only when the format is negotiated
+        c.Ctx.Output.Header("Vary", "Accept")
+        if imageproc.WebPSupported() && strings.Contains(c.Ctx.Input.Header("Accept"), "image/webp") {
+            format = imageproc.WebP
+        }
+    }
+
+    image, err := proxy.Get(c.Ctx.Request.Context(), request.PhotoID, request.Width, format)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+    c.ServeCached(image.Body, image.ContentType, image.ModTime, proxiedImageCache)
+}
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+    "backend_rental/services"
+)
+
+// LanguageController lists the content languages supported upstream
+type LanguageController struct {
+    BaseController
+}
+
+// GetLanguages handles GET requests to /v1/languages
+func (c *LanguageController) GetLanguages() {
+    languages, err := services.NewLanguageService().ListLanguages()
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = languages
+    c.ServeJSON()
+}
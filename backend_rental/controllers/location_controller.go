@@ -2,50 +2,80 @@
 package controllers
 
 import (
-    "log"
+    "log/slog"
+    "time"
+    "backend_rental/middleware"
     "backend_rental/models"
     "github.com/beego/beego/v2/client/orm"
 
     "backend_rental/services"
-    beego "github.com/beego/beego/v2/server/web"
+    "backend_rental/utils/httpcache"
+    "backend_rental/utils/pagination"
 )
 
 type LocationController struct {
-    beego.Controller
+    BaseController
 }
 
+// List handles GET requests to /v1/locations
 func (c *LocationController) List() {
-    // Pagination parameters
-    page, _ := c.GetInt("page", 1)
-	pageSize, _ := c.GetInt("page_size", 20)
-
-
-    // Optional filtering parameters
-    country := c.GetString("country")
-    cityName := c.GetString("city_name")
+    // Cursor pagination and optional filtering parameters
+    var request LocationListRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+    params, ok := c.PageParams(request.Query)
+    if !ok {
+        return
+    }
 
     locationService := &services.LocationService{}
 
     // Fetch locations with optional filtering
-    locations, totalCount, err := locationService.GetLocations(page, pageSize, country, cityName)
+    locations, page, err := locationService.GetLocations(c.Ctx.Request.Context(), params, request.Country, request.CityName)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    locations, err = locationService.LocalizeLocations(c.Ctx.Request.Context(), locations, middleware.Languages(c.Ctx))
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = pagination.Envelope{Data: locations, Pagination: page}
+    c.ServeJSON()
+}
+
+// Get handles GET requests to /v1/locations/:id
+func (c *LocationController) Get() {
+    var request LocationRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    locationService := &services.LocationService{}
+    location, err := locationService.GetLocalizedLocation(c.Ctx.Request.Context(), request.ID, middleware.Languages(c.Ctx))
     if err != nil {
-        c.Data["json"] = map[string]string{"error": err.Error()}
-        c.Ctx.Output.SetStatus(500)
-        c.ServeJSON()
+        c.RespondError(err)
         return
     }
 
-    // Prepare response
-    response := map[string]interface{}{
-        "locations": locations,
-        "pagination": map[string]interface{}{
-            "total_count": totalCount,
-            "page":        page,
-            "page_size":   pageSize,
-        },
+    c.Data["json"] = location
+    c.ServeJSON()
+}
+
+// Summary handles GET requests to /v1/locations/summary
+func (c *LocationController) Summary() {
+    locationService := &services.LocationService{}
+    summary, err := locationService.Summary(c.Ctx.Request.Context(), middleware.Languages(c.Ctx))
+    if err != nil {
+        c.RespondError(err)
+        return
     }
 
-    c.Data["json"] = response
+    c.Data["json"] = summary
     c.ServeJSON()
 }
 
@@ -53,31 +83,35 @@ func (c *LocationController) List() {
 func (c *LocationController) GetCountriesAndCities() {
     locationService := &services.LocationService{}
 
-    countryCities, err := locationService.GetUniqueCountriesAndCities()
+    countryCities, err := locationService.GetUniqueCountriesAndCities(c.Ctx.Request.Context(), middleware.Languages(c.Ctx))
     if err != nil {
-        c.Data["json"] = map[string]string{"error": err.Error()}
-        c.Ctx.Output.SetStatus(500)
-        c.ServeJSON()
+        c.RespondError(err)
         return
     }
 
-    c.Data["json"] = countryCities
-    c.ServeJSON()
+    lastModified, err := locationService.LastModified(c.Ctx.Request.Context())
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.ServeCachedJSON(countryCities, lastModified, countriesCitiesCache)
 }
 
+// countriesCitiesCache covers the catalogue of destinations, which only
+// changes when a new city is crawled
+var countriesCitiesCache = httpcache.Policy{MaxAge: time.Hour, StaleWhileRevalidate: 24 * time.Hour}
+
 func (c *LocationController) Debug() {
     o := orm.NewOrm()
     var locations []models.Location
     
     _, err := o.QueryTable(new(models.Location)).All(&locations)
     if err != nil {
-        log.Printf("Error fetching locations: %v", err)
-        c.Data["json"] = map[string]string{"error": err.Error()}
+        c.RespondError(err)
+        return
     } else {
-        log.Printf("Found %d locations in database", len(locations))
-        for i, loc := range locations {
-            log.Printf("Location %d: %s, %s, %s", i+1, loc.CityName, loc.Country, loc.ID)
-        }
+        slog.DebugContext(c.Ctx.Request.Context(), "listed stored locations", "count", len(locations))
         c.Data["json"] = locations
     }
     c.ServeJSON()
@@ -1,15 +1,19 @@
 package controllers
 
 import (
-    "strconv"
+    "strings"
+    "time"
+    "backend_rental/models"
     "backend_rental/services"
     "backend_rental/utils"
+    "backend_rental/utils/httpcache"
+    "backend_rental/utils/pagination"
     beego "github.com/beego/beego/v2/server/web"
 )
 
 // PropertyController handles property-related endpoints
 type PropertyController struct {
-    beego.Controller
+    BaseController
     propertyService services.PropertyServiceInterface
 }
 
@@ -24,42 +28,41 @@ func NewPropertyController() *PropertyController {
     }
 }
 
-// GetProperties handles fetching properties
-// @router /fetch [get]
+// Prepare wires the property service for router-created controller instances
+func (c *PropertyController) Prepare() {
+    if c.propertyService == nil {
+        rapidAPIKey, _ := beego.AppConfig.String("rapidapikey")
+        c.propertyService = services.NewPropertyService(utils.GetDB(), rapidAPIKey)
+    }
+}
+
+// GetProperties pages all properties; it is not routed
 func (c *PropertyController) GetProperties() {
-    page, _ := strconv.Atoi(c.GetString("page", "1"))
-    pageSize, _ := strconv.Atoi(c.GetString("pageSize", "10"))
+    params, ok := c.paginationParams()
+    if !ok {
+        return
+    }
 
-    properties, total, err := c.propertyService.ListProperties(page, pageSize)
+    properties, page, err := c.propertyService.ListProperties(c.Ctx.Request.Context(), models.PropertyFilter{}, "", params)
     if err != nil {
-        c.Data["json"] = map[string]interface{}{
-            "error": err.Error(),
-        }
-        c.ServeJSON()
+        c.RespondError(err)
         return
     }
 
-    c.Data["json"] = map[string]interface{}{
-        "data": properties,
-        "total": total,
-        "page": page,
-        "pageSize": pageSize,
-    }
+    c.Data["json"] = pagination.Envelope{Data: properties, Pagination: page}
     c.ServeJSON()
 }
 
-// GetPropertiesSummary handles the summary endpoint
-// @router /summary [get]
+// GetPropertiesSummary counts a page of properties per city; it is not routed
 func (c *PropertyController) GetPropertiesSummary() {
-    page, _ := strconv.Atoi(c.GetString("page", "1"))
-    pageSize, _ := strconv.Atoi(c.GetString("pageSize", "10"))
+    params, ok := c.paginationParams()
+    if !ok {
+        return
+    }
 
-    properties, _, err := c.propertyService.ListProperties(page, pageSize)
+    properties, _, err := c.propertyService.ListProperties(c.Ctx.Request.Context(), models.PropertyFilter{}, "", params)
     if err != nil {
-        c.Data["json"] = map[string]interface{}{
-            "error": err.Error(),
-        }
-        c.ServeJSON()
+        c.RespondError(err)
         return
     }
 
@@ -77,18 +80,16 @@ func (c *PropertyController) GetPropertiesSummary() {
     c.ServeJSON()
 }
 
-// ProcessAllProperties handles batch processing of properties
-// @router /process-all [get]
+// ProcessAllProperties counts a page of properties; it is not routed
 func (c *PropertyController) ProcessAllProperties() {
-    page, _ := strconv.Atoi(c.GetString("page", "1"))
-    pageSize, _ := strconv.Atoi(c.GetString("pageSize", "10"))
+    params, ok := c.paginationParams()
+    if !ok {
+        return
+    }
 
-    properties, _, err := c.propertyService.ListProperties(page, pageSize)
+    properties, _, err := c.propertyService.ListProperties(c.Ctx.Request.Context(), models.PropertyFilter{}, "", params)
     if err != nil {
-        c.Data["json"] = map[string]interface{}{
-            "error": err.Error(),
-        }
-        c.ServeJSON()
+        c.RespondError(err)
         return
     }
 
@@ -109,25 +110,68 @@ func (c *PropertyController) ProcessAllProperties() {
 
 
 
-// @router /v1/property/list [get]
+// ListProperties handles GET requests to /v1/property/list; it accepts the
+// PropertyFilter query parameters and a sort key, and returns facet counts
+// for the filtered set
 func (c *PropertyController) ListProperties() {
-    page, _ := strconv.Atoi(c.GetString("page", "1"))
-    pageSize, _ := strconv.Atoi(c.GetString("pageSize", "10"))
+    var req ListPropertiesRequest
+    if !c.BindRequest(&req) {
+        return
+    }
+    params, ok := c.PageParams(req.Query)
+    if !ok {
+        return
+    }
+    currency := strings.ToUpper(req.Currency)
+
+    currencyService := services.NewCurrencyService()
+    if currency != "" && !currencyService.IsSupportedCurrency(currency) {
+        c.RespondValidationError("unsupported currency: %s", currency)
+        return
+    }
     
-    properties, total, err := c.propertyService.ListProperties(page, pageSize)
+    properties, page, err := c.propertyService.ListProperties(c.Ctx.Request.Context(), req.PropertyFilter, req.Sort, params)
     if err != nil {
-        c.Data["json"] = map[string]interface{}{
-            "error": err.Error(),
-        }
-        c.ServeJSON()
+        c.RespondError(err)
+        return
+    }
+
+    facets, err := c.propertyService.GetFacets(c.Ctx.Request.Context(), req.PropertyFilter)
+    if err != nil {
+        c.RespondError(err)
         return
     }
+
+    lastModified, err := c.propertyService.LastModified(c.Ctx.Request.Context(), req.PropertyFilter)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    if currency != "" {
+        if err := currencyService.ApplyDisplayPrices(properties, currency); err != nil {
+            c.RespondError(err)
+            return
+        }
+        lastModified = httpcache.Latest(lastModified, currencyService.RatesUpdatedAt())
+    }
     
-    c.Data["json"] = map[string]interface{}{
-        "data": properties,
-        "total": total,
-        "page": page,
-        "pageSize": pageSize,
+    c.ServeCachedJSON(map[string]interface{}{
+        "data":       properties,
+        "pagination": page,
+        "facets":     facets,
+    }, lastModified, listPropertiesCache)
+}
+
+// listPropertiesCache lets caches reuse a listing briefly; prices and
+// availability move with every crawl
+var listPropertiesCache = httpcache.Policy{MaxAge: time.Minute, StaleWhileRevalidate: 10 * time.Minute}
+
+// paginationParams binds the cursor pagination parameters, answering 400 when they are invalid
+func (c *PropertyController) paginationParams() (pagination.Params, bool) {
+    var query pagination.Query
+    if !c.BindRequest(&query) {
+        return pagination.Params{}, false
     }
-    c.ServeJSON()
+    return c.PageParams(query)
 }
@@ -1,12 +1,13 @@
 package controllers
 
 import (
+    "backend_rental/middleware"
     "backend_rental/services"
-    beego "github.com/beego/beego/v2/server/web"
+    "backend_rental/utils/apperrors"
 )
 
 type PropertyDescriptionController struct {
-    beego.Controller
+    BaseController
     propDescService *services.PropDescService
 }
 
@@ -14,34 +15,43 @@ func (c *PropertyDescriptionController) Prepare() {
     c.propDescService = services.NewPropDescService()
 }
 
-// @router /v1/property/details [get]
+// GetPropertyDescription handles GET requests to /v1/property/description
 func (c *PropertyDescriptionController) GetPropertyDescription() {
-    destID := c.GetString("dest_id")
-    if destID == "" {
-        c.Data["json"] = map[string]interface{}{
-            "error": "dest_id is required",
-        }
-        c.ServeJSON()
+    var request PropertyContentRequest
+    if !c.BindRequest(&request) {
         return
     }
+    destID := request.DestID
 
-    details, err := c.propDescService.GetPropertyDescription(destID)
-    if err != nil {
-        c.Data["json"] = map[string]interface{}{
-            "error": err.Error(),
+    if request.Refresh {
+        if !c.RequireAdmin("refresh") {
+            return
+        }
+        if _, err := c.propDescService.RefreshPropertyDescription(c.Ctx.Request.Context(), destID); err != nil {
+            c.RespondError(err)
+            return
         }
-        c.ServeJSON()
+    }
+
+    details, err := c.propDescService.GetLocalizedPropertyDescription(
+        c.Ctx.Request.Context(), destID, middleware.Languages(c.Ctx))
+    if err != nil {
+        c.RespondError(err)
         return
     }
 
     if details == nil {
-        c.Data["json"] = map[string]interface{}{
-            "error": "Property details not found",
-        }
-        c.ServeJSON()
+        c.RespondError(apperrors.NotFound("property %s not found", destID))
+        return
+    }
+
+    details.GuestReviews, err = services.NewReviewService().GetAggregate(c.Ctx.Request.Context(), destID)
+    if err != nil {
+        c.RespondError(err)
         return
     }
 
+    c.Ctx.Output.Header("Content-Language", details.Language)
     c.Data["json"] = details
     c.ServeJSON()
 }
\ No newline at end of file
@@ -3,54 +3,82 @@ package controllers
 
 import (
     "fmt"
-    beego "github.com/beego/beego/v2/server/web"
+    "time"
+    "backend_rental/middleware"
     "backend_rental/services"
+    "backend_rental/utils/httpcache"
 )
 
 type PropertyImageController struct {
-    beego.Controller
+    BaseController
 }
 
 // GetPropertyDetails handles GET requests to /v1/property/images
 func (c *PropertyImageController) GetPropertyDetails() {
-    fmt.Println("=== Starting GetPropertyDetails ===")
-    
-    destID := c.GetString("dest_id")
-    fmt.Printf("Received dest_id: %s\n", destID)
-    
-    if destID == "" {
-        fmt.Println("Error: dest_id is empty")
-        c.Data["json"] = map[string]interface{}{
-            "error": "dest_id is required",
-        }
-        c.ServeJSON()
+    var request PropertyContentRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+    if request.Refresh && !c.RequireAdmin("refresh") {
+        return
+    }
+
+    propertyService, err := services.NewPropertyImageService()
+    if err != nil {
+        c.RespondError(fmt.Errorf("failed to initialize service: %w", err))
+        return
+    }
+
+    propertyDetails, err := propertyService.GetPropertyDetails(c.Ctx.Request.Context(), request.DestID, request.Refresh)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    err = services.NewPropDescService().LocalizeDescription(
+        c.Ctx.Request.Context(), propertyDetails, middleware.Languages(c.Ctx))
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    lastModified := httpcache.Latest(propertyDetails.ImagesFetchedAt, propertyDetails.DescriptionFetchedAt,
+        propertyDetails.ReviewsFetchedAt)
+    c.ServeCachedJSON(propertyDetails, lastModified, imagesCachePolicy(request.Refresh))
+}
+
+// GetPhotos handles GET requests to /v1/property/photos
+func (c *PropertyImageController) GetPhotos() {
+    var request PropertyPhotosRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+    if request.Refresh && !c.RequireAdmin("refresh") {
         return
     }
 
-    fmt.Println("Initializing property service...")
     propertyService, err := services.NewPropertyImageService()
     if err != nil {
-        fmt.Printf("Service initialization error: %v\n", err)
-        c.Data["json"] = map[string]interface{}{
-            "error": fmt.Sprintf("failed to initialize service: %v", err),
-        }
-        c.ServeJSON()
+        c.RespondError(fmt.Errorf("failed to initialize service: %w", err))
         return
     }
 
-    fmt.Printf("Fetching property details for dest_id: %s\n", destID)
-    propertyDetails, err := propertyService.GetPropertyDetails(destID)
+    photos, err := propertyService.GetPhotos(c.Ctx.Request.Context(), request.DestID, request.Category, request.Refresh)
     if err != nil {
-        fmt.Printf("Error fetching property details: %v\n", err)
-        c.Data["json"] = map[string]interface{}{
-            "error": err.Error(),
-        }
-        c.ServeJSON()
+        c.RespondError(err)
         return
     }
 
-    fmt.Println("Successfully retrieved property details")
-    c.Data["json"] = propertyDetails
-    c.ServeJSON()
-    fmt.Println("=== Completed GetPropertyDetails ===")
+    c.ServeCachedJSON(photos, photos.LastModified, imagesCachePolicy(request.Refresh))
+}
+
+// propertyImagesCache matches the upstream photo refresh interval
+var propertyImagesCache = httpcache.Policy{MaxAge: 24 * time.Hour, StaleWhileRevalidate: 7 * 24 * time.Hour}
+
+// imagesCachePolicy keeps responses to an admin refresh out of caches
+func imagesCachePolicy(refresh bool) httpcache.Policy {
+    if refresh {
+        return httpcache.NoStore
+    }
+    return propertyImagesCache
 }
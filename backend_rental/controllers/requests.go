@@ -0,0 +1,242 @@
+package controllers
+
+import (
+    "backend_rental/models"
+    "backend_rental/utils/pagination"
+    "github.com/beego/beego/v2/core/validation"
+)
+
+// Request structs of the /v1 routes, bound and validated by BaseController.BindRequest.
+// See package binding for the tags.
+
+// DestIDRequest identifies a property by its upstream destination ID
+type DestIDRequest struct {
+    DestID string `form:"dest_id" valid:"Required;MaxSize(32)"`
+}
+
+// PropertyContentRequest identifies a property whose stored upstream content
+// an admin may have re-fetched before it is served
+type PropertyContentRequest struct {
+    DestIDRequest
+    // Refresh bypasses the stored content and the upstream cache; it requires X-Admin-Token
+    Refresh bool `form:"refresh"`
+}
+
+// PropertyPhotosRequest is the query of /v1/property/photos
+type PropertyPhotosRequest struct {
+    PropertyContentRequest
+    // Category keeps only the photos of one upstream tag, e.g. Room
+    Category string `form:"category" valid:"MaxSize(128)"`
+}
+
+// ImageProxyRequest selects a photo variant of /img/:photo_id
+type ImageProxyRequest struct {
+    PhotoID int64 `path:"photo_id" valid:"Required;Min(1)"`
+    // Width is rounded up to the nearest served width; zero keeps the original size
+    Width int `form:"w" valid:"Range(0,4096)"`
+    // Format defaults to WebP for browsers accepting it and to the original
+    // format, or JPEG for resized variants, otherwise
+    Format string `form:"format" valid:"Enum(jpeg|png|webp)"`
+}
+
+// PhotoUploadRequest holds the fields of a multipart photo upload; the files
+// are sent as one or more photo parts
+type PhotoUploadRequest struct {
+    DestIDRequest
+    Tag     string `form:"tag" valid:"MaxSize(128)"`
+    Caption string `form:"caption" valid:"MaxSize(1000)"`
+}
+
+// HostPhotoRequest identifies a photo uploaded by a host
+type HostPhotoRequest struct {
+    PhotoID int64 `path:"photo_id" valid:"Required;Min(1)"`
+}
+
+// UpdatePhotoRequest replaces the tag and caption of a host photo
+type UpdatePhotoRequest struct {
+    HostPhotoRequest
+    Tag     string `json:"tag" valid:"MaxSize(128)"`
+    Caption string `json:"caption" valid:"MaxSize(1000)"`
+}
+
+// ReorderPhotosRequest lists every host photo of a property in its new order
+type ReorderPhotosRequest struct {
+    DestID   string  `json:"dest_id" valid:"Required;MaxSize(32)"`
+    PhotoIDs []int64 `json:"photo_ids"`
+}
+
+// BookingRequest is the query of the action-based /v1/booking endpoint
+type BookingRequest struct {
+    pagination.Query
+    Action   string `form:"action" valid:"Required;Enum(summary|process|city|list)"`
+    CityID   string `form:"id" valid:"MaxSize(32)"`
+    Country  string `form:"country" valid:"MaxSize(100)"`
+    CityName string `form:"city_name" valid:"MaxSize(100)"`
+}
+
+// LocationRequest identifies a stored location
+type LocationRequest struct {
+    ID string `path:"id" valid:"Required;MaxSize(32)"`
+}
+
+// LocationListRequest filters the paginated location list
+type LocationListRequest struct {
+    pagination.Query
+    Country  string `form:"country" valid:"MaxSize(100)"`
+    CityName string `form:"city_name" valid:"MaxSize(100)"`
+}
+
+// CrawlRequest identifies a recorded crawl run
+type CrawlRequest struct {
+    ID int64 `path:"id" valid:"Required;Min(1)"`
+}
+
+// ListPropertiesRequest filters, sorts and pages the property list
+type ListPropertiesRequest struct {
+    pagination.Query
+    models.PropertyFilter
+    Sort     string `form:"sort" valid:"Enum(newest|name|price_asc|price_desc|rating|bedrooms)"`
+    Currency string `form:"currency" valid:"Length(3);Alpha"`
+}
+
+// PropertyDetailsRequest selects stored property details, optionally priced in another currency
+type PropertyDetailsRequest struct {
+    PropertyIDs []string `json:"property_ids" valid:"Required;MaxSize(100)"`
+    Currency    string   `form:"currency" valid:"Length(3);Alpha"`
+}
+
+// SearchRequest is a free-text property search
+type SearchRequest struct {
+    pagination.Query
+    Q string `form:"q" valid:"Required;MaxSize(200)"`
+}
+
+// ListReviewsRequest pages the approved reviews of a property
+type ListReviewsRequest struct {
+    pagination.Query
+    DestID string `form:"dest_id" valid:"Required;MaxSize(32)"`
+    Sort   string `form:"sort" default:"newest" valid:"Enum(newest|oldest|highest|lowest)"`
+}
+
+// SubmitReviewRequest is a guest review; scores are on a 1-10 scale and the
+// overall score is their average
+type SubmitReviewRequest struct {
+    DestID        string  `json:"dest_id" valid:"Required;MaxSize(32)"`
+    AuthorName    string  `json:"author_name" valid:"Required;MaxSize(100)"`
+    StayReference string  `json:"stay_reference" valid:"MaxSize(64)"`
+    Cleanliness   float64 `json:"cleanliness"`
+    Location      float64 `json:"location"`
+    Value         float64 `json:"value"`
+    Text          string  `json:"text" valid:"MaxSize(5000)"`
+    Language      string  `json:"language" valid:"MaxSize(16)"`
+}
+
+func (r *SubmitReviewRequest) Valid(v *validation.Validation) {
+    for _, score := range []struct {
+        name  string
+        value float64
+    }{
+        {"cleanliness", r.Cleanliness},
+        {"location", r.Location},
+        {"value", r.Value},
+    } {
+        if score.value < 1 || score.value > 10 {
+            v.SetError(score.name, "must be between 1 and 10")
+        }
+    }
+}
+
+// Review converts the request into a review for submission
+func (r *SubmitReviewRequest) Review() *models.Review {
+    return &models.Review{
+        DestID:        r.DestID,
+        AuthorName:    r.AuthorName,
+        StayReference: r.StayReference,
+        Cleanliness:   r.Cleanliness,
+        Location:      r.Location,
+        Value:         r.Value,
+        Text:          r.Text,
+        Language:      r.Language,
+    }
+}
+
+// ReviewStatusRequest pages the reviews in one moderation status
+type ReviewStatusRequest struct {
+    pagination.Query
+    Status string `form:"status" default:"pending" valid:"Enum(pending|approved|rejected)"`
+}
+
+// ModerateReviewRequest sets the moderation status of a review
+type ModerateReviewRequest struct {
+    ID     int64  `path:"id" valid:"Required;Min(1)"`
+    Status string `json:"status" valid:"Required;Enum(pending|approved|rejected)"`
+}
+
+// UpdateRatesRequest replaces the FX rate table
+type UpdateRatesRequest struct {
+    Base  string             `json:"base" valid:"Required;Length(3);Alpha"`
+    Rates map[string]float64 `json:"rates"`
+}
+
+func (r *UpdateRatesRequest) Valid(v *validation.Validation) {
+    if len(r.Rates) == 0 {
+        v.SetError("rates", "is required")
+    }
+}
+
+// WishlistRequest identifies one of the guest's wishlists
+type WishlistRequest struct {
+    ID int64 `path:"id" valid:"Required;Min(1)"`
+}
+
+// WishlistNameRequest names a new or renamed wishlist
+type WishlistNameRequest struct {
+    Name string `json:"name" valid:"Required;MaxSize(100)"`
+}
+
+// RenameWishlistRequest renames one of the guest's wishlists
+type RenameWishlistRequest struct {
+    WishlistRequest
+    WishlistNameRequest
+}
+
+// AddWishlistItemRequest adds a property to a wishlist
+type AddWishlistItemRequest struct {
+    WishlistRequest
+    DestID string `json:"dest_id" valid:"Required;MaxSize(32)"`
+}
+
+// RemoveWishlistItemRequest removes a property from a wishlist
+type RemoveWishlistItemRequest struct {
+    WishlistRequest
+    DestID string `path:"dest_id" valid:"Required;MaxSize(32)"`
+}
+
+// SharedWishlistRequest looks a wishlist up by its share token
+type SharedWishlistRequest struct {
+    Token string `path:"token" valid:"Required;MaxSize(64)"`
+}
+
+// SavedSearchRequest identifies one of the guest's saved searches
+type SavedSearchRequest struct {
+    ID int64 `path:"id" valid:"Required;Min(1)"`
+}
+
+// CreateSavedSearchRequest saves a named property search
+type CreateSavedSearchRequest struct {
+    Name   string            `json:"name" valid:"Required;MaxSize(100)"`
+    Params map[string]string `json:"params"`
+}
+
+func (r *CreateSavedSearchRequest) Valid(v *validation.Validation) {
+    if len(r.Params) == 0 {
+        v.SetError("params", "is required")
+    }
+}
+
+// GraphQLRequest is a GraphQL query posted as JSON
+type GraphQLRequest struct {
+    Query         string                 `json:"query" valid:"Required;MaxSize(20000)"`
+    OperationName string                 `json:"operationName" valid:"MaxSize(100)"`
+    Variables     map[string]interface{} `json:"variables"`
+}
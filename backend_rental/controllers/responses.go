@@ -0,0 +1,22 @@
+package controllers
+
+import "backend_rental/models"
+
+// Response bodies that are not a model
+
+// WishlistResponse is a wishlist with its items
+type WishlistResponse struct {
+    Wishlist *models.Wishlist     `json:"wishlist"`
+    Items    []models.WishlistItem `json:"items"`
+}
+
+// ShareResponse carries the share token of a wishlist and the URL it opens
+type ShareResponse struct {
+    ShareToken string `json:"share_token"`
+    ShareURL   string `json:"share_url"`
+}
+
+// StatusResponse reports the outcome of a request without a resource to return
+type StatusResponse struct {
+    Status string `json:"status"`
+}
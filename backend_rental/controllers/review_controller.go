@@ -0,0 +1,104 @@
+package controllers
+
+import (
+    "backend_rental/services"
+    "backend_rental/utils/pagination"
+)
+
+// ReviewController handles guest review submission, listing and moderation
+type ReviewController struct {
+    BaseController
+    reviewService *services.ReviewService
+}
+
+func (c *ReviewController) Prepare() {
+    c.reviewService = services.NewReviewService()
+}
+
+// ListReviews handles GET requests to /v1/property/reviews
+func (c *ReviewController) ListReviews() {
+    var request ListReviewsRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+    params, ok := c.PageParams(request.Query)
+    if !ok {
+        return
+    }
+    destID := request.DestID
+
+    reviews, page, err := c.reviewService.ListReviews(c.Ctx.Request.Context(), destID, request.Sort, params)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    aggregate, err := c.reviewService.GetAggregate(c.Ctx.Request.Context(), destID)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = map[string]interface{}{
+        "data":       reviews,
+        "pagination": page,
+        "aggregate":  aggregate,
+    }
+    c.ServeJSON()
+}
+
+// SubmitReview handles POST requests to /v1/property/reviews
+func (c *ReviewController) SubmitReview() {
+    var request SubmitReviewRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    review := request.Review()
+    if err := c.reviewService.SubmitReview(c.Ctx.Request.Context(), review); err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Ctx.Output.SetStatus(201)
+    c.Data["json"] = review
+    c.ServeJSON()
+}
+
+// ListPendingReviews handles GET requests to /v1/admin/reviews
+func (c *ReviewController) ListPendingReviews() {
+    var request ReviewStatusRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+    params, ok := c.PageParams(request.Query)
+    if !ok {
+        return
+    }
+
+    reviews, page, err := c.reviewService.ListReviewsByStatus(c.Ctx.Request.Context(), request.Status, params)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = pagination.Envelope{Data: reviews, Pagination: page}
+    c.ServeJSON()
+}
+
+// ModerateReview handles PUT requests to /v1/admin/reviews/:id/moderation
+func (c *ReviewController) ModerateReview() {
+    var request ModerateReviewRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    review, err := c.reviewService.ModerateReview(c.Ctx.Request.Context(), request.ID, request.Status)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = review
+    c.ServeJSON()
+}
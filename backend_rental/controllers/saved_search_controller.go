@@ -0,0 +1,118 @@
+package controllers
+
+import (
+    "backend_rental/middleware"
+    "backend_rental/services"
+    "backend_rental/utils"
+    "backend_rental/utils/apperrors"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+// SavedSearchController manages a guest's saved searches, identified by X-Guest-ID
+type SavedSearchController struct {
+    BaseController
+    savedSearchService *services.SavedSearchService
+    guestID            string
+}
+
+func (c *SavedSearchController) Prepare() {
+    rapidAPIKey, _ := beego.AppConfig.String("rapidapikey")
+    c.savedSearchService = services.NewSavedSearchService(services.NewPropertyService(utils.GetDB(), rapidAPIKey))
+    c.guestID = middleware.GuestID(c.Ctx)
+}
+
+// ListSavedSearches handles GET requests to /v1/saved-searches
+func (c *SavedSearchController) ListSavedSearches() {
+    if !c.requireGuest() {
+        return
+    }
+
+    searches, err := c.savedSearchService.ListSavedSearches(c.guestID)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = searches
+    c.ServeJSON()
+}
+
+// CreateSavedSearch handles POST requests to /v1/saved-searches; params uses
+// the same keys as the property list query string
+func (c *SavedSearchController) CreateSavedSearch() {
+    if !c.requireGuest() {
+        return
+    }
+
+    var request CreateSavedSearchRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    search, err := c.savedSearchService.CreateSavedSearch(c.Ctx.Request.Context(), c.guestID, request.Name, request.Params)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Ctx.Output.SetStatus(201)
+    c.Data["json"] = search
+    c.ServeJSON()
+}
+
+// DeleteSavedSearch handles DELETE requests to /v1/saved-searches/:id
+func (c *SavedSearchController) DeleteSavedSearch() {
+    if !c.requireGuest() {
+        return
+    }
+
+    id, ok := c.savedSearchID()
+    if !ok {
+        return
+    }
+
+    if err := c.savedSearchService.DeleteSavedSearch(c.guestID, id); err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = StatusResponse{Status: "deleted"}
+    c.ServeJSON()
+}
+
+// RunSavedSearch handles POST requests to /v1/saved-searches/:id/run
+func (c *SavedSearchController) RunSavedSearch() {
+    if !c.requireGuest() {
+        return
+    }
+
+    id, ok := c.savedSearchID()
+    if !ok {
+        return
+    }
+
+    run, err := c.savedSearchService.RunSavedSearch(c.Ctx.Request.Context(), c.guestID, id)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = run
+    c.ServeJSON()
+}
+
+func (c *SavedSearchController) savedSearchID() (int64, bool) {
+    var request SavedSearchRequest
+    if !c.BindRequest(&request) {
+        return 0, false
+    }
+    return request.ID, true
+}
+
+func (c *SavedSearchController) requireGuest() bool {
+    if c.guestID == "" {
+        c.RespondError(apperrors.Unauthorized("X-Guest-ID header is required"))
+        return false
+    }
+    return true
+}
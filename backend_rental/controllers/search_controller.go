@@ -0,0 +1,51 @@
+package controllers
+
+import (
+    "backend_rental/middleware"
+    "backend_rental/services"
+    "backend_rental/utils"
+    "backend_rental/utils/pagination"
+)
+
+// SearchController serves full-text property search
+type SearchController struct {
+    BaseController
+    searchService *services.SearchService
+}
+
+func (c *SearchController) Prepare() {
+    c.searchService = services.NewSearchService(utils.GetDB())
+}
+
+// Search handles GET requests to /v1/property/search
+func (c *SearchController) Search() {
+    var request SearchRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+    params, ok := c.PageParams(request.Query)
+    if !ok {
+        return
+    }
+
+    results, page, err := c.searchService.Search(c.Ctx.Request.Context(), request.Q, middleware.Languages(c.Ctx), params)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = pagination.Envelope{Data: results, Pagination: page}
+    c.ServeJSON()
+}
+
+// Reindex handles POST requests to /v1/admin/search/reindex
+func (c *SearchController) Reindex() {
+    indexed, err := c.searchService.RebuildIndex(c.Ctx.Request.Context())
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = map[string]interface{}{"indexed": indexed}
+    c.ServeJSON()
+}
@@ -0,0 +1,15 @@
+package controllers
+
+import "backend_rental/utils/apiclient"
+
+// UpstreamCacheController reports on the cache of upstream responses
+type UpstreamCacheController struct {
+    BaseController
+}
+
+// Stats handles GET requests to /v1/admin/upstream-cache with the hit and
+// miss counts per upstream endpoint since startup
+func (c *UpstreamCacheController) Stats() {
+    c.Data["json"] = apiclient.DefaultCache().Stats()
+    c.ServeJSON()
+}
@@ -0,0 +1,227 @@
+package controllers
+
+import (
+    "backend_rental/middleware"
+    "backend_rental/models"
+    "backend_rental/services"
+    "backend_rental/utils"
+    "backend_rental/utils/apperrors"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+// WishlistController manages a guest's wishlists, identified by X-Guest-ID
+type WishlistController struct {
+    BaseController
+    wishlistService *services.WishlistService
+    guestID         string
+}
+
+func (c *WishlistController) Prepare() {
+    rapidAPIKey, _ := beego.AppConfig.String("rapidapikey")
+    c.wishlistService = services.NewWishlistService(services.NewPropertyService(utils.GetDB(), rapidAPIKey))
+    c.guestID = middleware.GuestID(c.Ctx)
+}
+
+// ListWishlists handles GET requests to /v1/wishlists
+func (c *WishlistController) ListWishlists() {
+    if !c.requireGuest() {
+        return
+    }
+
+    wishlists, err := c.wishlistService.ListWishlists(c.guestID)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = wishlists
+    c.ServeJSON()
+}
+
+// CreateWishlist handles POST requests to /v1/wishlists
+func (c *WishlistController) CreateWishlist() {
+    if !c.requireGuest() {
+        return
+    }
+
+    var request WishlistNameRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    wishlist, err := c.wishlistService.CreateWishlist(c.guestID, request.Name)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Ctx.Output.SetStatus(201)
+    c.Data["json"] = wishlist
+    c.ServeJSON()
+}
+
+// GetWishlist handles GET requests to /v1/wishlists/:id
+func (c *WishlistController) GetWishlist() {
+    if !c.requireGuest() {
+        return
+    }
+
+    id, ok := c.wishlistID()
+    if !ok {
+        return
+    }
+
+    wishlist, err := c.wishlistService.GetWishlist(c.guestID, id)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.serveWishlist(wishlist)
+}
+
+// GetSharedWishlist handles GET requests to /v1/wishlists/shared/:token
+func (c *WishlistController) GetSharedWishlist() {
+    var request SharedWishlistRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    wishlist, err := c.wishlistService.GetSharedWishlist(request.Token)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.serveWishlist(wishlist)
+}
+
+// RenameWishlist handles PUT requests to /v1/wishlists/:id
+func (c *WishlistController) RenameWishlist() {
+    if !c.requireGuest() {
+        return
+    }
+
+    var request RenameWishlistRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    wishlist, err := c.wishlistService.RenameWishlist(c.guestID, request.ID, request.Name)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = wishlist
+    c.ServeJSON()
+}
+
+// DeleteWishlist handles DELETE requests to /v1/wishlists/:id
+func (c *WishlistController) DeleteWishlist() {
+    if !c.requireGuest() {
+        return
+    }
+
+    id, ok := c.wishlistID()
+    if !ok {
+        return
+    }
+
+    if err := c.wishlistService.DeleteWishlist(c.guestID, id); err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = StatusResponse{Status: "deleted"}
+    c.ServeJSON()
+}
+
+// AddItem handles POST requests to /v1/wishlists/:id/items
+func (c *WishlistController) AddItem() {
+    if !c.requireGuest() {
+        return
+    }
+
+    var request AddWishlistItemRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    if err := c.wishlistService.AddItem(c.guestID, request.ID, request.DestID); err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.GetWishlist()
+}
+
+// RemoveItem handles DELETE requests to /v1/wishlists/:id/items/:dest_id
+func (c *WishlistController) RemoveItem() {
+    if !c.requireGuest() {
+        return
+    }
+
+    var request RemoveWishlistItemRequest
+    if !c.BindRequest(&request) {
+        return
+    }
+
+    if err := c.wishlistService.RemoveItem(c.guestID, request.ID, request.DestID); err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.GetWishlist()
+}
+
+// ShareWishlist handles POST requests to /v1/wishlists/:id/share
+func (c *WishlistController) ShareWishlist() {
+    if !c.requireGuest() {
+        return
+    }
+
+    id, ok := c.wishlistID()
+    if !ok {
+        return
+    }
+
+    wishlist, err := c.wishlistService.Share(c.guestID, id)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = ShareResponse{
+        ShareToken: *wishlist.ShareToken,
+        ShareURL:   "/v1/wishlists/shared/" + *wishlist.ShareToken,
+    }
+    c.ServeJSON()
+}
+
+func (c *WishlistController) serveWishlist(wishlist *models.Wishlist) {
+    items, err := c.wishlistService.ListItems(c.Ctx.Request.Context(), wishlist)
+    if err != nil {
+        c.RespondError(err)
+        return
+    }
+
+    c.Data["json"] = WishlistResponse{Wishlist: wishlist, Items: items}
+    c.ServeJSON()
+}
+
+func (c *WishlistController) wishlistID() (int64, bool) {
+    var request WishlistRequest
+    if !c.BindRequest(&request) {
+        return 0, false
+    }
+    return request.ID, true
+}
+
+func (c *WishlistController) requireGuest() bool {
+    if c.guestID == "" {
+        c.RespondError(apperrors.Unauthorized("X-Guest-ID header is required"))
+        return false
+    }
+    return true
+}
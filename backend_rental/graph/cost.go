@@ -0,0 +1,142 @@
+package graph
+
+import (
+    "strconv"
+    "strings"
+
+    "backend_rental/utils/apperrors"
+    beego "github.com/beego/beego/v2/server/web"
+    "github.com/graphql-go/graphql"
+    "github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bound the queries the executor accepts
+type Limits struct {
+    // MaxDepth is the deepest field nesting, counting top-level fields as 1
+    MaxDepth int
+    // MaxCost bounds the estimated number of resolved fields
+    MaxCost int
+}
+
+// LimitsFromConfig reads graphqlmaxdepth and graphqlmaxcost
+func LimitsFromConfig() Limits {
+    return Limits{
+        MaxDepth: beego.AppConfig.DefaultInt("graphqlmaxdepth", 8),
+        MaxCost:  beego.AppConfig.DefaultInt("graphqlmaxcost", 5000),
+    }
+}
+
+// Check rejects a validated document whose operation is too deep or too
+// costly. A field costs 1 plus its children, multiplied by its first argument
+// when it has one, so the estimate is the number of fields a full result
+// would hold. Introspection fields are free.
+func (l Limits) Check(schema *graphql.Schema, document *ast.Document, operationName string, variables map[string]interface{}) error {
+    walker := &costWalker{schema: schema, variables: variables, fragments: map[string]*ast.FragmentDefinition{}}
+    var operation *ast.OperationDefinition
+    for _, definition := range document.Definitions {
+        switch definition := definition.(type) {
+        case *ast.OperationDefinition:
+            if operation == nil && (operationName == "" || definition.Name != nil && definition.Name.Value == operationName) {
+                operation = definition
+            }
+        case *ast.FragmentDefinition:
+            walker.fragments[definition.Name.Value] = definition
+        }
+    }
+    if operation == nil || operation.Operation != ast.OperationTypeQuery {
+        // The executor reports the missing operation
+        return nil
+    }
+
+    cost, depth := walker.selectionSet(operation.SelectionSet, schema.QueryType(), 1)
+    if depth > l.MaxDepth {
+        return apperrors.Validation("query depth %d exceeds the limit of %d", depth, l.MaxDepth)
+    }
+    if cost > l.MaxCost {
+        return apperrors.Validation("query cost %d exceeds the limit of %d", cost, l.MaxCost)
+    }
+    return nil
+}
+
+type costWalker struct {
+    schema    *graphql.Schema
+    variables map[string]interface{}
+    fragments map[string]*ast.FragmentDefinition
+}
+
+// selectionSet returns the cost of a selection set on parent and the deepest
+// level it reaches
+func (w *costWalker) selectionSet(set *ast.SelectionSet, parent graphql.Type, depth int) (cost, maxDepth int) {
+    maxDepth = depth - 1
+    if set == nil {
+        return 0, maxDepth
+    }
+    object, _ := graphql.GetNamed(parent).(*graphql.Object)
+
+    for _, selection := range set.Selections {
+        var childCost, childDepth int
+        switch selection := selection.(type) {
+        case *ast.Field:
+            if strings.HasPrefix(selection.Name.Value, "__") || object == nil {
+                continue
+            }
+            field := object.Fields()[selection.Name.Value]
+            if field == nil {
+                continue
+            }
+            childCost, childDepth = w.selectionSet(selection.SelectionSet, field.Type, depth+1)
+            childCost = 1 + w.multiplier(selection, field)*childCost
+            if childDepth < depth {
+                childDepth = depth
+            }
+        case *ast.InlineFragment:
+            typ := parent
+            if selection.TypeCondition != nil {
+                typ = w.schema.Type(selection.TypeCondition.Name.Value)
+            }
+            childCost, childDepth = w.selectionSet(selection.SelectionSet, typ, depth)
+        case *ast.FragmentSpread:
+            fragment := w.fragments[selection.Name.Value]
+            if fragment == nil {
+                continue
+            }
+            childCost, childDepth = w.selectionSet(fragment.SelectionSet, w.schema.Type(fragment.TypeCondition.Name.Value), depth)
+        }
+        cost += childCost
+        if childDepth > maxDepth {
+            maxDepth = childDepth
+        }
+    }
+    return cost, maxDepth
+}
+
+// multiplier is the first argument of a field, from the query, its variables
+// or the schema default, or 1 for fields without one
+func (w *costWalker) multiplier(selection *ast.Field, field *graphql.FieldDefinition) int {
+    first := 0
+    for _, arg := range field.Args {
+        if arg.Name() == "first" {
+            first, _ = arg.DefaultValue.(int)
+        }
+    }
+    for _, arg := range selection.Arguments {
+        if arg.Name.Value != "first" {
+            continue
+        }
+        switch value := arg.Value.(type) {
+        case *ast.IntValue:
+            first, _ = strconv.Atoi(value.Value)
+        case *ast.Variable:
+            switch v := w.variables[value.Name.Value].(type) {
+            case int:
+                first = v
+            case float64:
+                first = int(v)
+            }
+        }
+    }
+    if first < 1 {
+        return 1
+    }
+    return first
+}
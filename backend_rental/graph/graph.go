@@ -0,0 +1,256 @@
+// Package graph serves the GraphQL API over locations and properties. Nested
+// fields are resolved through per-request loaders so that a list of N items
+// costs one query per field instead of N, and queries are rejected up front
+// when their depth or estimated cost exceeds the configured limits.
+package graph
+
+import (
+    "context"
+    "fmt"
+    "log/slog"
+    "sort"
+
+    "backend_rental/models"
+    "backend_rental/services"
+    "backend_rental/utils/apperrors"
+    "github.com/graphql-go/graphql"
+    "github.com/graphql-go/graphql/gqlerrors"
+    "github.com/graphql-go/graphql/language/parser"
+    "github.com/graphql-go/graphql/language/source"
+)
+
+// Request is a GraphQL query with its variables
+type Request struct {
+    Query         string
+    OperationName string
+    Variables     map[string]interface{}
+}
+
+// Executor runs queries against the schema
+type Executor struct {
+    schema     graphql.Schema
+    properties services.PropertyServiceInterface
+    limits     Limits
+}
+
+// NewExecutor builds the schema; properties backs the property fields
+func NewExecutor(properties services.PropertyServiceInterface, limits Limits) (*Executor, error) {
+    schema, err := NewSchema()
+    if err != nil {
+        return nil, err
+    }
+    return &Executor{schema: schema, properties: properties, limits: limits}, nil
+}
+
+// Execute parses, validates and runs a query. Request-level failures (syntax,
+// validation, limits) come back with a nil Data; the caller tells them apart
+// by ok being false.
+func (e *Executor) Execute(ctx context.Context, req Request, languages []string, requestID string) (result *graphql.Result, ok bool) {
+    document, err := parser.Parse(parser.ParseParams{
+        Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+    })
+    if err != nil {
+        return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, false
+    }
+
+    validation := graphql.ValidateDocument(&e.schema, document, nil)
+    if !validation.IsValid {
+        return &graphql.Result{Errors: validation.Errors}, false
+    }
+
+    if err := e.limits.Check(&e.schema, document, req.OperationName, req.Variables); err != nil {
+        return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, false
+    }
+
+    result = graphql.Execute(graphql.ExecuteParams{
+        Schema:        e.schema,
+        AST:           document,
+        OperationName: req.OperationName,
+        Args:          req.Variables,
+        Context:       context.WithValue(ctx, loadersKey{}, e.newLoaders(ctx, languages)),
+    })
+    for i, formatted := range result.Errors {
+        result.Errors[i] = maskError(formatted, requestID)
+    }
+    return result, true
+}
+
+// maskError replaces the message of a resolver error with the client-safe
+// detail of its domain error, logging internal causes
+func maskError(formatted gqlerrors.FormattedError, requestID string) gqlerrors.FormattedError {
+    cause := formatted.OriginalError()
+    if located, ok := cause.(*gqlerrors.Error); ok {
+        cause = located.OriginalError
+    }
+    if cause == nil {
+        return formatted
+    }
+
+    if apperrors.KindOf(cause) == apperrors.KindInternal {
+        slog.Error("GraphQL resolver failed", "request_id", requestID, "path", formatted.Path, "error", cause)
+    }
+    problem := apperrors.NewProblem(cause, "", requestID)
+    formatted.Message = problem.Detail
+    formatted.Extensions = map[string]interface{}{"type": problem.Type, "status": problem.Status}
+    if len(problem.Errors) > 0 {
+        formatted.Extensions["errors"] = problem.Errors
+    }
+    return formatted
+}
+
+type loadersKey struct{}
+
+// loaders batch the lookups of one request; the batches run under its ctx
+type loaders struct {
+    ctx             context.Context
+    languages       []string
+    propertyService services.PropertyServiceInterface
+
+    locations    *Loader[string, *location]
+    properties   *Loader[string, *models.Property]
+    descriptions *Loader[string, *models.PropertyDescription]
+    amenities    *Loader[string, []string]
+    aggregates   *Loader[string, *models.ReviewAggregate]
+
+    // Nested lists take a limit, so there is one loader per argument set
+    cityProperties map[int]*Loader[services.CityKey, []*models.Property]
+    reviews        map[string]*Loader[string, []models.Review]
+}
+
+func loadersFrom(p graphql.ResolveParams) *loaders {
+    return p.Context.Value(loadersKey{}).(*loaders)
+}
+
+func (e *Executor) newLoaders(ctx context.Context, languages []string) *loaders {
+    l := &loaders{
+        ctx:             ctx,
+        languages:       languages,
+        propertyService: e.properties,
+        cityProperties:  map[int]*Loader[services.CityKey, []*models.Property]{},
+        reviews:         map[string]*Loader[string, []models.Review]{},
+    }
+
+    l.locations = NewLoader(func(ids []string) (map[string]*location, error) {
+        locationService := &services.LocationService{}
+        stored, err := locationService.GetLocationsByIDs(ctx, ids)
+        if err != nil {
+            return nil, err
+        }
+        localized, err := locationService.LocalizeLocations(ctx, append([]models.Location(nil), stored...), languages)
+        if err != nil {
+            return nil, err
+        }
+        result := make(map[string]*location, len(stored))
+        for _, l := range newLocations(stored, localized) {
+            result[l.ID] = l
+        }
+        return result, nil
+    })
+
+    l.properties = NewLoader(func(destIDs []string) (map[string]*models.Property, error) {
+        properties, err := e.properties.GetPropertiesByDestIDs(ctx, destIDs)
+        if err != nil {
+            return nil, err
+        }
+        result := make(map[string]*models.Property, len(properties))
+        for i := range properties {
+            result[properties[i].DestID] = &properties[i]
+        }
+        return result, nil
+    })
+
+    l.descriptions = NewLoader(func(destIDs []string) (map[string]*models.PropertyDescription, error) {
+        return services.NewPropDescService().GetStoredDescriptions(destIDs, languages)
+    })
+
+    l.amenities = NewLoader(func(destIDs []string) (map[string][]string, error) {
+        amenities, err := e.properties.GetAmenities(ctx, destIDs)
+        if err != nil {
+            return nil, err
+        }
+
+        var names []string
+        seen := map[string]bool{}
+        for _, list := range amenities {
+            for _, name := range list {
+                if !seen[name] {
+                    seen[name] = true
+                    names = append(names, name)
+                }
+            }
+        }
+        sort.Strings(names)
+        translated, err := services.NewTranslationService().Lookup(models.TranslationEntityAmenity, names,
+            models.TranslationFieldName, languages)
+        if err != nil {
+            return nil, err
+        }
+
+        result := make(map[string][]string, len(destIDs))
+        for _, destID := range destIDs {
+            localized := make([]string, 0, len(amenities[destID]))
+            for _, name := range amenities[destID] {
+                if value, ok := translated[name]; ok {
+                    name = value
+                }
+                localized = append(localized, name)
+            }
+            result[destID] = localized
+        }
+        return result, nil
+    })
+
+    l.aggregates = NewLoader(func(destIDs []string) (map[string]*models.ReviewAggregate, error) {
+        return services.NewReviewService().GetAggregates(ctx, destIDs)
+    })
+
+    return l
+}
+
+// cityPropertyLoader returns the loader of the first properties of cities
+func (l *loaders) cityPropertyLoader(first int) *Loader[services.CityKey, []*models.Property] {
+    if loader, ok := l.cityProperties[first]; ok {
+        return loader
+    }
+    loader := NewLoader(func(cities []services.CityKey) (map[services.CityKey][]*models.Property, error) {
+        byCity, err := l.propertyService.GetPropertiesByCities(l.ctx, cities, first)
+        if err != nil {
+            return nil, err
+        }
+        result := make(map[services.CityKey][]*models.Property, len(cities))
+        for _, city := range cities {
+            properties := byCity[city]
+            items := make([]*models.Property, len(properties))
+            for i := range properties {
+                items[i] = &properties[i]
+                l.properties.Prime(items[i].DestID, items[i])
+            }
+            result[city] = items
+        }
+        return result, nil
+    })
+    l.cityProperties[first] = loader
+    return loader
+}
+
+// reviewLoader returns the loader of the first approved reviews of properties
+func (l *loaders) reviewLoader(sort string, first int) *Loader[string, []models.Review] {
+    key := fmt.Sprintf("%s/%d", sort, first)
+    if loader, ok := l.reviews[key]; ok {
+        return loader
+    }
+    loader := NewLoader(func(destIDs []string) (map[string][]models.Review, error) {
+        byProperty, err := services.NewReviewService().ListLatestReviews(l.ctx, destIDs, sort, first)
+        if err != nil {
+            return nil, err
+        }
+        for _, destID := range destIDs {
+            if byProperty[destID] == nil {
+                byProperty[destID] = []models.Review{}
+            }
+        }
+        return byProperty, nil
+    })
+    l.reviews[key] = loader
+    return loader
+}
@@ -0,0 +1,96 @@
+package graph
+
+import (
+    "errors"
+    "reflect"
+    "testing"
+
+    "github.com/graphql-go/graphql"
+    "github.com/graphql-go/graphql/language/parser"
+)
+
+func TestLimits(t *testing.T) {
+    schema, err := NewSchema()
+    if err != nil {
+        t.Fatalf("NewSchema: %v", err)
+    }
+    limits := Limits{MaxDepth: 5, MaxCost: 500}
+
+    tests := []struct {
+        name      string
+        query     string
+        variables map[string]interface{}
+        wantErr   bool
+    }{
+        // 1 + 20 * (1 + 1 + 1 + 5 * 1) = 161
+        {"defaults", `{ properties { items { name amenities reviews { text } } } }`, nil, false},
+        // 1 + 100 * (1 + 1 + 1 + 5 * 1) = 801
+        {"literal first", `{ properties(first: 100) { items { name amenities reviews { text } } } }`, nil, true},
+        {"variable first", `query($n: Int) { properties(first: $n) { items { name amenities reviews { text } } } }`,
+            map[string]interface{}{"n": float64(100)}, true},
+        {"fragment", `{ properties(first: 100) { ...items } } fragment items on PropertyConnection { items { name reviews { text } } }`, nil, true},
+        {"depth", `{ location(id: "1") { properties { location { properties { location { id } } } } } }`, nil, true},
+        {"introspection", `{ __schema { types { name fields { name type { name ofType { name ofType { name } } } } } } }`, nil, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            document, err := parser.Parse(parser.ParseParams{Source: tt.query})
+            if err != nil {
+                t.Fatalf("Parse: %v", err)
+            }
+            if result := graphql.ValidateDocument(&schema, document, nil); !result.IsValid {
+                t.Fatalf("ValidateDocument: %v", result.Errors)
+            }
+            err = limits.Check(&schema, document, "", tt.variables)
+            if (err != nil) != tt.wantErr {
+                t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+            }
+        })
+    }
+}
+
+func TestLoaderBatches(t *testing.T) {
+    var batches [][]string
+    loader := NewLoader(func(keys []string) (map[string]int, error) {
+        batches = append(batches, keys)
+        return map[string]int{"a": 1, "b": 2}, nil
+    })
+
+    thunks := []func() (interface{}, error){loader.Load("a"), loader.Load("b"), loader.Load("a"), loader.Load("c")}
+    var values []interface{}
+    for _, thunk := range thunks {
+        value, err := thunk()
+        if err != nil {
+            t.Fatal(err)
+        }
+        values = append(values, value)
+    }
+    if value, _ := loader.Get("b"); value != 2 {
+        t.Errorf("cached b = %d", value)
+    }
+
+    if !reflect.DeepEqual(batches, [][]string{{"a", "b", "c"}}) {
+        t.Errorf("batches = %v", batches)
+    }
+    if !reflect.DeepEqual(values, []interface{}{1, 2, 1, 0}) {
+        t.Errorf("values = %v", values)
+    }
+}
+
+func TestLoaderErrors(t *testing.T) {
+    calls := 0
+    loader := NewLoader(func(keys []string) (map[string]int, error) {
+        calls++
+        return nil, errors.New("down")
+    })
+    first, second := loader.Load("a"), loader.Load("b")
+    if _, err := first(); err == nil {
+        t.Error("expected an error for a")
+    }
+    if _, err := second(); err == nil {
+        t.Error("expected an error for b")
+    }
+    if calls != 1 {
+        t.Errorf("fetched %d times", calls)
+    }
+}
@@ -0,0 +1,83 @@
+package graph
+
+import "sync"
+
+// Loader batches the keys requested while one level of a query is resolved
+// into a single fetch. Load records a key and returns a thunk; the executor
+// runs the thunks of a level after resolving all of its fields, so the first
+// thunk fetches every key recorded so far. Results are cached for the request.
+type Loader[K comparable, V any] struct {
+    fetch func(keys []K) (map[K]V, error)
+
+    mu      sync.Mutex
+    pending []K
+    queued  map[K]bool
+    values  map[K]V
+    errs    map[K]error
+}
+
+// NewLoader creates a loader; keys missing from the fetched map load as the zero value
+func NewLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *Loader[K, V] {
+    return &Loader[K, V]{
+        fetch:  fetch,
+        queued: map[K]bool{},
+        values: map[K]V{},
+        errs:   map[K]error{},
+    }
+}
+
+// Load queues a key and returns a thunk resolving to its value
+func (l *Loader[K, V]) Load(key K) func() (interface{}, error) {
+    l.mu.Lock()
+    if _, done := l.values[key]; !done && !l.queued[key] {
+        l.queued[key] = true
+        l.pending = append(l.pending, key)
+    }
+    l.mu.Unlock()
+
+    return func() (interface{}, error) {
+        value, err := l.Get(key)
+        if err != nil {
+            return nil, err
+        }
+        return value, nil
+    }
+}
+
+// Get returns the value of a key, fetching it with the other queued keys when needed
+func (l *Loader[K, V]) Get(key K) (V, error) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    if _, done := l.values[key]; !done && l.errs[key] == nil {
+        if !l.queued[key] {
+            l.pending = append(l.pending, key)
+        }
+        l.flush()
+    }
+    return l.values[key], l.errs[key]
+}
+
+// Prime caches a value already loaded elsewhere, such as a list item
+func (l *Loader[K, V]) Prime(key K, value V) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    if _, done := l.values[key]; !done {
+        l.values[key] = value
+    }
+}
+
+func (l *Loader[K, V]) flush() {
+    keys := l.pending
+    l.pending = nil
+    l.queued = map[K]bool{}
+
+    fetched, err := l.fetch(keys)
+    for _, key := range keys {
+        if err != nil {
+            l.errs[key] = err
+            continue
+        }
+        l.values[key] = fetched[key]
+    }
+}
@@ -0,0 +1,453 @@
+package graph
+
+import (
+    "encoding/json"
+    "errors"
+    "net/url"
+    "strconv"
+
+    "backend_rental/models"
+    "backend_rental/services"
+    "backend_rental/utils"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/pagination"
+    "github.com/graphql-go/graphql"
+    "github.com/graphql-go/graphql/language/ast"
+)
+
+// Nested lists are bounded like the top-level pages
+const (
+    defaultNestedLimit = 5
+    maxNestedLimit     = pagination.MaxLimit
+)
+
+// location is a localized location that keeps its stored names, which
+// properties reference
+type location struct {
+    *models.Location
+    city services.CityKey
+}
+
+// Resolve resolves the fields without a resolver from the localized location
+func (l *location) Resolve(p graphql.ResolveParams) (interface{}, error) {
+    p.Source = l.Location
+    return graphql.DefaultResolveFn(p)
+}
+
+func newLocations(stored, localized []models.Location) []*location {
+    result := make([]*location, len(localized))
+    for i := range localized {
+        result[i] = &location{
+            Location: &localized[i],
+            city:     services.CityKey{CityName: stored[i].CityName, Country: stored[i].Country},
+        }
+    }
+    return result
+}
+
+// NewSchema builds the GraphQL schema over locations and properties
+func NewSchema() (graphql.Schema, error) {
+    pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+        Name: "PageInfo",
+        Fields: graphql.Fields{
+            "nextCursor": &graphql.Field{Type: graphql.String},
+            "prevCursor": &graphql.Field{Type: graphql.String},
+            "totalCount": &graphql.Field{Type: graphql.Int, Description: "Only counted when requested"},
+        },
+    })
+
+    imagesType := graphql.NewObject(graphql.ObjectConfig{
+        Name: "Images",
+        Fields: graphql.Fields{
+            "propertyBuilding": &graphql.Field{Type: stringList},
+            "property":         &graphql.Field{Type: stringList},
+            "room":             &graphql.Field{Type: stringList},
+        },
+    })
+
+    reviewType := graphql.NewObject(graphql.ObjectConfig{
+        Name: "Review",
+        Fields: graphql.Fields{
+            "id":          &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+            "authorName":  &graphql.Field{Type: graphql.String},
+            "cleanliness": &graphql.Field{Type: graphql.Float},
+            "location":    &graphql.Field{Type: graphql.Float},
+            "value":       &graphql.Field{Type: graphql.Float},
+            "overall":     &graphql.Field{Type: graphql.Float},
+            "text":        &graphql.Field{Type: graphql.String},
+            "language":    &graphql.Field{Type: graphql.String},
+            "createdAt":   &graphql.Field{Type: graphql.DateTime},
+        },
+    })
+
+    reviewSummaryType := graphql.NewObject(graphql.ObjectConfig{
+        Name:        "ReviewSummary",
+        Description: "Aggregate of the approved guest reviews",
+        Fields: graphql.Fields{
+            "reviewCount":  &graphql.Field{Type: graphql.Int},
+            "averageScore": &graphql.Field{Type: graphql.Float},
+            "cleanliness":  &graphql.Field{Type: graphql.Float},
+            "location":     &graphql.Field{Type: graphql.Float},
+            "value":        &graphql.Field{Type: graphql.Float},
+        },
+    })
+
+    descriptionType := graphql.NewObject(graphql.ObjectConfig{
+        Name:        "Description",
+        Description: "Stored description and upstream review summary; not fetched from upstream on demand",
+        Fields: graphql.Fields{
+            "text": &graphql.Field{
+                Type: graphql.String,
+                Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                    return p.Source.(*models.PropertyDescription).Description, nil
+                },
+            },
+            "language":    &graphql.Field{Type: graphql.String},
+            "rating":      &graphql.Field{Type: graphql.Float},
+            "reviewWord":  &graphql.Field{
+                Type: graphql.String,
+                Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                    return p.Source.(*models.PropertyDescription).Review, nil
+                },
+            },
+            "reviewCount": &graphql.Field{Type: graphql.Int},
+            "highlights": &graphql.Field{
+                Type: stringList,
+                Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                    var highlights []string
+                    if raw := p.Source.(*models.PropertyDescription).Highlights; raw != "" {
+                        if err := json.Unmarshal([]byte(raw), &highlights); err != nil {
+                            return nil, err
+                        }
+                    }
+                    return highlights, nil
+                },
+            },
+        },
+    })
+
+    locationType := graphql.NewObject(graphql.ObjectConfig{
+        Name: "Location",
+        Fields: graphql.Fields{
+            "id":          &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+            "cityName":    &graphql.Field{Type: graphql.String},
+            "country":     &graphql.Field{Type: graphql.String},
+            "countryCode": &graphql.Field{Type: graphql.String},
+            "latitude":    &graphql.Field{Type: graphql.Float},
+            "longitude":   &graphql.Field{Type: graphql.Float},
+        },
+    })
+
+    propertyType := graphql.NewObject(graphql.ObjectConfig{
+        Name: "Property",
+        Fields: graphql.Fields{
+            "destId":    &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+            "name":      &graphql.Field{Type: graphql.String},
+            "cityId":    &graphql.Field{Type: graphql.String},
+            "cityName":  &graphql.Field{Type: graphql.String},
+            "country":   &graphql.Field{Type: graphql.String},
+            "type":      &graphql.Field{Type: graphql.String},
+            "bedrooms":  &graphql.Field{Type: graphql.Int},
+            "bathrooms": &graphql.Field{Type: graphql.Int},
+            "rating":    &graphql.Field{Type: graphql.Float},
+            "price":     &graphql.Field{Type: graphql.Float},
+            "currency":  &graphql.Field{Type: graphql.String},
+            "location": &graphql.Field{
+                Type: locationType,
+                Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                    property := p.Source.(*models.Property)
+                    return loadersFrom(p).locations.Load(utils.CityLocationID(property.CityName, property.Country)), nil
+                },
+            },
+            "amenities": &graphql.Field{
+                Type: stringList,
+                Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                    return loadersFrom(p).amenities.Load(p.Source.(*models.Property).DestID), nil
+                },
+            },
+            "description": &graphql.Field{
+                Type: descriptionType,
+                Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                    return loadersFrom(p).descriptions.Load(p.Source.(*models.Property).DestID), nil
+                },
+            },
+            "images": &graphql.Field{
+                Type: imagesType,
+                Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                    description := loadersFrom(p).descriptions.Load(p.Source.(*models.Property).DestID)
+                    return func() (interface{}, error) {
+                        value, err := description()
+                        if err != nil || value.(*models.PropertyDescription) == nil {
+                            return nil, err
+                        }
+                        images := &models.CategorizedImages{}
+                        if raw := value.(*models.PropertyDescription).Images; raw != "" {
+                            if err := json.Unmarshal([]byte(raw), images); err != nil {
+                                return nil, err
+                            }
+                        }
+                        return images, nil
+                    }, nil
+                },
+            },
+            "reviews": &graphql.Field{
+                Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reviewType))),
+                Args: graphql.FieldConfigArgument{
+                    "first": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultNestedLimit},
+                    "sort": &graphql.ArgumentConfig{
+                        Type:         reviewSortType,
+                        DefaultValue: "newest",
+                    },
+                },
+                Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                    first, err := nestedLimit(p)
+                    if err != nil {
+                        return nil, err
+                    }
+                    loader := loadersFrom(p).reviewLoader(p.Args["sort"].(string), first)
+                    return loader.Load(p.Source.(*models.Property).DestID), nil
+                },
+            },
+            "reviewSummary": &graphql.Field{
+                Type: reviewSummaryType,
+                Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                    return loadersFrom(p).aggregates.Load(p.Source.(*models.Property).DestID), nil
+                },
+            },
+        },
+    })
+
+    locationType.AddFieldConfig("properties", &graphql.Field{
+        Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(propertyType))),
+        Args: graphql.FieldConfigArgument{
+            "first": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultNestedLimit},
+        },
+        Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+            first, err := nestedLimit(p)
+            if err != nil {
+                return nil, err
+            }
+            return loadersFrom(p).cityPropertyLoader(first).Load(p.Source.(*location).city), nil
+        },
+    })
+
+    locationConnection := connection("LocationConnection", locationType, pageInfoType)
+    propertyConnection := connection("PropertyConnection", propertyType, pageInfoType)
+
+    query := graphql.NewObject(graphql.ObjectConfig{
+        Name: "Query",
+        Fields: graphql.Fields{
+            "location": &graphql.Field{
+                Type: locationType,
+                Args: graphql.FieldConfigArgument{
+                    "id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+                },
+                Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                    return loadersFrom(p).locations.Load(p.Args["id"].(string)), nil
+                },
+            },
+            "locations": &graphql.Field{
+                Type: locationConnection,
+                Args: pageArgs(graphql.FieldConfigArgument{
+                    "country":  &graphql.ArgumentConfig{Type: graphql.String},
+                    "cityName": &graphql.ArgumentConfig{Type: graphql.String},
+                }),
+                Resolve: resolveLocations,
+            },
+            "property": &graphql.Field{
+                Type: propertyType,
+                Args: graphql.FieldConfigArgument{
+                    "destId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+                },
+                Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+                    return loadersFrom(p).properties.Load(p.Args["destId"].(string)), nil
+                },
+            },
+            "properties": &graphql.Field{
+                Type: propertyConnection,
+                Args: pageArgs(graphql.FieldConfigArgument{
+                    "cityId":      &graphql.ArgumentConfig{Type: graphql.String},
+                    "cityName":    &graphql.ArgumentConfig{Type: graphql.String},
+                    "country":     &graphql.ArgumentConfig{Type: graphql.String},
+                    "type":        &graphql.ArgumentConfig{Type: graphql.String},
+                    "minBedrooms": &graphql.ArgumentConfig{Type: graphql.Int},
+                    "maxBedrooms": &graphql.ArgumentConfig{Type: graphql.Int},
+                    "minRating":   &graphql.ArgumentConfig{Type: graphql.Float},
+                    "amenities":   &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+                    "sort":        &graphql.ArgumentConfig{Type: propertySortType},
+                }),
+                Resolve: resolveProperties,
+            },
+        },
+    })
+
+    return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+var stringList = graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))
+
+var reviewSortType = graphql.NewEnum(graphql.EnumConfig{
+    Name: "ReviewSort",
+    Values: graphql.EnumValueConfigMap{
+        "NEWEST":  &graphql.EnumValueConfig{Value: "newest"},
+        "OLDEST":  &graphql.EnumValueConfig{Value: "oldest"},
+        "HIGHEST": &graphql.EnumValueConfig{Value: "highest"},
+        "LOWEST":  &graphql.EnumValueConfig{Value: "lowest"},
+    },
+})
+
+var propertySortType = graphql.NewEnum(graphql.EnumConfig{
+    Name: "PropertySort",
+    Values: graphql.EnumValueConfigMap{
+        "NEWEST":     &graphql.EnumValueConfig{Value: "newest"},
+        "NAME":       &graphql.EnumValueConfig{Value: "name"},
+        "PRICE_ASC":  &graphql.EnumValueConfig{Value: "price_asc"},
+        "PRICE_DESC": &graphql.EnumValueConfig{Value: "price_desc"},
+        "RATING":     &graphql.EnumValueConfig{Value: "rating"},
+        "BEDROOMS":   &graphql.EnumValueConfig{Value: "bedrooms"},
+    },
+})
+
+// page is a resolved connection
+type page struct {
+    Items    interface{}     `json:"items"`
+    PageInfo pagination.Page `json:"pageInfo"`
+}
+
+func connection(name string, item, pageInfo *graphql.Object) *graphql.Object {
+    return graphql.NewObject(graphql.ObjectConfig{
+        Name: name,
+        Fields: graphql.Fields{
+            "items":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(item)))},
+            "pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfo)},
+        },
+    })
+}
+
+func pageArgs(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+    args["first"] = &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: pagination.DefaultLimit}
+    args["after"] = &graphql.ArgumentConfig{Type: graphql.String, Description: "Cursor of a previous page"}
+    return args
+}
+
+// pageParams validates the page arguments like the query parameters of the REST lists
+func pageParams(p graphql.ResolveParams) (pagination.Params, error) {
+    values := url.Values{"limit": {strconv.Itoa(p.Args["first"].(int))}}
+    if after, ok := p.Args["after"].(string); ok {
+        values.Set("cursor", after)
+    }
+    if selects(p.Info.FieldASTs[0].SelectionSet, "pageInfo", "totalCount") {
+        values.Set("include_total", "true")
+    }
+    params, err := pagination.ParseParams(values)
+    var appErr *apperrors.Error
+    if errors.As(err, &appErr) {
+        // Report the fields under their argument names
+        renamed := map[string]string{"limit": "first", "cursor": "after"}
+        fields := make([]apperrors.FieldError, len(appErr.Fields))
+        for i, field := range appErr.Fields {
+            if name, ok := renamed[field.Field]; ok {
+                field.Field = name
+            }
+            fields[i] = field
+        }
+        if len(fields) > 0 {
+            return params, apperrors.InvalidFields(fields...)
+        }
+    }
+    return params, err
+}
+
+func nestedLimit(p graphql.ResolveParams) (int, error) {
+    first := p.Args["first"].(int)
+    if first < 1 || first > maxNestedLimit {
+        return 0, apperrors.InvalidFields(apperrors.FieldError{Field: "first", Message: "must be between 1 and " + strconv.Itoa(maxNestedLimit)})
+    }
+    return first, nil
+}
+
+// selects reports whether a selection set selects the field at path; fragments are not followed
+func selects(set *ast.SelectionSet, path ...string) bool {
+    if set == nil || len(path) == 0 {
+        return len(path) == 0
+    }
+    for _, selection := range set.Selections {
+        if field, ok := selection.(*ast.Field); ok && field.Name.Value == path[0] {
+            if selects(field.SelectionSet, path[1:]...) {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+func resolveLocations(p graphql.ResolveParams) (interface{}, error) {
+    params, err := pageParams(p)
+    if err != nil {
+        return nil, err
+    }
+    country, _ := p.Args["country"].(string)
+    cityName, _ := p.Args["cityName"].(string)
+
+    locationService := &services.LocationService{}
+    stored, pageInfo, err := locationService.GetLocations(p.Context, params, country, cityName)
+    if err != nil {
+        return nil, err
+    }
+    localized, err := locationService.LocalizeLocations(p.Context, append([]models.Location(nil), stored...), loadersFrom(p).languages)
+    if err != nil {
+        return nil, err
+    }
+
+    locations := newLocations(stored, localized)
+    loaders := loadersFrom(p)
+    for _, l := range locations {
+        loaders.locations.Prime(l.ID, l)
+    }
+    return &page{Items: locations, PageInfo: pageInfo}, nil
+}
+
+func resolveProperties(p graphql.ResolveParams) (interface{}, error) {
+    params, err := pageParams(p)
+    if err != nil {
+        return nil, err
+    }
+
+    values := url.Values{}
+    for arg, param := range map[string]string{"cityId": "city_id", "cityName": "city_name", "country": "country", "type": "type"} {
+        if value, ok := p.Args[arg].(string); ok {
+            values.Set(param, value)
+        }
+    }
+    for arg, param := range map[string]string{"minBedrooms": "min_bedrooms", "maxBedrooms": "max_bedrooms"} {
+        if value, ok := p.Args[arg].(int); ok {
+            values.Set(param, strconv.Itoa(value))
+        }
+    }
+    if rating, ok := p.Args["minRating"].(float64); ok {
+        values.Set("rating", strconv.FormatFloat(rating, 'f', -1, 64))
+    }
+    if amenities, ok := p.Args["amenities"].([]interface{}); ok {
+        for _, amenity := range amenities {
+            values.Add("amenities", amenity.(string))
+        }
+    }
+    filter, err := models.ParsePropertyFilter(values)
+    if err != nil {
+        return nil, err
+    }
+    sort, _ := p.Args["sort"].(string)
+
+    loaders := loadersFrom(p)
+    properties, pageInfo, err := loaders.propertyService.ListProperties(p.Context, filter, sort, params)
+    if err != nil {
+        return nil, err
+    }
+
+    items := make([]*models.Property, len(properties))
+    for i := range properties {
+        items[i] = &properties[i]
+        loaders.properties.Prime(items[i].DestID, items[i])
+    }
+    return &page{Items: items, PageInfo: pageInfo}, nil
+}
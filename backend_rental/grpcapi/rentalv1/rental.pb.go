@@ -0,0 +1,1287 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: rental/v1/rental.proto
+
+// Internal gRPC interface to the rental catalogue, served next to the HTTP
+// API. Regenerate grpcapi/rentalv1 with go generate ./grpcapi after editing.
+
+package rentalv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetLocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLocationRequest) Reset() {
+	*x = GetLocationRequest{}
+	mi := &file_rental_v1_rental_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLocationRequest) ProtoMessage() {}
+
+func (x *GetLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLocationRequest.ProtoReflect.Descriptor instead.
+func (*GetLocationRequest) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetLocationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CityName      string                 `protobuf:"bytes,2,opt,name=city_name,json=cityName,proto3" json:"city_name,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	CountryCode   string                 `protobuf:"bytes,4,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	Latitude      float64                `protobuf:"fixed64,5,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,6,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_rental_v1_rental_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Location) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Location) GetCityName() string {
+	if x != nil {
+		return x.CityName
+	}
+	return ""
+}
+
+func (x *Location) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Location) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *Location) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Location) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+type ListPropertiesRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	CityId       string                 `protobuf:"bytes,1,opt,name=city_id,json=cityId,proto3" json:"city_id,omitempty"`
+	CityName     string                 `protobuf:"bytes,2,opt,name=city_name,json=cityName,proto3" json:"city_name,omitempty"`
+	Name         string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Country      string                 `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
+	Type         string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	MinBedrooms  int32                  `protobuf:"varint,6,opt,name=min_bedrooms,json=minBedrooms,proto3" json:"min_bedrooms,omitempty"`
+	MaxBedrooms  int32                  `protobuf:"varint,7,opt,name=max_bedrooms,json=maxBedrooms,proto3" json:"max_bedrooms,omitempty"`
+	MinBathrooms int32                  `protobuf:"varint,8,opt,name=min_bathrooms,json=minBathrooms,proto3" json:"min_bathrooms,omitempty"`
+	MaxBathrooms int32                  `protobuf:"varint,9,opt,name=max_bathrooms,json=maxBathrooms,proto3" json:"max_bathrooms,omitempty"`
+	MinRating    float64                `protobuf:"fixed64,10,opt,name=min_rating,json=minRating,proto3" json:"min_rating,omitempty"`
+	Amenities    []string               `protobuf:"bytes,11,rep,name=amenities,proto3" json:"amenities,omitempty"`
+	// One of newest, name, price_asc, price_desc, rating or bedrooms
+	Sort string `protobuf:"bytes,12,opt,name=sort,proto3" json:"sort,omitempty"`
+	// Page size, 20 when unset
+	Limit int32 `protobuf:"varint,13,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Cursor of a previous page
+	Cursor       string `protobuf:"bytes,14,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	IncludeTotal bool   `protobuf:"varint,15,opt,name=include_total,json=includeTotal,proto3" json:"include_total,omitempty"`
+	// Adds display prices converted to this currency
+	Currency      string `protobuf:"bytes,16,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPropertiesRequest) Reset() {
+	*x = ListPropertiesRequest{}
+	mi := &file_rental_v1_rental_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPropertiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPropertiesRequest) ProtoMessage() {}
+
+func (x *ListPropertiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPropertiesRequest.ProtoReflect.Descriptor instead.
+func (*ListPropertiesRequest) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListPropertiesRequest) GetCityId() string {
+	if x != nil {
+		return x.CityId
+	}
+	return ""
+}
+
+func (x *ListPropertiesRequest) GetCityName() string {
+	if x != nil {
+		return x.CityName
+	}
+	return ""
+}
+
+func (x *ListPropertiesRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListPropertiesRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *ListPropertiesRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ListPropertiesRequest) GetMinBedrooms() int32 {
+	if x != nil {
+		return x.MinBedrooms
+	}
+	return 0
+}
+
+func (x *ListPropertiesRequest) GetMaxBedrooms() int32 {
+	if x != nil {
+		return x.MaxBedrooms
+	}
+	return 0
+}
+
+func (x *ListPropertiesRequest) GetMinBathrooms() int32 {
+	if x != nil {
+		return x.MinBathrooms
+	}
+	return 0
+}
+
+func (x *ListPropertiesRequest) GetMaxBathrooms() int32 {
+	if x != nil {
+		return x.MaxBathrooms
+	}
+	return 0
+}
+
+func (x *ListPropertiesRequest) GetMinRating() float64 {
+	if x != nil {
+		return x.MinRating
+	}
+	return 0
+}
+
+func (x *ListPropertiesRequest) GetAmenities() []string {
+	if x != nil {
+		return x.Amenities
+	}
+	return nil
+}
+
+func (x *ListPropertiesRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListPropertiesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListPropertiesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListPropertiesRequest) GetIncludeTotal() bool {
+	if x != nil {
+		return x.IncludeTotal
+	}
+	return false
+}
+
+func (x *ListPropertiesRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type ListPropertiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Properties    []*Property            `protobuf:"bytes,1,rep,name=properties,proto3" json:"properties,omitempty"`
+	Page          *PageInfo              `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPropertiesResponse) Reset() {
+	*x = ListPropertiesResponse{}
+	mi := &file_rental_v1_rental_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPropertiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPropertiesResponse) ProtoMessage() {}
+
+func (x *ListPropertiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPropertiesResponse.ProtoReflect.Descriptor instead.
+func (*ListPropertiesResponse) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListPropertiesResponse) GetProperties() []*Property {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *ListPropertiesResponse) GetPage() *PageInfo {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+type PageInfo struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	NextCursor string                 `protobuf:"bytes,1,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	PrevCursor string                 `protobuf:"bytes,2,opt,name=prev_cursor,json=prevCursor,proto3" json:"prev_cursor,omitempty"`
+	Limit      int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Only set when include_total was requested
+	TotalCount    *int64 `protobuf:"varint,4,opt,name=total_count,json=totalCount,proto3,oneof" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageInfo) Reset() {
+	*x = PageInfo{}
+	mi := &file_rental_v1_rental_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageInfo) ProtoMessage() {}
+
+func (x *PageInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageInfo.ProtoReflect.Descriptor instead.
+func (*PageInfo) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PageInfo) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *PageInfo) GetPrevCursor() string {
+	if x != nil {
+		return x.PrevCursor
+	}
+	return ""
+}
+
+func (x *PageInfo) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *PageInfo) GetTotalCount() int64 {
+	if x != nil && x.TotalCount != nil {
+		return *x.TotalCount
+	}
+	return 0
+}
+
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        float64                `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_rental_v1_rental_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Money) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Property struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DestId        string                 `protobuf:"bytes,1,opt,name=dest_id,json=destId,proto3" json:"dest_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CityId        string                 `protobuf:"bytes,3,opt,name=city_id,json=cityId,proto3" json:"city_id,omitempty"`
+	CityName      string                 `protobuf:"bytes,4,opt,name=city_name,json=cityName,proto3" json:"city_name,omitempty"`
+	Country       string                 `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
+	Type          string                 `protobuf:"bytes,6,opt,name=type,proto3" json:"type,omitempty"`
+	Bedrooms      int32                  `protobuf:"varint,7,opt,name=bedrooms,proto3" json:"bedrooms,omitempty"`
+	Bathrooms     int32                  `protobuf:"varint,8,opt,name=bathrooms,proto3" json:"bathrooms,omitempty"`
+	Rating        float64                `protobuf:"fixed64,9,opt,name=rating,proto3" json:"rating,omitempty"`
+	Price         float64                `protobuf:"fixed64,10,opt,name=price,proto3" json:"price,omitempty"`
+	Currency      string                 `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`
+	DisplayPrice  *Money                 `protobuf:"bytes,12,opt,name=display_price,json=displayPrice,proto3" json:"display_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Property) Reset() {
+	*x = Property{}
+	mi := &file_rental_v1_rental_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Property) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Property) ProtoMessage() {}
+
+func (x *Property) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Property.ProtoReflect.Descriptor instead.
+func (*Property) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Property) GetDestId() string {
+	if x != nil {
+		return x.DestId
+	}
+	return ""
+}
+
+func (x *Property) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Property) GetCityId() string {
+	if x != nil {
+		return x.CityId
+	}
+	return ""
+}
+
+func (x *Property) GetCityName() string {
+	if x != nil {
+		return x.CityName
+	}
+	return ""
+}
+
+func (x *Property) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Property) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Property) GetBedrooms() int32 {
+	if x != nil {
+		return x.Bedrooms
+	}
+	return 0
+}
+
+func (x *Property) GetBathrooms() int32 {
+	if x != nil {
+		return x.Bathrooms
+	}
+	return 0
+}
+
+func (x *Property) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Property) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Property) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Property) GetDisplayPrice() *Money {
+	if x != nil {
+		return x.DisplayPrice
+	}
+	return nil
+}
+
+type GetPropertyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DestId        string                 `protobuf:"bytes,1,opt,name=dest_id,json=destId,proto3" json:"dest_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPropertyRequest) Reset() {
+	*x = GetPropertyRequest{}
+	mi := &file_rental_v1_rental_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPropertyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPropertyRequest) ProtoMessage() {}
+
+func (x *GetPropertyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPropertyRequest.ProtoReflect.Descriptor instead.
+func (*GetPropertyRequest) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetPropertyRequest) GetDestId() string {
+	if x != nil {
+		return x.DestId
+	}
+	return ""
+}
+
+type PropertyDetail struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Property *Property              `protobuf:"bytes,1,opt,name=property,proto3" json:"property,omitempty"`
+	// Unset when the description has not been fetched from upstream
+	Description *Description `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Amenities   []string     `protobuf:"bytes,3,rep,name=amenities,proto3" json:"amenities,omitempty"`
+	// Unset when the property has no approved reviews
+	GuestReviews  *ReviewAggregate `protobuf:"bytes,4,opt,name=guest_reviews,json=guestReviews,proto3" json:"guest_reviews,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PropertyDetail) Reset() {
+	*x = PropertyDetail{}
+	mi := &file_rental_v1_rental_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PropertyDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PropertyDetail) ProtoMessage() {}
+
+func (x *PropertyDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PropertyDetail.ProtoReflect.Descriptor instead.
+func (*PropertyDetail) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PropertyDetail) GetProperty() *Property {
+	if x != nil {
+		return x.Property
+	}
+	return nil
+}
+
+func (x *PropertyDetail) GetDescription() *Description {
+	if x != nil {
+		return x.Description
+	}
+	return nil
+}
+
+func (x *PropertyDetail) GetAmenities() []string {
+	if x != nil {
+		return x.Amenities
+	}
+	return nil
+}
+
+func (x *PropertyDetail) GetGuestReviews() *ReviewAggregate {
+	if x != nil {
+		return x.GuestReviews
+	}
+	return nil
+}
+
+type Description struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Language      string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Rating        float64                `protobuf:"fixed64,3,opt,name=rating,proto3" json:"rating,omitempty"`
+	Review        string                 `protobuf:"bytes,4,opt,name=review,proto3" json:"review,omitempty"`
+	ReviewCount   int32                  `protobuf:"varint,5,opt,name=review_count,json=reviewCount,proto3" json:"review_count,omitempty"`
+	Highlights    []string               `protobuf:"bytes,6,rep,name=highlights,proto3" json:"highlights,omitempty"`
+	Images        *Images                `protobuf:"bytes,7,opt,name=images,proto3" json:"images,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Description) Reset() {
+	*x = Description{}
+	mi := &file_rental_v1_rental_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Description) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Description) ProtoMessage() {}
+
+func (x *Description) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Description.ProtoReflect.Descriptor instead.
+func (*Description) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Description) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Description) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *Description) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Description) GetReview() string {
+	if x != nil {
+		return x.Review
+	}
+	return ""
+}
+
+func (x *Description) GetReviewCount() int32 {
+	if x != nil {
+		return x.ReviewCount
+	}
+	return 0
+}
+
+func (x *Description) GetHighlights() []string {
+	if x != nil {
+		return x.Highlights
+	}
+	return nil
+}
+
+func (x *Description) GetImages() *Images {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+type Images struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PropertyBuilding []string               `protobuf:"bytes,1,rep,name=property_building,json=propertyBuilding,proto3" json:"property_building,omitempty"`
+	Property         []string               `protobuf:"bytes,2,rep,name=property,proto3" json:"property,omitempty"`
+	Room             []string               `protobuf:"bytes,3,rep,name=room,proto3" json:"room,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Images) Reset() {
+	*x = Images{}
+	mi := &file_rental_v1_rental_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Images) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Images) ProtoMessage() {}
+
+func (x *Images) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Images.ProtoReflect.Descriptor instead.
+func (*Images) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Images) GetPropertyBuilding() []string {
+	if x != nil {
+		return x.PropertyBuilding
+	}
+	return nil
+}
+
+func (x *Images) GetProperty() []string {
+	if x != nil {
+		return x.Property
+	}
+	return nil
+}
+
+func (x *Images) GetRoom() []string {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+type ReviewAggregate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewCount   int32                  `protobuf:"varint,1,opt,name=review_count,json=reviewCount,proto3" json:"review_count,omitempty"`
+	AverageScore  float64                `protobuf:"fixed64,2,opt,name=average_score,json=averageScore,proto3" json:"average_score,omitempty"`
+	Cleanliness   float64                `protobuf:"fixed64,3,opt,name=cleanliness,proto3" json:"cleanliness,omitempty"`
+	Location      float64                `protobuf:"fixed64,4,opt,name=location,proto3" json:"location,omitempty"`
+	Value         float64                `protobuf:"fixed64,5,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewAggregate) Reset() {
+	*x = ReviewAggregate{}
+	mi := &file_rental_v1_rental_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewAggregate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewAggregate) ProtoMessage() {}
+
+func (x *ReviewAggregate) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewAggregate.ProtoReflect.Descriptor instead.
+func (*ReviewAggregate) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReviewAggregate) GetReviewCount() int32 {
+	if x != nil {
+		return x.ReviewCount
+	}
+	return 0
+}
+
+func (x *ReviewAggregate) GetAverageScore() float64 {
+	if x != nil {
+		return x.AverageScore
+	}
+	return 0
+}
+
+func (x *ReviewAggregate) GetCleanliness() float64 {
+	if x != nil {
+		return x.Cleanliness
+	}
+	return 0
+}
+
+func (x *ReviewAggregate) GetLocation() float64 {
+	if x != nil {
+		return x.Location
+	}
+	return 0
+}
+
+func (x *ReviewAggregate) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type WatchCrawlRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchCrawlRequest) Reset() {
+	*x = WatchCrawlRequest{}
+	mi := &file_rental_v1_rental_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchCrawlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCrawlRequest) ProtoMessage() {}
+
+func (x *WatchCrawlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCrawlRequest.ProtoReflect.Descriptor instead.
+func (*WatchCrawlRequest) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WatchCrawlRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CrawlRun struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TriggeredBy string                 `protobuf:"bytes,2,opt,name=triggered_by,json=triggeredBy,proto3" json:"triggered_by,omitempty"`
+	// One of running, succeeded or failed
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrawlRun) Reset() {
+	*x = CrawlRun{}
+	mi := &file_rental_v1_rental_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrawlRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrawlRun) ProtoMessage() {}
+
+func (x *CrawlRun) ProtoReflect() protoreflect.Message {
+	mi := &file_rental_v1_rental_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrawlRun.ProtoReflect.Descriptor instead.
+func (*CrawlRun) Descriptor() ([]byte, []int) {
+	return file_rental_v1_rental_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CrawlRun) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CrawlRun) GetTriggeredBy() string {
+	if x != nil {
+		return x.TriggeredBy
+	}
+	return ""
+}
+
+func (x *CrawlRun) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CrawlRun) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CrawlRun) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *CrawlRun) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+var File_rental_v1_rental_proto protoreflect.FileDescriptor
+
+const file_rental_v1_rental_proto_rawDesc = "" +
+	"\n" +
+	"\x16rental/v1/rental.proto\x12\trental.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"$\n" +
+	"\x12GetLocationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xae\x01\n" +
+	"\bLocation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tcity_name\x18\x02 \x01(\tR\bcityName\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12!\n" +
+	"\fcountry_code\x18\x04 \x01(\tR\vcountryCode\x12\x1a\n" +
+	"\blatitude\x18\x05 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x06 \x01(\x01R\tlongitude\"\xdf\x03\n" +
+	"\x15ListPropertiesRequest\x12\x17\n" +
+	"\acity_id\x18\x01 \x01(\tR\x06cityId\x12\x1b\n" +
+	"\tcity_name\x18\x02 \x01(\tR\bcityName\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12!\n" +
+	"\fmin_bedrooms\x18\x06 \x01(\x05R\vminBedrooms\x12!\n" +
+	"\fmax_bedrooms\x18\a \x01(\x05R\vmaxBedrooms\x12#\n" +
+	"\rmin_bathrooms\x18\b \x01(\x05R\fminBathrooms\x12#\n" +
+	"\rmax_bathrooms\x18\t \x01(\x05R\fmaxBathrooms\x12\x1d\n" +
+	"\n" +
+	"min_rating\x18\n" +
+	" \x01(\x01R\tminRating\x12\x1c\n" +
+	"\tamenities\x18\v \x03(\tR\tamenities\x12\x12\n" +
+	"\x04sort\x18\f \x01(\tR\x04sort\x12\x14\n" +
+	"\x05limit\x18\r \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x0e \x01(\tR\x06cursor\x12#\n" +
+	"\rinclude_total\x18\x0f \x01(\bR\fincludeTotal\x12\x1a\n" +
+	"\bcurrency\x18\x10 \x01(\tR\bcurrency\"v\n" +
+	"\x16ListPropertiesResponse\x123\n" +
+	"\n" +
+	"properties\x18\x01 \x03(\v2\x13.rental.v1.PropertyR\n" +
+	"properties\x12'\n" +
+	"\x04page\x18\x02 \x01(\v2\x13.rental.v1.PageInfoR\x04page\"\x98\x01\n" +
+	"\bPageInfo\x12\x1f\n" +
+	"\vnext_cursor\x18\x01 \x01(\tR\n" +
+	"nextCursor\x12\x1f\n" +
+	"\vprev_cursor\x18\x02 \x01(\tR\n" +
+	"prevCursor\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12$\n" +
+	"\vtotal_count\x18\x04 \x01(\x03H\x00R\n" +
+	"totalCount\x88\x01\x01B\x0e\n" +
+	"\f_total_count\";\n" +
+	"\x05Money\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\xd6\x02\n" +
+	"\bProperty\x12\x17\n" +
+	"\adest_id\x18\x01 \x01(\tR\x06destId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x17\n" +
+	"\acity_id\x18\x03 \x01(\tR\x06cityId\x12\x1b\n" +
+	"\tcity_name\x18\x04 \x01(\tR\bcityName\x12\x18\n" +
+	"\acountry\x18\x05 \x01(\tR\acountry\x12\x12\n" +
+	"\x04type\x18\x06 \x01(\tR\x04type\x12\x1a\n" +
+	"\bbedrooms\x18\a \x01(\x05R\bbedrooms\x12\x1c\n" +
+	"\tbathrooms\x18\b \x01(\x05R\tbathrooms\x12\x16\n" +
+	"\x06rating\x18\t \x01(\x01R\x06rating\x12\x14\n" +
+	"\x05price\x18\n" +
+	" \x01(\x01R\x05price\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrency\x125\n" +
+	"\rdisplay_price\x18\f \x01(\v2\x10.rental.v1.MoneyR\fdisplayPrice\"-\n" +
+	"\x12GetPropertyRequest\x12\x17\n" +
+	"\adest_id\x18\x01 \x01(\tR\x06destId\"\xda\x01\n" +
+	"\x0ePropertyDetail\x12/\n" +
+	"\bproperty\x18\x01 \x01(\v2\x13.rental.v1.PropertyR\bproperty\x128\n" +
+	"\vdescription\x18\x02 \x01(\v2\x16.rental.v1.DescriptionR\vdescription\x12\x1c\n" +
+	"\tamenities\x18\x03 \x03(\tR\tamenities\x12?\n" +
+	"\rguest_reviews\x18\x04 \x01(\v2\x1a.rental.v1.ReviewAggregateR\fguestReviews\"\xdb\x01\n" +
+	"\vDescription\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x16\n" +
+	"\x06rating\x18\x03 \x01(\x01R\x06rating\x12\x16\n" +
+	"\x06review\x18\x04 \x01(\tR\x06review\x12!\n" +
+	"\freview_count\x18\x05 \x01(\x05R\vreviewCount\x12\x1e\n" +
+	"\n" +
+	"highlights\x18\x06 \x03(\tR\n" +
+	"highlights\x12)\n" +
+	"\x06images\x18\a \x01(\v2\x11.rental.v1.ImagesR\x06images\"e\n" +
+	"\x06Images\x12+\n" +
+	"\x11property_building\x18\x01 \x03(\tR\x10propertyBuilding\x12\x1a\n" +
+	"\bproperty\x18\x02 \x03(\tR\bproperty\x12\x12\n" +
+	"\x04room\x18\x03 \x03(\tR\x04room\"\xad\x01\n" +
+	"\x0fReviewAggregate\x12!\n" +
+	"\freview_count\x18\x01 \x01(\x05R\vreviewCount\x12#\n" +
+	"\raverage_score\x18\x02 \x01(\x01R\faverageScore\x12 \n" +
+	"\vcleanliness\x18\x03 \x01(\x01R\vcleanliness\x12\x1a\n" +
+	"\blocation\x18\x04 \x01(\x01R\blocation\x12\x14\n" +
+	"\x05value\x18\x05 \x01(\x01R\x05value\"#\n" +
+	"\x11WatchCrawlRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\xe3\x01\n" +
+	"\bCrawlRun\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12!\n" +
+	"\ftriggered_by\x18\x02 \x01(\tR\vtriggeredBy\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"started_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt2\xb5\x02\n" +
+	"\rRentalService\x12A\n" +
+	"\vGetLocation\x12\x1d.rental.v1.GetLocationRequest\x1a\x13.rental.v1.Location\x12U\n" +
+	"\x0eListProperties\x12 .rental.v1.ListPropertiesRequest\x1a!.rental.v1.ListPropertiesResponse\x12G\n" +
+	"\vGetProperty\x12\x1d.rental.v1.GetPropertyRequest\x1a\x19.rental.v1.PropertyDetail\x12A\n" +
+	"\n" +
+	"WatchCrawl\x12\x1c.rental.v1.WatchCrawlRequest\x1a\x13.rental.v1.CrawlRun0\x01B*Z(backend_rental/grpcapi/rentalv1;rentalv1b\x06proto3"
+
+var (
+	file_rental_v1_rental_proto_rawDescOnce sync.Once
+	file_rental_v1_rental_proto_rawDescData []byte
+)
+
+func file_rental_v1_rental_proto_rawDescGZIP() []byte {
+	file_rental_v1_rental_proto_rawDescOnce.Do(func() {
+		file_rental_v1_rental_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rental_v1_rental_proto_rawDesc), len(file_rental_v1_rental_proto_rawDesc)))
+	})
+	return file_rental_v1_rental_proto_rawDescData
+}
+
+var file_rental_v1_rental_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_rental_v1_rental_proto_goTypes = []any{
+	(*GetLocationRequest)(nil),     // 0: rental.v1.GetLocationRequest
+	(*Location)(nil),               // 1: rental.v1.Location
+	(*ListPropertiesRequest)(nil),  // 2: rental.v1.ListPropertiesRequest
+	(*ListPropertiesResponse)(nil), // 3: rental.v1.ListPropertiesResponse
+	(*PageInfo)(nil),               // 4: rental.v1.PageInfo
+	(*Money)(nil),                  // 5: rental.v1.Money
+	(*Property)(nil),               // 6: rental.v1.Property
+	(*GetPropertyRequest)(nil),     // 7: rental.v1.GetPropertyRequest
+	(*PropertyDetail)(nil),         // 8: rental.v1.PropertyDetail
+	(*Description)(nil),            // 9: rental.v1.Description
+	(*Images)(nil),                 // 10: rental.v1.Images
+	(*ReviewAggregate)(nil),        // 11: rental.v1.ReviewAggregate
+	(*WatchCrawlRequest)(nil),      // 12: rental.v1.WatchCrawlRequest
+	(*CrawlRun)(nil),               // 13: rental.v1.CrawlRun
+	(*timestamppb.Timestamp)(nil),  // 14: google.protobuf.Timestamp
+}
+var file_rental_v1_rental_proto_depIdxs = []int32{
+	6,  // 0: rental.v1.ListPropertiesResponse.properties:type_name -> rental.v1.Property
+	4,  // 1: rental.v1.ListPropertiesResponse.page:type_name -> rental.v1.PageInfo
+	5,  // 2: rental.v1.Property.display_price:type_name -> rental.v1.Money
+	6,  // 3: rental.v1.PropertyDetail.property:type_name -> rental.v1.Property
+	9,  // 4: rental.v1.PropertyDetail.description:type_name -> rental.v1.Description
+	11, // 5: rental.v1.PropertyDetail.guest_reviews:type_name -> rental.v1.ReviewAggregate
+	10, // 6: rental.v1.Description.images:type_name -> rental.v1.Images
+	14, // 7: rental.v1.CrawlRun.started_at:type_name -> google.protobuf.Timestamp
+	14, // 8: rental.v1.CrawlRun.finished_at:type_name -> google.protobuf.Timestamp
+	0,  // 9: rental.v1.RentalService.GetLocation:input_type -> rental.v1.GetLocationRequest
+	2,  // 10: rental.v1.RentalService.ListProperties:input_type -> rental.v1.ListPropertiesRequest
+	7,  // 11: rental.v1.RentalService.GetProperty:input_type -> rental.v1.GetPropertyRequest
+	12, // 12: rental.v1.RentalService.WatchCrawl:input_type -> rental.v1.WatchCrawlRequest
+	1,  // 13: rental.v1.RentalService.GetLocation:output_type -> rental.v1.Location
+	3,  // 14: rental.v1.RentalService.ListProperties:output_type -> rental.v1.ListPropertiesResponse
+	8,  // 15: rental.v1.RentalService.GetProperty:output_type -> rental.v1.PropertyDetail
+	13, // 16: rental.v1.RentalService.WatchCrawl:output_type -> rental.v1.CrawlRun
+	13, // [13:17] is the sub-list for method output_type
+	9,  // [9:13] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_rental_v1_rental_proto_init() }
+func file_rental_v1_rental_proto_init() {
+	if File_rental_v1_rental_proto != nil {
+		return
+	}
+	file_rental_v1_rental_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rental_v1_rental_proto_rawDesc), len(file_rental_v1_rental_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rental_v1_rental_proto_goTypes,
+		DependencyIndexes: file_rental_v1_rental_proto_depIdxs,
+		MessageInfos:      file_rental_v1_rental_proto_msgTypes,
+	}.Build()
+	File_rental_v1_rental_proto = out.File
+	file_rental_v1_rental_proto_goTypes = nil
+	file_rental_v1_rental_proto_depIdxs = nil
+}
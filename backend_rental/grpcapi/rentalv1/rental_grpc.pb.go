@@ -0,0 +1,250 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: rental/v1/rental.proto
+
+// Internal gRPC interface to the rental catalogue, served next to the HTTP
+// API. Regenerate grpcapi/rentalv1 with go generate ./grpcapi after editing.
+
+package rentalv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RentalService_GetLocation_FullMethodName    = "/rental.v1.RentalService/GetLocation"
+	RentalService_ListProperties_FullMethodName = "/rental.v1.RentalService/ListProperties"
+	RentalService_GetProperty_FullMethodName    = "/rental.v1.RentalService/GetProperty"
+	RentalService_WatchCrawl_FullMethodName     = "/rental.v1.RentalService/WatchCrawl"
+)
+
+// RentalServiceClient is the client API for RentalService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RentalServiceClient interface {
+	// GetLocation returns a location localized for the accept-language metadata
+	GetLocation(ctx context.Context, in *GetLocationRequest, opts ...grpc.CallOption) (*Location, error)
+	// ListProperties pages the properties matching a filter
+	ListProperties(ctx context.Context, in *ListPropertiesRequest, opts ...grpc.CallOption) (*ListPropertiesResponse, error)
+	// GetProperty returns a property with its description, amenities and review summary
+	GetProperty(ctx context.Context, in *GetPropertyRequest, opts ...grpc.CallOption) (*PropertyDetail, error)
+	// WatchCrawl streams the state of a crawl run until it finishes
+	WatchCrawl(ctx context.Context, in *WatchCrawlRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CrawlRun], error)
+}
+
+type rentalServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRentalServiceClient(cc grpc.ClientConnInterface) RentalServiceClient {
+	return &rentalServiceClient{cc}
+}
+
+func (c *rentalServiceClient) GetLocation(ctx context.Context, in *GetLocationRequest, opts ...grpc.CallOption) (*Location, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Location)
+	err := c.cc.Invoke(ctx, RentalService_GetLocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) ListProperties(ctx context.Context, in *ListPropertiesRequest, opts ...grpc.CallOption) (*ListPropertiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPropertiesResponse)
+	err := c.cc.Invoke(ctx, RentalService_ListProperties_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) GetProperty(ctx context.Context, in *GetPropertyRequest, opts ...grpc.CallOption) (*PropertyDetail, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PropertyDetail)
+	err := c.cc.Invoke(ctx, RentalService_GetProperty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rentalServiceClient) WatchCrawl(ctx context.Context, in *WatchCrawlRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CrawlRun], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RentalService_ServiceDesc.Streams[0], RentalService_WatchCrawl_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchCrawlRequest, CrawlRun]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RentalService_WatchCrawlClient = grpc.ServerStreamingClient[CrawlRun]
+
+// RentalServiceServer is the server API for RentalService service.
+// All implementations must embed UnimplementedRentalServiceServer
+// for forward compatibility.
+type RentalServiceServer interface {
+	// GetLocation returns a location localized for the accept-language metadata
+	GetLocation(context.Context, *GetLocationRequest) (*Location, error)
+	// ListProperties pages the properties matching a filter
+	ListProperties(context.Context, *ListPropertiesRequest) (*ListPropertiesResponse, error)
+	// GetProperty returns a property with its description, amenities and review summary
+	GetProperty(context.Context, *GetPropertyRequest) (*PropertyDetail, error)
+	// WatchCrawl streams the state of a crawl run until it finishes
+	WatchCrawl(*WatchCrawlRequest, grpc.ServerStreamingServer[CrawlRun]) error
+	mustEmbedUnimplementedRentalServiceServer()
+}
+
+// UnimplementedRentalServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRentalServiceServer struct{}
+
+func (UnimplementedRentalServiceServer) GetLocation(context.Context, *GetLocationRequest) (*Location, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLocation not implemented")
+}
+func (UnimplementedRentalServiceServer) ListProperties(context.Context, *ListPropertiesRequest) (*ListPropertiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProperties not implemented")
+}
+func (UnimplementedRentalServiceServer) GetProperty(context.Context, *GetPropertyRequest) (*PropertyDetail, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProperty not implemented")
+}
+func (UnimplementedRentalServiceServer) WatchCrawl(*WatchCrawlRequest, grpc.ServerStreamingServer[CrawlRun]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCrawl not implemented")
+}
+func (UnimplementedRentalServiceServer) mustEmbedUnimplementedRentalServiceServer() {}
+func (UnimplementedRentalServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeRentalServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RentalServiceServer will
+// result in compilation errors.
+type UnsafeRentalServiceServer interface {
+	mustEmbedUnimplementedRentalServiceServer()
+}
+
+func RegisterRentalServiceServer(s grpc.ServiceRegistrar, srv RentalServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRentalServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RentalService_ServiceDesc, srv)
+}
+
+func _RentalService_GetLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).GetLocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RentalService_GetLocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).GetLocation(ctx, req.(*GetLocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_ListProperties_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPropertiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).ListProperties(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RentalService_ListProperties_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).ListProperties(ctx, req.(*ListPropertiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_GetProperty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPropertyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RentalServiceServer).GetProperty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RentalService_GetProperty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RentalServiceServer).GetProperty(ctx, req.(*GetPropertyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RentalService_WatchCrawl_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCrawlRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RentalServiceServer).WatchCrawl(m, &grpc.GenericServerStream[WatchCrawlRequest, CrawlRun]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RentalService_WatchCrawlServer = grpc.ServerStreamingServer[CrawlRun]
+
+// RentalService_ServiceDesc is the grpc.ServiceDesc for RentalService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RentalService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rental.v1.RentalService",
+	HandlerType: (*RentalServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLocation",
+			Handler:    _RentalService_GetLocation_Handler,
+		},
+		{
+			MethodName: "ListProperties",
+			Handler:    _RentalService_ListProperties_Handler,
+		},
+		{
+			MethodName: "GetProperty",
+			Handler:    _RentalService_GetProperty_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCrawl",
+			Handler:       _RentalService_WatchCrawl_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rental/v1/rental.proto",
+}
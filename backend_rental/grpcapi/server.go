@@ -0,0 +1,429 @@
+// Package grpcapi serves the rental.v1 gRPC interface for internal consumers.
+// It runs next to the Beego HTTP server and calls the same services as the
+// controllers; errors are mapped from their apperrors kind to gRPC codes.
+package grpcapi
+
+//go:generate protoc -I ../proto --go_out=.. --go_opt=module=backend_rental --go-grpc_out=.. --go-grpc_opt=module=backend_rental rental/v1/rental.proto
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log/slog"
+    "net"
+    "net/url"
+    "strconv"
+    "strings"
+
+    "backend_rental/grpcapi/rentalv1"
+    "backend_rental/middleware"
+    "backend_rental/models"
+    "backend_rental/services"
+    "backend_rental/utils"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/pagination"
+    beego "github.com/beego/beego/v2/server/web"
+    "google.golang.org/genproto/googleapis/rpc/errdetails"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements rentalv1.RentalServiceServer
+type Server struct {
+    rentalv1.UnimplementedRentalServiceServer
+    properties services.PropertyServiceInterface
+}
+
+// NewServer creates a server backed by properties
+func NewServer(properties services.PropertyServiceInterface) *Server {
+    return &Server{properties: properties}
+}
+
+// NewGRPCServer creates a gRPC server with the rental service and the error
+// and language interceptors registered
+func NewGRPCServer(properties services.PropertyServiceInterface) *grpc.Server {
+    server := grpc.NewServer(
+        grpc.ChainUnaryInterceptor(unaryInterceptor),
+        grpc.ChainStreamInterceptor(streamInterceptor),
+    )
+    rentalv1.RegisterRentalServiceServer(server, NewServer(properties))
+    return server
+}
+
+// ListenAndServe listens on addr and serves the rental service in the
+// background; the caller stops the returned server on shutdown
+func ListenAndServe(addr string) (*grpc.Server, error) {
+    listener, err := net.Listen("tcp", addr)
+    if err != nil {
+        return nil, err
+    }
+    rapidAPIKey, _ := beego.AppConfig.String("rapidapikey")
+    server := NewGRPCServer(services.NewPropertyService(utils.GetDB(), rapidAPIKey))
+    slog.Info("gRPC server listening", "addr", addr)
+    go func() {
+        if err := server.Serve(listener); err != nil {
+            slog.Error("gRPC server stopped", "error", err)
+        }
+    }()
+    return server, nil
+}
+
+// GracefulStop stops server once its in-flight calls finish, or cancels
+// them when ctx is done first
+func GracefulStop(ctx context.Context, server *grpc.Server) {
+    stopped := make(chan struct{})
+    go func() {
+        server.GracefulStop()
+        close(stopped)
+    }()
+    select {
+    case <-stopped:
+    case <-ctx.Done():
+        server.Stop()
+        <-stopped
+    }
+}
+
+// GetLocation returns a localized location
+func (s *Server) GetLocation(ctx context.Context, req *rentalv1.GetLocationRequest) (*rentalv1.Location, error) {
+    if req.GetId() == "" {
+        return nil, apperrors.InvalidFields(apperrors.FieldError{Field: "id", Message: "is required"})
+    }
+    location, err := (&services.LocationService{}).GetLocalizedLocation(ctx, req.GetId(), languages(ctx))
+    if err != nil {
+        return nil, err
+    }
+    return &rentalv1.Location{
+        Id:          location.ID,
+        CityName:    location.CityName,
+        Country:     location.Country,
+        CountryCode: location.CountryCode,
+        Latitude:    location.Latitude,
+        Longitude:   location.Longitude,
+    }, nil
+}
+
+// ListProperties pages the properties matching a filter; the request is
+// validated like the query parameters of /v1/property/list
+func (s *Server) ListProperties(ctx context.Context, req *rentalv1.ListPropertiesRequest) (*rentalv1.ListPropertiesResponse, error) {
+    values := url.Values{}
+    set := func(name, value string) {
+        if value != "" {
+            values.Set(name, value)
+        }
+    }
+    setInt := func(name string, value int32) {
+        if value != 0 {
+            values.Set(name, strconv.Itoa(int(value)))
+        }
+    }
+    set("city_id", req.GetCityId())
+    set("city_name", req.GetCityName())
+    set("name", req.GetName())
+    set("country", req.GetCountry())
+    set("type", req.GetType())
+    setInt("min_bedrooms", req.GetMinBedrooms())
+    setInt("max_bedrooms", req.GetMaxBedrooms())
+    setInt("min_bathrooms", req.GetMinBathrooms())
+    setInt("max_bathrooms", req.GetMaxBathrooms())
+    if req.GetMinRating() != 0 {
+        values.Set("rating", strconv.FormatFloat(req.GetMinRating(), 'f', -1, 64))
+    }
+    set("amenities", strings.Join(req.GetAmenities(), ","))
+    filter, err := models.ParsePropertyFilter(values)
+    if err != nil {
+        return nil, err
+    }
+
+    pageValues := url.Values{}
+    if req.GetLimit() != 0 {
+        pageValues.Set("limit", strconv.Itoa(int(req.GetLimit())))
+    }
+    if req.GetCursor() != "" {
+        pageValues.Set("cursor", req.GetCursor())
+    }
+    if req.GetIncludeTotal() {
+        pageValues.Set("include_total", "true")
+    }
+    params, err := pagination.ParseParams(pageValues)
+    if err != nil {
+        return nil, err
+    }
+
+    currency := strings.ToUpper(req.GetCurrency())
+    currencyService := services.NewCurrencyService()
+    if currency != "" && !currencyService.IsSupportedCurrency(currency) {
+        return nil, apperrors.InvalidFields(apperrors.FieldError{Field: "currency", Message: "is not supported"})
+    }
+
+    properties, page, err := s.properties.ListProperties(ctx, filter, req.GetSort(), params)
+    if err != nil {
+        return nil, err
+    }
+    if currency != "" {
+        if err := currencyService.ApplyDisplayPrices(properties, currency); err != nil {
+            return nil, err
+        }
+    }
+
+    response := &rentalv1.ListPropertiesResponse{
+        Properties: make([]*rentalv1.Property, len(properties)),
+        Page: &rentalv1.PageInfo{
+            NextCursor: page.NextCursor,
+            PrevCursor: page.PrevCursor,
+            Limit:      int32(page.Limit),
+            TotalCount: page.TotalCount,
+        },
+    }
+    for i := range properties {
+        response.Properties[i] = toProperty(&properties[i])
+    }
+    return response, nil
+}
+
+// GetProperty returns a property with its description, amenities and review summary
+func (s *Server) GetProperty(ctx context.Context, req *rentalv1.GetPropertyRequest) (*rentalv1.PropertyDetail, error) {
+    destID := req.GetDestId()
+    if destID == "" {
+        return nil, apperrors.InvalidFields(apperrors.FieldError{Field: "dest_id", Message: "is required"})
+    }
+
+    properties, err := s.properties.GetPropertiesByDestIDs(ctx, []string{destID})
+    if err != nil {
+        return nil, err
+    }
+    if len(properties) == 0 {
+        return nil, apperrors.NotFound("property %s not found", destID)
+    }
+    detail := &rentalv1.PropertyDetail{Property: toProperty(&properties[0])}
+
+    amenities, err := s.properties.GetAmenities(ctx, []string{destID})
+    if err != nil {
+        return nil, err
+    }
+    detail.Amenities, err = localizeAmenities(amenities[destID], languages(ctx))
+    if err != nil {
+        return nil, err
+    }
+
+    descriptions, err := services.NewPropDescService().GetStoredDescriptions([]string{destID}, languages(ctx))
+    if err != nil {
+        return nil, err
+    }
+    if description := descriptions[destID]; description != nil {
+        if detail.Description, err = toDescription(description); err != nil {
+            return nil, err
+        }
+    }
+
+    aggregate, err := services.NewReviewService().GetAggregate(ctx, destID)
+    if err != nil {
+        return nil, err
+    }
+    if aggregate != nil {
+        detail.GuestReviews = &rentalv1.ReviewAggregate{
+            ReviewCount:  int32(aggregate.ReviewCount),
+            AverageScore: aggregate.AverageScore,
+            Cleanliness:  aggregate.Cleanliness,
+            Location:     aggregate.Location,
+            Value:        aggregate.Value,
+        }
+    }
+    return detail, nil
+}
+
+// WatchCrawl sends the state of a crawl run, then its final state once it finishes
+func (s *Server) WatchCrawl(req *rentalv1.WatchCrawlRequest, stream rentalv1.RentalService_WatchCrawlServer) error {
+    if req.GetId() < 1 {
+        return apperrors.InvalidFields(apperrors.FieldError{Field: "id", Message: "must be at least 1"})
+    }
+    states, stop, err := services.NewCrawlService().Watch(req.GetId())
+    if err != nil {
+        return err
+    }
+    defer stop()
+
+    for {
+        select {
+        case run, ok := <-states:
+            if !ok {
+                return nil
+            }
+            if err := stream.Send(toCrawlRun(&run)); err != nil {
+                return err
+            }
+        case <-stream.Context().Done():
+            return stream.Context().Err()
+        }
+    }
+}
+
+func toProperty(property *models.Property) *rentalv1.Property {
+    message := &rentalv1.Property{
+        DestId:    property.DestID,
+        Name:      property.Name,
+        CityId:    property.CityID,
+        CityName:  property.CityName,
+        Country:   property.Country,
+        Type:      property.Type,
+        Bedrooms:  int32(property.Bedrooms),
+        Bathrooms: int32(property.Bathrooms),
+        Rating:    property.Rating,
+        Price:     property.Price,
+        Currency:  property.Currency,
+    }
+    if property.DisplayPrice != nil {
+        message.DisplayPrice = &rentalv1.Money{Amount: property.DisplayPrice.Amount, Currency: property.DisplayPrice.Currency}
+    }
+    return message
+}
+
+func toDescription(description *models.PropertyDescription) (*rentalv1.Description, error) {
+    message := &rentalv1.Description{
+        Text:        description.Description,
+        Language:    description.Language,
+        Rating:      description.Rating,
+        Review:      description.Review,
+        ReviewCount: int32(description.ReviewCount),
+        Images:      &rentalv1.Images{},
+    }
+    if description.Highlights != "" {
+        if err := json.Unmarshal([]byte(description.Highlights), &message.Highlights); err != nil {
+            return nil, fmt.Errorf("error decoding highlights of property %s: %v", description.DestID, err)
+        }
+    }
+    if description.Images != "" {
+        var images models.CategorizedImages
+        if err := json.Unmarshal([]byte(description.Images), &images); err != nil {
+            return nil, fmt.Errorf("error decoding images of property %s: %v", description.DestID, err)
+        }
+        message.Images = &rentalv1.Images{
+            PropertyBuilding: images.PropertyBuilding,
+            Property:         images.Property,
+            Room:             images.Room,
+        }
+    }
+    return message, nil
+}
+
+func toCrawlRun(run *models.CrawlRun) *rentalv1.CrawlRun {
+    message := &rentalv1.CrawlRun{
+        Id:          run.Id,
+        TriggeredBy: run.TriggeredBy,
+        Status:      run.Status,
+        Error:       run.Error,
+        StartedAt:   timestamppb.New(run.StartedAt),
+    }
+    if !run.FinishedAt.IsZero() {
+        message.FinishedAt = timestamppb.New(run.FinishedAt)
+    }
+    return message
+}
+
+// localizeAmenities translates amenity names into the first available language of the chain
+func localizeAmenities(names []string, languages []string) ([]string, error) {
+    translated, err := services.NewTranslationService().Lookup(models.TranslationEntityAmenity, names,
+        models.TranslationFieldName, languages)
+    if err != nil {
+        return nil, err
+    }
+    localized := make([]string, len(names))
+    for i, name := range names {
+        if value, ok := translated[name]; ok {
+            name = value
+        }
+        localized[i] = name
+    }
+    return localized, nil
+}
+
+type languagesKey struct{}
+
+// withLanguages negotiates the language chain from the accept-language
+// metadata, as the HTTP middleware does from the header
+func withLanguages(ctx context.Context) context.Context {
+    var acceptLanguage string
+    if md, ok := metadata.FromIncomingContext(ctx); ok {
+        acceptLanguage = strings.Join(md.Get("accept-language"), ",")
+    }
+    defaultLanguage := beego.AppConfig.DefaultString("defaultlanguage", "en-gb")
+    return context.WithValue(ctx, languagesKey{}, middleware.LanguageChain("", acceptLanguage, defaultLanguage))
+}
+
+func languages(ctx context.Context) []string {
+    chain, _ := ctx.Value(languagesKey{}).([]string)
+    return chain
+}
+
+func unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+    resp, err := handler(withLanguages(ctx), req)
+    if err != nil {
+        return nil, toStatus(info.FullMethod, err)
+    }
+    return resp, nil
+}
+
+// languageStream carries the negotiated languages in its context
+type languageStream struct {
+    grpc.ServerStream
+    ctx context.Context
+}
+
+func (s *languageStream) Context() context.Context {
+    return s.ctx
+}
+
+func streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+    err := handler(srv, &languageStream{ServerStream: stream, ctx: withLanguages(stream.Context())})
+    if err != nil {
+        return toStatus(info.FullMethod, err)
+    }
+    return nil
+}
+
+var kindCodes = map[apperrors.Kind]codes.Code{
+    apperrors.KindInternal:            codes.Internal,
+    apperrors.KindValidation:          codes.InvalidArgument,
+    apperrors.KindNotFound:            codes.NotFound,
+    apperrors.KindConflict:            codes.FailedPrecondition,
+    apperrors.KindUnauthorized:        codes.Unauthenticated,
+    apperrors.KindRateLimited:         codes.ResourceExhausted,
+    apperrors.KindUpstreamUnavailable: codes.Unavailable,
+}
+
+// toStatus converts a service error to a gRPC status with the client-safe
+// message of its problem details; field errors become BadRequest details
+func toStatus(method string, err error) error {
+    if _, ok := status.FromError(err); ok {
+        return err
+    }
+    if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+        return status.FromContextError(err).Err()
+    }
+
+    kind := apperrors.KindOf(err)
+    if kind == apperrors.KindInternal {
+        slog.Error("gRPC call failed", "method", method, "error", err)
+    }
+    problem := apperrors.NewProblem(err, method, "")
+    st := status.New(kindCodes[kind], problem.Detail)
+    if len(problem.Errors) == 0 {
+        return st.Err()
+    }
+
+    details := &errdetails.BadRequest{}
+    for _, field := range problem.Errors {
+        details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+            Field:       field.Field,
+            Description: field.Message,
+        })
+    }
+    if withDetails, detailErr := st.WithDetails(details); detailErr == nil {
+        st = withDetails
+    }
+    return st.Err()
+}
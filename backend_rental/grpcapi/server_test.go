@@ -0,0 +1,106 @@
+package grpcapi
+
+import (
+    "context"
+    "fmt"
+    "net"
+    "testing"
+
+    "backend_rental/grpcapi/rentalv1"
+    "backend_rental/models"
+    "backend_rental/services"
+    "backend_rental/utils/pagination"
+    "google.golang.org/genproto/googleapis/rpc/errdetails"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials/insecure"
+    "google.golang.org/grpc/status"
+    "google.golang.org/grpc/test/bufconn"
+)
+
+// fakeProperties serves a fixed page of properties
+type fakeProperties struct {
+    services.PropertyServiceInterface
+    filter models.PropertyFilter
+    sort   string
+}
+
+func (f *fakeProperties) ListProperties(ctx context.Context, filter models.PropertyFilter, sort string, params pagination.Params) ([]models.Property, pagination.Page, error) {
+    f.filter, f.sort = filter, sort
+    return []models.Property{{DestID: "7", Name: "Loft", Bedrooms: 2}}, pagination.Page{Limit: params.Limit, NextCursor: "next"}, nil
+}
+
+func dial(t *testing.T, properties services.PropertyServiceInterface) rentalv1.RentalServiceClient {
+    t.Helper()
+    listener := bufconn.Listen(1 << 20)
+    server := NewGRPCServer(properties)
+    go server.Serve(listener)
+    t.Cleanup(server.Stop)
+
+    conn, err := grpc.NewClient("passthrough:///bufnet",
+        grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+        grpc.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        t.Fatal(err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    return rentalv1.NewRentalServiceClient(conn)
+}
+
+func TestListProperties(t *testing.T) {
+    properties := &fakeProperties{}
+    client := dial(t, properties)
+
+    resp, err := client.ListProperties(context.Background(), &rentalv1.ListPropertiesRequest{
+        CityId:      "42",
+        MinBedrooms: 2,
+        Amenities:   []string{"wifi", "pool"},
+        Sort:        "price_asc",
+        Limit:       5,
+    })
+    if err != nil {
+        t.Fatalf("ListProperties: %v", err)
+    }
+    if len(resp.Properties) != 1 || resp.Properties[0].DestId != "7" || resp.Properties[0].Bedrooms != 2 {
+        t.Errorf("properties = %v", resp.Properties)
+    }
+    if resp.Page.Limit != 5 || resp.Page.NextCursor != "next" || resp.Page.TotalCount != nil {
+        t.Errorf("page = %v", resp.Page)
+    }
+    if properties.filter.CityID != "42" || properties.filter.MinBedrooms != 2 || len(properties.filter.Amenities) != 2 || properties.sort != "price_asc" {
+        t.Errorf("filter = %+v, sort = %q", properties.filter, properties.sort)
+    }
+}
+
+func TestInvalidRequestsReportFieldViolations(t *testing.T) {
+    client := dial(t, &fakeProperties{})
+
+    _, err := client.ListProperties(context.Background(), &rentalv1.ListPropertiesRequest{MinBedrooms: 4, MaxBedrooms: 2, Limit: 500})
+    st := status.Convert(err)
+    if st.Code() != codes.InvalidArgument {
+        t.Fatalf("code = %v, err = %v", st.Code(), err)
+    }
+    fields := map[string]bool{}
+    for _, detail := range st.Details() {
+        if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+            for _, violation := range badRequest.FieldViolations {
+                fields[violation.Field] = true
+            }
+        }
+    }
+    if !fields["min_bedrooms"] {
+        t.Errorf("violations = %v", fields)
+    }
+
+    _, err = client.GetProperty(context.Background(), &rentalv1.GetPropertyRequest{})
+    if status.Code(err) != codes.InvalidArgument {
+        t.Errorf("GetProperty without dest_id: %v", err)
+    }
+}
+
+func TestWrappedCancellationMapsToCanceled(t *testing.T) {
+    err := toStatus("/rental.v1.RentalService/ListProperties", fmt.Errorf("listing properties: %w", context.Canceled))
+    if status.Code(err) != codes.Canceled {
+        t.Errorf("code = %v, err = %v", status.Code(err), err)
+    }
+}
@@ -1,26 +1,118 @@
 package main
 
 import (
+    "backend_rental/grpcapi"
+    "backend_rental/services"
     "backend_rental/utils"
+    "backend_rental/utils/logging"
+    "backend_rental/utils/tracing"
     _ "backend_rental/routers"
-    "log"
+    "context"
+    "fmt"
+    "log/slog"
+    "os"
+    "os/signal"
+    "syscall"
+    "time"
     beego "github.com/beego/beego/v2/server/web"
+    "google.golang.org/grpc"
 
 )
 
 
 func main() {
+    logging.Init(beego.AppConfig.DefaultString("loglevel", "info"), beego.AppConfig.DefaultString("logformat", "json"))
+    for _, key := range []string{"rapidapikey", "admintoken", "dbpassword", "s3secretkey"} {
+        logging.AddSecret(beego.AppConfig.DefaultString(key, ""))
+    }
+
+    shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+        Exporter:    beego.AppConfig.DefaultString("tracingexporter", tracing.ExporterNone),
+        Endpoint:    beego.AppConfig.DefaultString("otlpendpoint", ""),
+        Insecure:    beego.AppConfig.DefaultBool("otlpinsecure", false),
+        SampleRatio: beego.AppConfig.DefaultFloat("tracingsampleratio", 1),
+    })
+    if err != nil {
+        slog.Error("failed to initialize tracing", "error", err)
+        os.Exit(1)
+    }
+    defer func() {
+        ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        if err := shutdownTracing(ctx); err != nil {
+            slog.Error("failed to flush traces", "error", err)
+        }
+    }()
+
     if beego.BConfig.RunMode == "dev" {
         beego.BConfig.WebConfig.DirectoryIndex = true
         beego.BConfig.WebConfig.StaticDir["/swagger"] = "swagger"
     }
 
 	if err := utils.InitDatabaseFromConfig(); err != nil {
-        log.Fatalf("Failed to initialize database: %v", err)
+        slog.Error("failed to initialize database", "error", err)
+        os.Exit(1)
+    }
+
+    languageService := services.NewLanguageService()
+    if err := languageService.LoadSupportedLanguages(); err != nil {
+        slog.Error("failed to load supported languages", "error", err)
+    }
+    go func() {
+        err := languageService.SyncLanguages(context.Background())
+        if err != nil {
+            slog.Error("language sync failed", "error", err)
+        }
+        services.RecordTask(services.TaskLanguageSync, err)
+    }()
+
+    currencyService := services.NewCurrencyService()
+    if ratesFile, _ := beego.AppConfig.String("fxratesfile"); ratesFile != "" {
+        if err := currencyService.LoadRatesFromFile(ratesFile); err != nil {
+            slog.Error("failed to load FX rates", "file", ratesFile, "error", err)
+        }
     }
-    log.Println("Service initialized")
-    log.Println("Starting application...")
+    if err := currencyService.LoadRates(); err != nil {
+        slog.Error("failed to load FX rates", "error", err)
+    }
+
+    // Refresh the location catalogue in the background on every start
+    if _, err := services.NewCrawlService().Start(services.CrawlTriggerStartup); err != nil {
+        slog.Error("failed to start location crawl", "error", err)
+    }
+
+    var grpcServer *grpc.Server
+    if grpcPort := beego.AppConfig.DefaultInt("grpcport", 9090); grpcPort > 0 {
+        server, err := grpcapi.ListenAndServe(fmt.Sprintf(":%d", grpcPort))
+        if err != nil {
+            slog.Error("failed to start the gRPC server", "error", err)
+        }
+        grpcServer = server
+    }
+
+    // On SIGINT or SIGTERM stop accepting requests on the HTTP and gRPC servers
+    // and let the in-flight ones finish; beego.Run then returns and the
+    // deferred trace flush runs
+    signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    drained := make(chan struct{})
+    go func() {
+        defer close(drained)
+        <-signalCtx.Done()
+        ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+        defer cancel()
+        if grpcServer != nil {
+            go grpcapi.GracefulStop(ctx, grpcServer)
+        }
+        if err := beego.BeeApp.Server.Shutdown(ctx); err != nil {
+            slog.Error("failed to shut down the HTTP server", "error", err)
+        }
+    }()
+
+    slog.Info("service initialized", "run_mode", beego.BConfig.RunMode)
     beego.Run()
+    stop()
+    <-drained
+    slog.Info("service stopped")
 }
 
 
@@ -0,0 +1,43 @@
+// middleware/access_log.go
+package middleware
+
+import (
+    "log/slog"
+    "time"
+
+    beego "github.com/beego/beego/v2/server/web"
+    "github.com/beego/beego/v2/server/web/context"
+)
+
+// probePaths are polled every few seconds by the orchestrator
+var probePaths = map[string]bool{"/healthz": true, "/readyz": true}
+
+// LogRequests writes one structured line per request with its route, status
+// and duration; server errors are logged at error level and successful probes
+// at debug level
+func LogRequests(next beego.FilterFunc) beego.FilterFunc {
+    return func(ctx *context.Context) {
+        start := time.Now()
+        next(ctx)
+
+        status := ctx.ResponseWriter.Status
+        if status == 0 {
+            status = 200
+        }
+        level := slog.LevelInfo
+        if status >= 500 {
+            level = slog.LevelError
+        } else if probePaths[ctx.Request.URL.Path] && status < 400 {
+            level = slog.LevelDebug
+        }
+        route, _ := ctx.Input.GetData("RouterPattern").(string)
+        slog.Log(ctx.Request.Context(), level, "request",
+            "method", ctx.Input.Method(),
+            "path", ctx.Request.URL.Path,
+            "route", route,
+            "status", status,
+            "duration_ms", time.Since(start).Milliseconds(),
+            "ip", ctx.Input.IP(),
+        )
+    }
+}
@@ -0,0 +1,30 @@
+// middleware/admin.go
+package middleware
+
+import (
+    "crypto/subtle"
+
+    "backend_rental/utils/apperrors"
+    "github.com/beego/beego/v2/server/web/context"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+// RequireAdmin rejects requests that do not carry the configured admin token
+func RequireAdmin(ctx *context.Context) {
+    if IsAdmin(ctx) {
+        return
+    }
+
+    WriteProblem(ctx, apperrors.Unauthorized("admin token required"))
+}
+
+// IsAdmin reports whether the request carries the admin token in X-Admin-Token
+func IsAdmin(ctx *context.Context) bool {
+    token := beego.AppConfig.DefaultString("admintoken", "")
+    if token == "" {
+        return false
+    }
+
+    provided := ctx.Input.Header("X-Admin-Token")
+    return subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
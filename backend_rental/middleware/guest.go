@@ -0,0 +1,14 @@
+// middleware/guest.go
+package middleware
+
+import (
+    "strings"
+
+    "github.com/beego/beego/v2/server/web/context"
+)
+
+// GuestID returns the guest identifier sent in X-Guest-ID, which scopes
+// wishlists and saved searches until user accounts exist
+func GuestID(ctx *context.Context) string {
+    return strings.TrimSpace(ctx.Input.Header("X-Guest-ID"))
+}
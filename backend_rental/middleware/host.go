@@ -0,0 +1,14 @@
+// middleware/host.go
+package middleware
+
+import (
+    "strings"
+
+    "github.com/beego/beego/v2/server/web/context"
+)
+
+// HostID returns the host identifier sent in X-Host-ID, which scopes the
+// photos a host uploads until host accounts exist
+func HostID(ctx *context.Context) string {
+    return strings.TrimSpace(ctx.Input.Header("X-Host-ID"))
+}
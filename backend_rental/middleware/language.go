@@ -0,0 +1,112 @@
+// middleware/language.go
+package middleware
+
+import (
+    "sort"
+    "strconv"
+    "strings"
+
+    "github.com/beego/beego/v2/server/web/context"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+const languagesKey = "languages"
+
+// NegotiateLanguage builds the language fallback chain from ?lang= and
+// Accept-Language and stores it on the request for controllers to use
+func NegotiateLanguage(ctx *context.Context) {
+    defaultLanguage := beego.AppConfig.DefaultString("defaultlanguage", "en-gb")
+    chain := LanguageChain(ctx.Input.Query("lang"), ctx.Input.Header("Accept-Language"), defaultLanguage)
+
+    ctx.Input.SetData(languagesKey, chain)
+    ctx.Output.Header("Vary", "Accept-Language")
+}
+
+// Languages returns the negotiated language chain, most preferred first
+func Languages(ctx *context.Context) []string {
+    if chain, ok := ctx.Input.GetData(languagesKey).([]string); ok {
+        return chain
+    }
+    return nil
+}
+
+// LanguageChain orders the requested languages by preference and expands each
+// into its fallbacks, e.g. pt-BR becomes pt-br, pt, then the default language and en
+func LanguageChain(langParam, acceptLanguage, defaultLanguage string) []string {
+    var requested []string
+    if langParam != "" {
+        requested = append(requested, langParam)
+    }
+    requested = append(requested, parseAcceptLanguage(acceptLanguage)...)
+    requested = append(requested, defaultLanguage, "en")
+
+    seen := make(map[string]bool)
+    chain := make([]string, 0, len(requested)*2)
+    for _, tag := range requested {
+        for _, candidate := range expandLanguageTag(tag) {
+            if !seen[candidate] {
+                seen[candidate] = true
+                chain = append(chain, candidate)
+            }
+        }
+    }
+
+    return chain
+}
+
+// expandLanguageTag returns the normalised tag followed by its parent tags
+func expandLanguageTag(tag string) []string {
+    tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+    if tag == "" || tag == "*" {
+        return nil
+    }
+
+    parts := strings.Split(tag, "-")
+    tags := make([]string, 0, len(parts))
+    for i := len(parts); i > 0; i-- {
+        tags = append(tags, strings.Join(parts[:i], "-"))
+    }
+    return tags
+}
+
+// parseAcceptLanguage returns the Accept-Language tags ordered by quality
+func parseAcceptLanguage(header string) []string {
+    type weightedTag struct {
+        tag     string
+        quality float64
+    }
+
+    var tags []weightedTag
+    for _, part := range strings.Split(header, ",") {
+        fields := strings.Split(strings.TrimSpace(part), ";")
+        tag := strings.TrimSpace(fields[0])
+        if tag == "" || tag == "*" {
+            continue
+        }
+
+        quality := 1.0
+        for _, param := range fields[1:] {
+            param = strings.TrimSpace(param)
+            if strings.HasPrefix(param, "q=") {
+                if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+                    quality = q
+                }
+            }
+        }
+        if quality <= 0 {
+            continue
+        }
+
+        tags = append(tags, weightedTag{tag: tag, quality: quality})
+    }
+
+    sort.SliceStable(tags, func(i, j int) bool {
+        return tags[i].quality > tags[j].quality
+    })
+
+    result := make([]string, len(tags))
+    for i, t := range tags {
+        result[i] = t.tag
+    }
+    return result
+}
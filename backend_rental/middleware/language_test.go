@@ -0,0 +1,28 @@
+package middleware
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestLanguageChain(t *testing.T) {
+    cases := []struct {
+        name           string
+        langParam      string
+        acceptLanguage string
+        want           []string
+    }{
+        {"default only", "", "", []string{"en-gb", "en"}},
+        {"region falls back to base", "pt-BR", "", []string{"pt-br", "pt", "en-gb", "en"}},
+        {"query param wins over header", "de", "fr-FR", []string{"de", "fr-fr", "fr", "en-gb", "en"}},
+        {"header ordered by quality", "", "fr;q=0.5, pt_BR, *;q=0.1", []string{"pt-br", "pt", "fr", "en-gb", "en"}},
+        {"zero quality ignored", "", "es;q=0, en-US", []string{"en-us", "en", "en-gb"}},
+    }
+
+    for _, tc := range cases {
+        got := LanguageChain(tc.langParam, tc.acceptLanguage, "en-gb")
+        if !reflect.DeepEqual(got, tc.want) {
+            t.Errorf("%s: LanguageChain(%q, %q) = %v, want %v", tc.name, tc.langParam, tc.acceptLanguage, got, tc.want)
+        }
+    }
+}
@@ -0,0 +1,33 @@
+// middleware/metrics.go
+package middleware
+
+import (
+    "strconv"
+    "time"
+
+    "backend_rental/utils/metrics"
+    beego "github.com/beego/beego/v2/server/web"
+    "github.com/beego/beego/v2/server/web/context"
+)
+
+// ObserveRequests records the duration of every request by route pattern.
+// It wraps the whole handling, so requests rejected by filters before routing
+// are recorded too, under the route "unmatched".
+func ObserveRequests(next beego.FilterFunc) beego.FilterFunc {
+    return func(ctx *context.Context) {
+        start := time.Now()
+        next(ctx)
+
+        route, _ := ctx.Input.GetData("RouterPattern").(string)
+        if route == "" {
+            route = "unmatched"
+        }
+        status := ctx.ResponseWriter.Status
+        if status == 0 {
+            status = 200
+        }
+        metrics.HTTPRequestDuration.
+            WithLabelValues(ctx.Input.Method(), route, strconv.Itoa(status)).
+            Observe(time.Since(start).Seconds())
+    }
+}
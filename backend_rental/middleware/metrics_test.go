@@ -0,0 +1,36 @@
+package middleware
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "backend_rental/utils/metrics"
+    "github.com/beego/beego/v2/server/web/context"
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/testutil"
+    dto "github.com/prometheus/client_model/go"
+)
+
+func TestObserveRequestsLabelsByRoutePattern(t *testing.T) {
+    handler := ObserveRequests(func(ctx *context.Context) {
+        ctx.Input.SetData("RouterPattern", "/v1/locations/:id")
+        ctx.Output.SetStatus(http.StatusNotFound)
+        ctx.Output.Body([]byte("not found"))
+    })
+
+    for _, id := range []string{"1", "2"} {
+        ctx := context.NewContext()
+        ctx.Reset(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/locations/"+id, nil))
+        handler(ctx)
+    }
+
+    if got := testutil.CollectAndCount(metrics.HTTPRequestDuration); got != 1 {
+        t.Fatalf("%d series, want one for both IDs", got)
+    }
+    var sample dto.Metric
+    metrics.HTTPRequestDuration.WithLabelValues("GET", "/v1/locations/:id", "404").(prometheus.Metric).Write(&sample)
+    if got := sample.GetHistogram().GetSampleCount(); got != 2 {
+        t.Errorf("%d requests observed, want 2", got)
+    }
+}
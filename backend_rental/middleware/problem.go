@@ -0,0 +1,25 @@
+// middleware/problem.go
+package middleware
+
+import (
+    "encoding/json"
+    "log/slog"
+
+    "backend_rental/utils/apperrors"
+    "github.com/beego/beego/v2/server/web/context"
+)
+
+// WriteProblem answers the request with err as an application/problem+json body
+func WriteProblem(ctx *context.Context, err error) {
+    requestID := RequestID(ctx)
+    problem := apperrors.NewProblem(err, ctx.Request.URL.Path, requestID)
+    if problem.Status >= 500 {
+        slog.ErrorContext(ctx.Request.Context(), "request failed",
+            "method", ctx.Request.Method, "path", ctx.Request.URL.Path, "status", problem.Status, "error", err)
+    }
+
+    body, _ := json.Marshal(problem)
+    ctx.Output.Header("Content-Type", "application/problem+json; charset=utf-8")
+    ctx.Output.SetStatus(problem.Status)
+    ctx.Output.Body(body)
+}
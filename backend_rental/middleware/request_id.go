@@ -0,0 +1,56 @@
+// middleware/request_id.go
+package middleware
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+
+    "backend_rental/utils/logging"
+    "github.com/beego/beego/v2/server/web/context"
+)
+
+const (
+    requestIDHeader = "X-Request-ID"
+    requestIDKey    = "request_id"
+    maxRequestIDLen = 128
+)
+
+// AssignRequestID adopts the caller's X-Request-ID or generates one, and
+// echoes it on the response. The request context carries it too, so log
+// lines of services and upstream calls made for the request include it.
+func AssignRequestID(ctx *context.Context) {
+    id := ctx.Input.Header(requestIDHeader)
+    if !validRequestID(id) {
+        id = newRequestID()
+    }
+
+    ctx.Input.SetData(requestIDKey, id)
+    ctx.Output.Header(requestIDHeader, id)
+    ctx.Request = ctx.Request.WithContext(logging.WithRequestID(ctx.Request.Context(), id))
+}
+
+// RequestID returns the ID assigned to the request
+func RequestID(ctx *context.Context) string {
+    if id, ok := ctx.Input.GetData(requestIDKey).(string); ok {
+        return id
+    }
+    return ""
+}
+
+func validRequestID(id string) bool {
+    if id == "" || len(id) > maxRequestIDLen {
+        return false
+    }
+    for _, r := range id {
+        if r < 0x21 || r > 0x7e {
+            return false
+        }
+    }
+    return true
+}
+
+func newRequestID() string {
+    b := make([]byte, 16)
+    rand.Read(b)
+    return hex.EncodeToString(b)
+}
@@ -0,0 +1,49 @@
+// middleware/tracing.go
+package middleware
+
+import (
+    "net/http"
+
+    "backend_rental/utils/tracing"
+    beego "github.com/beego/beego/v2/server/web"
+    "github.com/beego/beego/v2/server/web/context"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/trace"
+)
+
+// TraceRequests starts a server span for every request, continuing the trace
+// of a traceparent header if the caller sent one. The request context carries
+// the span, so spans started by controllers and services nest under it. The
+// span is named after the route pattern once routing has run.
+func TraceRequests(next beego.FilterFunc) beego.FilterFunc {
+    return func(ctx *context.Context) {
+        parent := otel.GetTextMapPropagator().Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+        spanCtx, span := otel.Tracer(tracing.ServiceName).Start(parent, ctx.Input.Method()+" "+ctx.Request.URL.Path,
+            trace.WithSpanKind(trace.SpanKindServer),
+            trace.WithAttributes(
+                attribute.String("http.request.method", ctx.Input.Method()),
+                attribute.String("url.path", ctx.Request.URL.Path),
+                attribute.String("client.address", ctx.Input.IP()),
+            ))
+        defer span.End()
+        ctx.Request = ctx.Request.WithContext(spanCtx)
+
+        next(ctx)
+
+        if route, _ := ctx.Input.GetData("RouterPattern").(string); route != "" {
+            span.SetName(ctx.Input.Method() + " " + route)
+            span.SetAttributes(attribute.String("http.route", route))
+        }
+        status := ctx.ResponseWriter.Status
+        if status == 0 {
+            status = http.StatusOK
+        }
+        span.SetAttributes(attribute.Int("http.response.status_code", status))
+        if status >= 500 {
+            span.SetStatus(codes.Error, http.StatusText(status))
+        }
+    }
+}
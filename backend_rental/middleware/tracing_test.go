@@ -0,0 +1,53 @@
+package middleware
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/beego/beego/v2/server/web/context"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/propagation"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/sdk/trace/tracetest"
+    "go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceRequestsContinuesCallerTrace(t *testing.T) {
+    recorder := tracetest.NewSpanRecorder()
+    otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+    otel.SetTextMapPropagator(propagation.TraceContext{})
+
+    var inner trace.SpanContext
+    handler := TraceRequests(func(ctx *context.Context) {
+        inner = trace.SpanContextFromContext(ctx.Request.Context())
+        ctx.Input.SetData("RouterPattern", "/v1/locations/:id")
+        ctx.Output.SetStatus(http.StatusInternalServerError)
+        ctx.Output.Body([]byte("boom"))
+    })
+
+    req := httptest.NewRequest(http.MethodGet, "/v1/locations/7", nil)
+    req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+    ctx := context.NewContext()
+    ctx.Reset(httptest.NewRecorder(), req)
+    handler(ctx)
+
+    spans := recorder.Ended()
+    if len(spans) != 1 {
+        t.Fatalf("%d spans, want 1", len(spans))
+    }
+    span := spans[0]
+    if span.Name() != "GET /v1/locations/:id" {
+        t.Errorf("span named %q", span.Name())
+    }
+    if got := span.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+        t.Errorf("trace %s, want the caller's", got)
+    }
+    if span.SpanContext().SpanID() != inner.SpanID() {
+        t.Error("handler context does not carry the request span")
+    }
+    if span.Status().Code != codes.Error {
+        t.Errorf("status %v, want an error for a 500", span.Status().Code)
+    }
+}
@@ -8,6 +8,9 @@ type PropertyDetails struct {
     Bathroom     int        `json:"bathroom"`
     Amenities    []Facility `json:"amenities"`
     CityID       *int       `json:"city_id"` // Add the CityID field as a pointer
+    Price        float64    `json:"price"`
+    Currency     string     `json:"currency"`
+    DisplayPrice *Money     `json:"display_price,omitempty"`
 }
 
 type Facility struct {
@@ -9,6 +9,8 @@ type RentalProperty struct {
     Bedrooms  int      `json:"bedrooms" orm:"column(bedrooms)"`
     Bathroom  int      `json:"bathroom" orm:"column(bathroom)"`
     Amenities string   `json:"amenities" orm:"column(amenities)"`
+    Price     float64  `json:"price" orm:"column(price);null"`
+    Currency  string   `json:"currency" orm:"column(currency);size(3);null"`
 }
 
 // TableName returns the name of the table for ORM mapping.
@@ -5,6 +5,7 @@ type City struct {
     CityID      string  `json:"city_id"`
     CityName    string  `json:"city_name"`
     Country     string  `json:"country"`
+    DestID      string  `json:"dest_id"`
 }
 
 type CityResponse struct {
@@ -0,0 +1,28 @@
+package models
+
+import (
+    "time"
+
+    "github.com/beego/beego/v2/client/orm"
+)
+
+// Crawl run states
+const (
+    CrawlStatusRunning   = "running"
+    CrawlStatusSucceeded = "succeeded"
+    CrawlStatusFailed    = "failed"
+)
+
+// CrawlRun records one crawl of the upstream location catalogue
+type CrawlRun struct {
+    Id          int64     `json:"id" orm:"auto;column(id)"`
+    TriggeredBy string    `json:"triggered_by" orm:"column(triggered_by);size(16)"`
+    Status      string    `json:"status" orm:"column(status);size(16);index"`
+    Error       string    `json:"error,omitempty" orm:"column(error);null;type(text)"`
+    StartedAt   time.Time `json:"started_at" orm:"column(started_at);auto_now_add;type(datetime)"`
+    FinishedAt  time.Time `json:"finished_at" orm:"column(finished_at);null;type(datetime)"`
+}
+
+func init() {
+    orm.RegisterModel(new(CrawlRun))
+}
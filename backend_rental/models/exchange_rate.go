@@ -0,0 +1,19 @@
+package models
+
+import (
+    "time"
+
+    "github.com/beego/beego/v2/client/orm"
+)
+
+// ExchangeRate is how many units of Currency one unit of Base buys
+type ExchangeRate struct {
+    Currency  string    `json:"currency" orm:"pk;size(3);column(currency)"`
+    Base      string    `json:"base" orm:"size(3);column(base)"`
+    Rate      float64   `json:"rate" orm:"column(rate)"`
+    UpdatedAt time.Time `json:"updated_at" orm:"column(updated_at);type(datetime)"`
+}
+
+func init() {
+    orm.RegisterModel(new(ExchangeRate))
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+    "time"
+
+    "github.com/beego/beego/v2/client/orm"
+)
+
+// Language is an upstream-supported content language
+type Language struct {
+    Code        string    `json:"code" orm:"pk;size(16);column(code)"`
+    Name        string    `json:"name" orm:"column(name)"`
+    CountryFlag string    `json:"country_flag" orm:"column(country_flag);null"`
+    UpdatedAt   time.Time `json:"updated_at" orm:"column(updated_at);auto_now;type(datetime)"`
+}
+
+func init() {
+    orm.RegisterModel(new(Language))
+}
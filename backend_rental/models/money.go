@@ -0,0 +1,7 @@
+package models
+
+// Money is an amount in an ISO 4217 currency, rounded to its minor units
+type Money struct {
+    Amount   float64 `json:"amount"`
+    Currency string  `json:"currency"`
+}
@@ -11,6 +11,39 @@ type Property struct {
     Name     string    `json:"name"`
     CityID   string    `json:"city_id"`
     CityName string    `json:"city_name"`
+    Country  string    `json:"country" gorm:"index"`
+
+    // Type, Bedrooms and Bathrooms come from the stay detail; Rating from the review summary
+    Type      string  `json:"type" gorm:"index"`
+    Bedrooms  int     `json:"bedrooms" gorm:"index"`
+    Bathrooms int     `json:"bathrooms"`
+    Rating    float64 `json:"rating"`
+
+    // Price and Currency are kept exactly as received from upstream
+    Price    float64   `json:"price"`
+    Currency string    `json:"currency" gorm:"size:3"`
+
+    DisplayPrice *Money `json:"display_price,omitempty" gorm:"-"`
+}
+
+// PropertyAmenity is one amenity of a property, stored under its upstream default-language name
+type PropertyAmenity struct {
+    ID     uint   `json:"-" gorm:"primaryKey"`
+    DestID string `json:"dest_id" gorm:"uniqueIndex:idx_property_amenity;size:64"`
+    Name   string `json:"name" gorm:"uniqueIndex:idx_property_amenity;index"`
+}
+
+// FacetCount is the number of matching properties sharing one facet value
+type FacetCount struct {
+    Value string `json:"value"`
+    Count int64  `json:"count"`
+}
+
+// PropertyFacets summarises a property search by type, amenity and bedroom count
+type PropertyFacets struct {
+    Types     []FacetCount `json:"types"`
+    Amenities []FacetCount `json:"amenities"`
+    Bedrooms  []FacetCount `json:"bedrooms"`
 }
     // package models
     
@@ -1,6 +1,8 @@
 package models
 
 import (
+    "time"
+
     "github.com/beego/beego/v2/client/orm"
 )
 
@@ -10,8 +12,23 @@ type PropertyDescription struct {
     Description string `orm:"type(text)"`
     Rating      float64
     Review      string `orm:"type(text)"`
+    ReviewCount int
+    // Highlights holds the upstream highlight names as a JSON array
+    Highlights  string `orm:"type(text);null"`
+    Language    string `orm:"size(16);null"`
+
+    // Each upstream field records when it was fetched and a hash of its
+    // content, so refreshes can tell changed content from a re-download
+    ImagesFetchedAt      time.Time `orm:"null;type(datetime)"`
+    ImagesHash           string    `orm:"size(64);null"`
+    DescriptionFetchedAt time.Time `orm:"null;type(datetime)"`
+    DescriptionHash      string    `orm:"size(64);null"`
+    ReviewsFetchedAt     time.Time `orm:"null;type(datetime)"`
+
+    // GuestReviews aggregates the approved reviews submitted through this API
+    GuestReviews *ReviewAggregate `orm:"-"`
 }
 
 func init() {
     orm.RegisterModel(new(PropertyDescription))
-}
\ No newline at end of file
+}
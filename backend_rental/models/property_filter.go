@@ -0,0 +1,54 @@
+package models
+
+import (
+    "net/url"
+
+    "backend_rental/utils/binding"
+    "github.com/beego/beego/v2/core/validation"
+)
+
+// PropertyFilter narrows a property search; empty fields match everything
+type PropertyFilter struct {
+    CityID       string   `json:"city_id,omitempty" form:"city_id" valid:"MaxSize(32)"`
+    CityName     string   `json:"city_name,omitempty" form:"city_name" valid:"MaxSize(100)"`
+    Name         string   `json:"name,omitempty" form:"name" valid:"MaxSize(200)"`
+    Country      string   `json:"country,omitempty" form:"country" valid:"MaxSize(100)"`
+    Type         string   `json:"type,omitempty" form:"type" valid:"MaxSize(50)"`
+    MinBedrooms  int      `json:"min_bedrooms,omitempty" form:"min_bedrooms" valid:"Range(0,50)"`
+    MaxBedrooms  int      `json:"max_bedrooms,omitempty" form:"max_bedrooms" valid:"Range(0,50)"`
+    MinBathrooms int      `json:"min_bathrooms,omitempty" form:"min_bathrooms" valid:"Range(0,50)"`
+    MaxBathrooms int      `json:"max_bathrooms,omitempty" form:"max_bathrooms" valid:"Range(0,50)"`
+    MinRating    float64  `json:"min_rating,omitempty" form:"rating"`
+    // Amenities matches properties that have every listed amenity
+    Amenities    []string `json:"amenities,omitempty" form:"amenities" valid:"MaxSize(20)"`
+}
+
+// Valid checks the rating range and that the minimum bounds do not exceed the maximums
+func (f *PropertyFilter) Valid(v *validation.Validation) {
+    if f.MinRating < 0 || f.MinRating > 10 {
+        v.SetError("rating", "must be between 0 and 10")
+    }
+    if f.MaxBedrooms > 0 && f.MinBedrooms > f.MaxBedrooms {
+        v.SetError("min_bedrooms", "must not exceed max_bedrooms")
+    }
+    if f.MaxBathrooms > 0 && f.MinBathrooms > f.MaxBathrooms {
+        v.SetError("min_bathrooms", "must not exceed max_bathrooms")
+    }
+}
+
+// IsEmpty reports whether the filter matches every property
+func (f PropertyFilter) IsEmpty() bool {
+    return f.CityID == "" && f.CityName == "" && f.Name == "" && f.Country == "" && f.Type == "" &&
+        f.MinBedrooms == 0 && f.MaxBedrooms == 0 && f.MinBathrooms == 0 && f.MaxBathrooms == 0 &&
+        f.MinRating == 0 && len(f.Amenities) == 0
+}
+
+// ParsePropertyFilter reads the filter from query parameters; amenities is a
+// comma-separated list
+func ParsePropertyFilter(values url.Values) (PropertyFilter, error) {
+    var filter PropertyFilter
+    if err := binding.BindValues(values, &filter); err != nil {
+        return PropertyFilter{}, err
+    }
+    return filter, nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+    "net/url"
+    "reflect"
+    "testing"
+)
+
+func TestParsePropertyFilter(t *testing.T) {
+    values, _ := url.ParseQuery("city_id=-2601889&type=Apartment&min_bedrooms=2&max_bedrooms=4&rating=8.5&amenities=Free%20WiFi,%20Parking,")
+    got, err := ParsePropertyFilter(values)
+    if err != nil {
+        t.Fatalf("ParsePropertyFilter returned error: %v", err)
+    }
+
+    want := PropertyFilter{
+        CityID:      "-2601889",
+        Type:        "Apartment",
+        MinBedrooms: 2,
+        MaxBedrooms: 4,
+        MinRating:   8.5,
+        Amenities:   []string{"Free WiFi", "Parking"},
+    }
+    if !reflect.DeepEqual(got, want) {
+        t.Errorf("ParsePropertyFilter = %+v, want %+v", got, want)
+    }
+    if got.IsEmpty() {
+        t.Errorf("IsEmpty() = true for %+v", got)
+    }
+}
+
+func TestParsePropertyFilterRejectsInvalidValues(t *testing.T) {
+    for _, query := range []string{
+        "min_bedrooms=two",
+        "max_bathrooms=-1",
+        "rating=11",
+        "min_bedrooms=3&max_bedrooms=2",
+    } {
+        values, _ := url.ParseQuery(query)
+        if _, err := ParsePropertyFilter(values); err == nil {
+            t.Errorf("ParsePropertyFilter(%q) returned no error", query)
+        }
+    }
+}
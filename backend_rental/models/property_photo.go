@@ -0,0 +1,71 @@
+package models
+
+import (
+    "time"
+
+    "github.com/beego/beego/v2/client/orm"
+)
+
+// Photo sources
+const (
+    PhotoSourceUpstream = "upstream"
+    PhotoSourceHost     = "host"
+)
+
+// HostPhotoIDBase offsets the IDs of host uploads above the upstream photo
+// IDs, so both share property_photos and the /img proxy
+const HostPhotoIDBase int64 = 1_000_000_000_000_000
+
+// PropertyPhoto is one photo of a property, from upstream or uploaded by a
+// host. URLs holds the photo's URL per size as a JSON object, e.g.
+// {"max1280x900": "https://..."}.
+type PropertyPhoto struct {
+    Id      int64  `json:"-" orm:"auto;column(id)"`
+    DestID  string `json:"-" orm:"column(dest_id);size(32);index"`
+    PhotoID int64  `json:"id" orm:"column(photo_id);index"`
+    Source  string `json:"source" orm:"column(source);size(16);default(upstream)"`
+    // HostID is the X-Host-ID of the host who uploaded the photo
+    HostID string `json:"-" orm:"column(host_id);size(64);null"`
+    Tag    string `json:"tag" orm:"column(tag);size(128)"`
+    // Position is the photo's place among the photos of its source, starting at 0
+    Position  int       `json:"position" orm:"column(position)"`
+    Caption   string    `json:"caption,omitempty" orm:"column(caption);type(text);null"`
+    URLs      string    `json:"-" orm:"column(urls);type(text)"`
+    FetchedAt time.Time `json:"-" orm:"column(fetched_at);type(datetime)"`
+
+    // Sizes is URLs decoded for responses
+    Sizes map[string]string `json:"urls" orm:"-"`
+    // ProxyURL serves the photo through /img, which keeps working when the
+    // upstream URLs expire
+    ProxyURL string `json:"proxy_url" orm:"-"`
+}
+
+func (p *PropertyPhoto) TableName() string {
+    return "property_photos"
+}
+
+func (p *PropertyPhoto) TableUnique() [][]string {
+    return [][]string{
+        {"DestID", "PhotoID"},
+    }
+}
+
+// PhotoCategory holds the photos of one upstream tag in upstream order
+type PhotoCategory struct {
+    Tag    string          `json:"tag"`
+    Photos []PropertyPhoto `json:"photos"`
+}
+
+// PropertyPhotos are the photos of a property grouped by tag, with the photo
+// chosen to represent the property
+type PropertyPhotos struct {
+    DestID     string          `json:"dest_id"`
+    Cover      *PropertyPhoto  `json:"cover"`
+    Categories []PhotoCategory `json:"categories"`
+    // LastModified is when the photos were last fetched or uploaded
+    LastModified time.Time `json:"-"`
+}
+
+func init() {
+    orm.RegisterModel(new(PropertyPhoto))
+}
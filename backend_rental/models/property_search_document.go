@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// PropertySearchDocument is the per-language full-text index entry of a
+// property. Document is maintained in SQL from the text columns, weighted
+// name > location > amenities > description; Keywords backs the trigram
+// match that tolerates typos.
+type PropertySearchDocument struct {
+    DestID      string    `json:"dest_id" gorm:"primaryKey;size:64"`
+    Language    string    `json:"language" gorm:"primaryKey;size:16"`
+    Config      string    `json:"-" gorm:"size:32"`
+    Name        string    `json:"name"`
+    Location    string    `json:"location"`
+    Amenities   string    `json:"amenities"`
+    Description string    `json:"description"`
+    Keywords    string    `json:"-"`
+    Document    string    `json:"-" gorm:"type:tsvector;index:,type:gin"`
+    UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PropertySearchResult is one ranked full-text search hit
+type PropertySearchResult struct {
+    Property *Property `json:"property"`
+    Rank     float64   `json:"rank"`
+    // Snippet is an excerpt with the matched terms wrapped in <mark> tags
+    Snippet  string    `json:"snippet"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+    "time"
+
+    "github.com/beego/beego/v2/client/orm"
+)
+
+// Review moderation states
+const (
+    ReviewStatusPending  = "pending"
+    ReviewStatusApproved = "approved"
+    ReviewStatusRejected = "rejected"
+)
+
+// Review is a guest review of a stay with per-aspect sub-scores on a 1-10 scale
+type Review struct {
+    Id            int64     `json:"id" orm:"auto;column(id)"`
+    DestID        string    `json:"dest_id" orm:"column(dest_id);index"`
+    AuthorName    string    `json:"author_name" orm:"column(author_name)"`
+    StayReference string    `json:"stay_reference" orm:"column(stay_reference);null"`
+    Cleanliness   float64   `json:"cleanliness" orm:"column(cleanliness)"`
+    Location      float64   `json:"location" orm:"column(location)"`
+    Value         float64   `json:"value" orm:"column(value)"`
+    Overall       float64   `json:"overall" orm:"column(overall)"`
+    Text          string    `json:"text" orm:"column(text);type(text)"`
+    Language      string    `json:"language" orm:"column(language);size(16)"`
+    Status        string    `json:"status" orm:"column(status);size(16);index"`
+    CreatedAt     time.Time `json:"created_at" orm:"column(created_at);auto_now_add;type(datetime)"`
+    ModeratedAt   time.Time `json:"moderated_at" orm:"column(moderated_at);null;type(datetime)"`
+}
+
+// ReviewAggregate holds the recomputed scores over a property's approved reviews
+type ReviewAggregate struct {
+    DestID       string    `json:"dest_id" orm:"pk;column(dest_id)"`
+    ReviewCount  int       `json:"review_count" orm:"column(review_count)"`
+    AverageScore float64   `json:"average_score" orm:"column(average_score)"`
+    Cleanliness  float64   `json:"cleanliness" orm:"column(cleanliness)"`
+    Location     float64   `json:"location" orm:"column(location)"`
+    Value        float64   `json:"value" orm:"column(value)"`
+    UpdatedAt    time.Time `json:"updated_at" orm:"column(updated_at);type(datetime)"`
+}
+
+func init() {
+    orm.RegisterModel(new(Review), new(ReviewAggregate))
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+    "time"
+
+    "github.com/beego/beego/v2/client/orm"
+)
+
+// SavedSearch stores a guest's property search parameters and the results of its last run
+type SavedSearch struct {
+    Id      int64  `json:"id" orm:"auto;column(id)"`
+    OwnerID string `json:"-" orm:"column(owner_id);index"`
+    Name    string `json:"name" orm:"column(name)"`
+    // Query is the URL-encoded search parameters accepted by /v1/property/list
+    Query         string    `json:"query" orm:"column(query);type(text)"`
+    LastRunAt     time.Time `json:"last_run_at" orm:"column(last_run_at);null;type(datetime)"`
+    LastResultIDs string    `json:"-" orm:"column(last_result_ids);type(text);null"`
+    CreatedAt     time.Time `json:"created_at" orm:"column(created_at);auto_now_add;type(datetime)"`
+}
+
+func init() {
+    orm.RegisterModel(new(SavedSearch))
+}
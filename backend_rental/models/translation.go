@@ -0,0 +1,40 @@
+package models
+
+import (
+    "time"
+
+    "github.com/beego/beego/v2/client/orm"
+)
+
+// Translation entities and fields stored per language code
+const (
+    TranslationEntityProperty = "property"
+    TranslationEntityAmenity  = "amenity"
+    TranslationEntityLocation = "location"
+
+    TranslationFieldDescription = "description"
+    TranslationFieldName        = "name"
+    TranslationFieldCityName    = "city_name"
+    TranslationFieldCountry     = "country"
+)
+
+// Translation holds one field of an entity in one language
+type Translation struct {
+    Id        int64     `orm:"auto;column(id)"`
+    Entity    string    `orm:"size(32);column(entity)"`
+    EntityID  string    `orm:"column(entity_id)"`
+    Field     string    `orm:"size(32);column(field)"`
+    Language  string    `orm:"size(16);column(language)"`
+    Value     string    `orm:"type(text);column(value)"`
+    FetchedAt time.Time `orm:"column(fetched_at);type(datetime)"`
+}
+
+func (t *Translation) TableUnique() [][]string {
+    return [][]string{
+        {"Entity", "EntityID", "Field", "Language"},
+    }
+}
+
+func init() {
+    orm.RegisterModel(new(Translation))
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+    "time"
+
+    "github.com/beego/beego/v2/client/orm"
+)
+
+// UpstreamResponse is a cached upstream GET response, the Postgres tier of
+// the upstream cache
+type UpstreamResponse struct {
+    // Key is the SHA-256 of the normalised request URL
+    Key      string `orm:"pk;column(key);size(64)"`
+    URL      string `orm:"column(url);type(text)"`
+    Endpoint string `orm:"column(endpoint);size(64);index"`
+    Body     string `orm:"column(body);type(text)"`
+    // The response is served as is until ExpiresAt, then served while it is
+    // refreshed in the background until StaleUntil
+    FetchedAt  time.Time `orm:"column(fetched_at);type(datetime)"`
+    ExpiresAt  time.Time `orm:"column(expires_at);type(datetime)"`
+    StaleUntil time.Time `orm:"column(stale_until);type(datetime);index"`
+}
+
+func init() {
+    orm.RegisterModel(new(UpstreamResponse))
+}
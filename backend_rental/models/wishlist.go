@@ -0,0 +1,39 @@
+package models
+
+import (
+    "time"
+
+    "github.com/beego/beego/v2/client/orm"
+)
+
+// Wishlist is a guest's named collection of saved properties. ShareToken is
+// NULL until the wishlist is shared: an empty string would collide with the
+// other unshared wishlists on the unique index.
+type Wishlist struct {
+    Id         int64     `json:"id" orm:"auto;column(id)"`
+    OwnerID    string    `json:"-" orm:"column(owner_id);index"`
+    Name       string    `json:"name" orm:"column(name)"`
+    ShareToken *string   `json:"share_token,omitempty" orm:"column(share_token);null;unique"`
+    CreatedAt  time.Time `json:"created_at" orm:"column(created_at);auto_now_add;type(datetime)"`
+    UpdatedAt  time.Time `json:"updated_at" orm:"column(updated_at);auto_now;type(datetime)"`
+}
+
+// WishlistItem is a property saved to a wishlist
+type WishlistItem struct {
+    Id         int64     `json:"-" orm:"auto;column(id)"`
+    WishlistID int64     `json:"-" orm:"column(wishlist_id);index"`
+    DestID     string    `json:"dest_id" orm:"column(dest_id)"`
+    AddedAt    time.Time `json:"added_at" orm:"column(added_at);auto_now_add;type(datetime)"`
+
+    Property *Property `json:"property,omitempty" orm:"-"`
+}
+
+func (w *WishlistItem) TableUnique() [][]string {
+    return [][]string{
+        {"WishlistID", "DestID"},
+    }
+}
+
+func init() {
+    orm.RegisterModel(new(Wishlist), new(WishlistItem))
+}
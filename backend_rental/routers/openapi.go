@@ -0,0 +1,189 @@
+package routers
+
+import (
+    "net/http"
+    "strings"
+    "sync"
+
+    "backend_rental/controllers"
+    "backend_rental/models"
+    "backend_rental/services"
+    "backend_rental/utils/apiclient"
+    "backend_rental/utils/metrics"
+    "backend_rental/utils/openapi"
+    "github.com/graphql-go/graphql"
+    beego "github.com/beego/beego/v2/server/web"
+    "github.com/beego/beego/v2/server/web/context"
+)
+
+// routeDocs documents the routes registered in router.go. The OpenAPI
+// document lists the routes of the router tree and takes their summary and
+// types from here, so a route missing here is still listed, under its handler
+// name; TestOpenAPIDocumentsRegisteredRoutes fails on it and on entries for
+// routes that are not registered.
+var routeDocs = []openapi.Route{
+    {Method: "GET", Path: "/v1/openapi.json", Tag: "meta", Summary: "This OpenAPI document",
+        Response: map[string]interface{}{}},
+    {Method: "GET", Path: "/metrics", Tag: "meta", Summary: "Prometheus metrics of requests, upstream calls, crawls and database pools",
+        ContentType: "text/plain"},
+    {Method: "GET", Path: "/healthz", Tag: "meta", Summary: "Liveness probe; answers while the process serves HTTP",
+        Response: controllers.StatusResponse{}},
+    {Method: "GET", Path: "/readyz", Tag: "meta",
+        Summary: "Readiness probe checking the config, the database pools and the migrations; 503 while a check fails, with the errors on /v1/admin/status",
+        Response: services.Readiness{}},
+
+    {Method: "GET", Path: "/v1/locations", Tag: "locations", Summary: "List crawled locations",
+        Request: controllers.LocationListRequest{}, Response: openapi.List(models.Location{})},
+    {Method: "GET", Path: "/v1/locations/summary", Tag: "locations", Summary: "Count locations and properties per country",
+        Response: models.BookingSummary{}},
+    {Method: "GET", Path: "/v1/locations/:id", Tag: "locations", Summary: "Get a location",
+        Request: controllers.LocationRequest{}, Response: models.Location{}},
+
+    {Method: "GET", Path: "/v1/booking", Tag: "booking", Deprecated: true,
+        Summary: "Action-based alias of the location and crawl routes; the response is that of the aliased route",
+        Request: controllers.BookingRequest{}},
+    {Method: "GET", Path: "/v1/booking/summary", Tag: "booking", Deprecated: true,
+        Summary: "Alias of GET /v1/locations/summary", Response: models.BookingSummary{}},
+    {Method: "GET", Path: "/v1/booking/process", Tag: "booking", Deprecated: true, Security: openapi.AdminToken,
+        Summary: "Alias of POST /v1/admin/crawls", Response: models.CrawlRun{}, Status: http.StatusAccepted},
+
+    {Method: "GET", Path: "/v1/property/list", Tag: "properties", Summary: "Filter, sort and page properties with facet counts",
+        Request:  controllers.ListPropertiesRequest{},
+        Response: openapi.List(models.Property{}).With("facets", models.PropertyFacets{})},
+    {Method: "GET", Path: "/v1/property/countries-cities", Tag: "locations", Summary: "List the cities of each country",
+        Response: map[string][]string{}},
+    {Method: "POST", Path: "/v1/property/details", Tag: "properties", Summary: "Get stored details of properties by ID",
+        Request: controllers.PropertyDetailsRequest{}, Response: map[string]*models.PropertyDetails{}},
+    {Method: "GET", Path: "/v1/property/description", Tag: "properties", Summary: "Get the localized description of a property",
+        Request: controllers.PropertyContentRequest{}, Response: models.PropertyDescription{}},
+    {Method: "GET", Path: "/v1/property/images", Tag: "properties", Summary: "Get the categorized images of a property",
+        Request: controllers.PropertyContentRequest{}, Response: models.PropertyDescription{}},
+    {Method: "GET", Path: "/img/:photo_id", Tag: "images", Summary: "Get a photo, resized and re-encoded on demand, through the image proxy",
+        Request: controllers.ImageProxyRequest{}, ContentType: "image/*"},
+    {Method: "GET", Path: "/v1/property/photos", Tag: "properties", Summary: "Get every photo of a property grouped by tag, with a cover photo",
+        Request: controllers.PropertyPhotosRequest{}, Response: models.PropertyPhotos{}},
+    {Method: "POST", Path: "/v1/property/photos", Tag: "host photos", Security: openapi.HostID,
+        Summary: "Upload photos of a property as multipart photo parts; they are re-encoded without metadata",
+        Request: controllers.PhotoUploadRequest{}, Response: []models.PropertyPhoto{}, Status: http.StatusCreated},
+    {Method: "PUT", Path: "/v1/property/photos/order", Tag: "host photos", Security: openapi.HostID,
+        Summary: "Reorder the host photos of a property", Request: controllers.ReorderPhotosRequest{}, Response: []models.PropertyPhoto{}},
+    {Method: "PUT", Path: "/v1/property/photos/:photo_id", Tag: "host photos", Security: openapi.HostID,
+        Summary: "Set the tag and caption of a host photo", Request: controllers.UpdatePhotoRequest{}, Response: models.PropertyPhoto{}},
+    {Method: "DELETE", Path: "/v1/property/photos/:photo_id", Tag: "host photos", Security: openapi.HostID,
+        Summary: "Delete a host photo", Request: controllers.HostPhotoRequest{}, Response: controllers.StatusResponse{}},
+    {Method: "GET", Path: "/v1/property/reviews", Tag: "reviews", Summary: "List the approved reviews of a property",
+        Request:  controllers.ListReviewsRequest{},
+        Response: openapi.List(models.Review{}).With("aggregate", models.ReviewAggregate{})},
+    {Method: "POST", Path: "/v1/property/reviews", Tag: "reviews", Summary: "Submit a review for moderation",
+        Request: controllers.SubmitReviewRequest{}, Response: models.Review{}, Status: http.StatusCreated},
+    {Method: "GET", Path: "/v1/property/search", Tag: "properties", Summary: "Full-text property search",
+        Request: controllers.SearchRequest{}, Response: openapi.List(models.PropertySearchResult{})},
+
+    {Method: "GET", Path: "/v1/languages", Tag: "meta", Summary: "List the supported content languages",
+        Response: []models.Language{}},
+    {Method: "GET", Path: "/v1/currencies", Tag: "meta", Summary: "List the FX rates",
+        Response: []models.ExchangeRate{}},
+    {Method: "POST", Path: "/v1/graphql", Tag: "graphql", Summary: "Run a GraphQL query over locations and properties",
+        Request: controllers.GraphQLRequest{}, Response: graphql.Result{}},
+
+    {Method: "GET", Path: "/v1/wishlists", Tag: "wishlists", Security: openapi.GuestID, Summary: "List the guest's wishlists",
+        Response: []models.Wishlist{}},
+    {Method: "POST", Path: "/v1/wishlists", Tag: "wishlists", Security: openapi.GuestID, Summary: "Create a wishlist",
+        Request: controllers.WishlistNameRequest{}, Response: models.Wishlist{}, Status: http.StatusCreated},
+    {Method: "GET", Path: "/v1/wishlists/shared/:token", Tag: "wishlists", Summary: "Get a shared wishlist",
+        Request: controllers.SharedWishlistRequest{}, Response: controllers.WishlistResponse{}},
+    {Method: "GET", Path: "/v1/wishlists/:id", Tag: "wishlists", Security: openapi.GuestID, Summary: "Get a wishlist with its items",
+        Request: controllers.WishlistRequest{}, Response: controllers.WishlistResponse{}},
+    {Method: "PUT", Path: "/v1/wishlists/:id", Tag: "wishlists", Security: openapi.GuestID, Summary: "Rename a wishlist",
+        Request: controllers.RenameWishlistRequest{}, Response: models.Wishlist{}},
+    {Method: "DELETE", Path: "/v1/wishlists/:id", Tag: "wishlists", Security: openapi.GuestID, Summary: "Delete a wishlist",
+        Request: controllers.WishlistRequest{}, Response: controllers.StatusResponse{}},
+    {Method: "POST", Path: "/v1/wishlists/:id/items", Tag: "wishlists", Security: openapi.GuestID, Summary: "Add a property to a wishlist",
+        Request: controllers.AddWishlistItemRequest{}, Response: controllers.WishlistResponse{}},
+    {Method: "DELETE", Path: "/v1/wishlists/:id/items/:dest_id", Tag: "wishlists", Security: openapi.GuestID,
+        Summary: "Remove a property from a wishlist",
+        Request: controllers.RemoveWishlistItemRequest{}, Response: controllers.WishlistResponse{}},
+    {Method: "POST", Path: "/v1/wishlists/:id/share", Tag: "wishlists", Security: openapi.GuestID, Summary: "Share a wishlist by token",
+        Request: controllers.WishlistRequest{}, Response: controllers.ShareResponse{}},
+
+    {Method: "GET", Path: "/v1/saved-searches", Tag: "saved searches", Security: openapi.GuestID,
+        Summary: "List the guest's saved searches", Response: []models.SavedSearch{}},
+    {Method: "POST", Path: "/v1/saved-searches", Tag: "saved searches", Security: openapi.GuestID, Summary: "Save a search",
+        Request: controllers.CreateSavedSearchRequest{}, Response: models.SavedSearch{}, Status: http.StatusCreated},
+    {Method: "DELETE", Path: "/v1/saved-searches/:id", Tag: "saved searches", Security: openapi.GuestID, Summary: "Delete a saved search",
+        Request: controllers.SavedSearchRequest{}, Response: controllers.StatusResponse{}},
+    {Method: "POST", Path: "/v1/saved-searches/:id/run", Tag: "saved searches", Security: openapi.GuestID,
+        Summary: "Run a saved search and report the properties new since the last run",
+        Request: controllers.SavedSearchRequest{}, Response: services.SavedSearchRun{}},
+
+    {Method: "PUT", Path: "/v1/admin/fx-rates", Tag: "admin", Security: openapi.AdminToken, Summary: "Replace the FX rate table",
+        Request: controllers.UpdateRatesRequest{}, Response: []models.ExchangeRate{}},
+    {Method: "GET", Path: "/v1/admin/reviews", Tag: "admin", Security: openapi.AdminToken, Summary: "List reviews by moderation status",
+        Request: controllers.ReviewStatusRequest{}, Response: openapi.List(models.Review{})},
+    {Method: "PUT", Path: "/v1/admin/reviews/:id/moderation", Tag: "admin", Security: openapi.AdminToken, Summary: "Moderate a review",
+        Request: controllers.ModerateReviewRequest{}, Response: models.Review{}},
+    {Method: "POST", Path: "/v1/admin/search/reindex", Tag: "admin", Security: openapi.AdminToken, Summary: "Rebuild the search index",
+        Response: struct {
+            Indexed int `json:"indexed"`
+        }{}},
+    {Method: "POST", Path: "/v1/admin/crawls", Tag: "admin", Security: openapi.AdminToken, Summary: "Start a location crawl",
+        Response: models.CrawlRun{}, Status: http.StatusAccepted},
+    {Method: "GET", Path: "/v1/admin/crawls/:id", Tag: "admin", Security: openapi.AdminToken, Summary: "Get a crawl run",
+        Request: controllers.CrawlRequest{}, Response: models.CrawlRun{}},
+    {Method: "GET", Path: "/v1/admin/upstream-cache", Tag: "admin", Security: openapi.AdminToken, Summary: "Get the upstream cache hit and miss counts per endpoint",
+        Response: map[string]apiclient.CacheStats{}},
+    {Method: "GET", Path: "/v1/admin/status", Tag: "admin", Security: openapi.AdminToken,
+        Summary: "Get the readiness checks with their errors, the upstream error rates and quota, the last runs of the background tasks and the queue depth",
+        Response: services.SystemStatus{}},
+}
+
+var openAPIDocument = sync.OnceValue(buildOpenAPI)
+
+func buildOpenAPI() *openapi.Document {
+    docs := make(map[string]openapi.Route, len(routeDocs))
+    for _, doc := range routeDocs {
+        docs[routeKey(doc.Method, doc.Path)] = doc
+    }
+
+    builder := openapi.New("Rental API", "1.0")
+    for _, route := range registeredRoutes() {
+        if doc, ok := docs[routeKey(route.Method, route.Path)]; ok {
+            route = doc
+        }
+        builder.Add(route)
+    }
+    return builder.Document()
+}
+
+// registeredRoutes lists every method and pattern of the Beego router tree,
+// summarized by the name of its handler method
+func registeredRoutes() []openapi.Route {
+    var routes []openapi.Route
+    seen := map[string]bool{}
+    // A route is in the tree of each of its methods, with all of them
+    for _, info := range beego.BeeApp.Handlers.GetAllControllerInfo() {
+        for method, handler := range info.GetMethod() {
+            method = strings.ToUpper(method)
+            key := routeKey(method, info.GetPattern())
+            if seen[key] {
+                continue
+            }
+            seen[key] = true
+            routes = append(routes, openapi.Route{Method: method, Path: info.GetPattern(), Summary: handler})
+        }
+    }
+    return routes
+}
+
+func routeKey(method, pattern string) string {
+    return strings.ToUpper(method) + " " + openapi.PathOf(pattern)
+}
+
+// serveOpenAPI handles GET requests to /v1/openapi.json
+func serveOpenAPI(ctx *context.Context) {
+    ctx.Output.JSON(openAPIDocument(), false, false)
+}
+
+func serveMetrics(ctx *context.Context) {
+    metrics.Handler().ServeHTTP(ctx.ResponseWriter, ctx.Request)
+}
@@ -0,0 +1,72 @@
+package routers
+
+import (
+    "strings"
+    "testing"
+
+    "backend_rental/utils/openapi"
+)
+
+func TestOpenAPIDocumentsRegisteredRoutes(t *testing.T) {
+    doc := buildOpenAPI()
+
+    routes := registeredRoutes()
+    if len(routes) == 0 {
+        t.Fatal("no routes registered")
+    }
+    documented := map[string]bool{}
+    for _, route := range routeDocs {
+        documented[routeKey(route.Method, route.Path)] = true
+    }
+    registered := map[string]bool{}
+    for _, route := range routes {
+        key := routeKey(route.Method, route.Path)
+        registered[key] = true
+        if doc.Paths[openapi.PathOf(route.Path)][strings.ToLower(route.Method)] == nil {
+            t.Errorf("%s %s is registered but missing from the OpenAPI document", route.Method, route.Path)
+        }
+        if !documented[key] {
+            t.Errorf("%s %s is registered but has no entry in routeDocs", route.Method, route.Path)
+        }
+    }
+    for _, route := range routeDocs {
+        if !registered[routeKey(route.Method, route.Path)] {
+            t.Errorf("%s %s is documented but not registered", route.Method, route.Path)
+        }
+    }
+}
+
+func TestOpenAPIDescribesRequests(t *testing.T) {
+    op := buildOpenAPI().Paths["/v1/wishlists/{id}/items/{dest_id}"]["delete"]
+    if op == nil {
+        t.Fatal("DELETE /v1/wishlists/{id}/items/{dest_id} is not documented")
+    }
+    var names []string
+    for _, param := range op.Parameters {
+        if param.In != "path" || !param.Required {
+            t.Errorf("parameter %+v, want a required path parameter", param)
+        }
+        names = append(names, param.Name)
+    }
+    if strings.Join(names, ",") != "id,dest_id" {
+        t.Errorf("parameters = %v, want id,dest_id", names)
+    }
+
+    review := buildOpenAPI().Paths["/v1/property/reviews"]["post"]
+    body := review.RequestBody.Content["application/json"].Schema
+    if body.Properties["author_name"] == nil || !contains(body.Required, "dest_id") {
+        t.Errorf("review body schema = %+v", body)
+    }
+    if review.Responses["201"].Content == nil {
+        t.Errorf("review responses = %+v, want a 201 body", review.Responses)
+    }
+}
+
+func contains(values []string, value string) bool {
+    for _, v := range values {
+        if v == value {
+            return true
+        }
+    }
+    return false
+}
@@ -2,20 +2,34 @@ package routers
 
 import (
     "backend_rental/controllers"
+    "backend_rental/middleware"
     beego "github.com/beego/beego/v2/server/web"
 )
 
 func init() {
+    beego.ErrorController(&controllers.ErrorController{})
+
+    beego.InsertFilterChain("/*", middleware.ObserveRequests)
+    beego.InsertFilterChain("/*", middleware.LogRequests)
+    beego.InsertFilterChain("/*", middleware.TraceRequests)
+
+    beego.InsertFilter("/*", beego.BeforeRouter, middleware.AssignRequestID)
+    beego.InsertFilter("/v1/*", beego.BeforeRouter, middleware.NegotiateLanguage)
+    beego.InsertFilter("/v1/admin/*", beego.BeforeRouter, middleware.RequireAdmin)
+
     ns := beego.NewNamespace("/v1",
+        beego.NSGet("/openapi.json", serveOpenAPI),
 
-        // Booking routes
-        beego.NSNamespace("/booking",
-            beego.NSInclude(
-                &controllers.BookingController{},
-            ),
-            beego.NSRouter("/summary", &controllers.BookingController{}, "get:Get"),
-            beego.NSRouter("/process", &controllers.BookingController{}, "get:Get"),
-        ),
+
+        // Location routes
+        beego.NSRouter("/locations", &controllers.LocationController{}, "get:List"),
+        beego.NSRouter("/locations/summary", &controllers.LocationController{}, "get:Summary"),
+        beego.NSRouter("/locations/:id", &controllers.LocationController{}, "get:Get"),
+
+        // Deprecated action-based booking routes, aliases of the location and crawl routes
+        beego.NSRouter("/booking", &controllers.BookingController{}, "get:Get"),
+        beego.NSRouter("/booking/summary", &controllers.BookingController{}, "get:Summary"),
+        beego.NSRouter("/booking/process", &controllers.BookingController{}, "get:Process"),
 
         // Property routes
         beego.NSNamespace("/property",
@@ -27,8 +41,53 @@ func init() {
             beego.NSRouter("/details", &controllers.PropertyDetailsController{}, "post:GetPropertyDetails"),
             beego.NSRouter("/description", &controllers.PropertyDescriptionController{}, "get:GetPropertyDescription"),
             beego.NSRouter("/images", &controllers.PropertyImageController{}, "get:GetPropertyDetails"),
+            beego.NSRouter("/photos", &controllers.PropertyImageController{}, "get:GetPhotos"),
+
+            // Host photo routes, scoped by X-Host-ID
+            beego.NSRouter("/photos", &controllers.HostPhotoController{}, "post:UploadPhotos"),
+            beego.NSRouter("/photos/order", &controllers.HostPhotoController{}, "put:ReorderPhotos"),
+            beego.NSRouter("/photos/:photo_id", &controllers.HostPhotoController{}, "put:UpdatePhoto;delete:DeletePhoto"),
+            beego.NSRouter("/reviews", &controllers.ReviewController{}, "get:ListReviews;post:SubmitReview"),
+            beego.NSRouter("/search", &controllers.SearchController{}, "get:Search"),
+        ),
+
+        beego.NSRouter("/languages", &controllers.LanguageController{}, "get:GetLanguages"),
+        beego.NSRouter("/currencies", &controllers.CurrencyController{}, "get:GetRates"),
+        beego.NSRouter("/graphql", &controllers.GraphQLController{}, "post:Query"),
+
+        // Guest wishlist and saved search routes, scoped by X-Guest-ID
+        beego.NSRouter("/wishlists", &controllers.WishlistController{}, "get:ListWishlists;post:CreateWishlist"),
+        beego.NSRouter("/wishlists/shared/:token", &controllers.WishlistController{}, "get:GetSharedWishlist"),
+        beego.NSRouter("/wishlists/:id", &controllers.WishlistController{}, "get:GetWishlist;put:RenameWishlist;delete:DeleteWishlist"),
+        beego.NSRouter("/wishlists/:id/items", &controllers.WishlistController{}, "post:AddItem"),
+        beego.NSRouter("/wishlists/:id/items/:dest_id", &controllers.WishlistController{}, "delete:RemoveItem"),
+        beego.NSRouter("/wishlists/:id/share", &controllers.WishlistController{}, "post:ShareWishlist"),
+        beego.NSRouter("/saved-searches", &controllers.SavedSearchController{}, "get:ListSavedSearches;post:CreateSavedSearch"),
+        beego.NSRouter("/saved-searches/:id", &controllers.SavedSearchController{}, "delete:DeleteSavedSearch"),
+        beego.NSRouter("/saved-searches/:id/run", &controllers.SavedSearchController{}, "post:RunSavedSearch"),
+
+        // Admin routes, guarded by the admin token filter
+        beego.NSNamespace("/admin",
+            beego.NSRouter("/fx-rates", &controllers.CurrencyController{}, "put:UpdateRates"),
+            beego.NSRouter("/reviews", &controllers.ReviewController{}, "get:ListPendingReviews"),
+            beego.NSRouter("/reviews/:id/moderation", &controllers.ReviewController{}, "put:ModerateReview"),
+            beego.NSRouter("/search/reindex", &controllers.SearchController{}, "post:Reindex"),
+            beego.NSRouter("/crawls", &controllers.CrawlController{}, "post:Start"),
+            beego.NSRouter("/crawls/:id", &controllers.CrawlController{}, "get:Get"),
+            beego.NSRouter("/upstream-cache", &controllers.UpstreamCacheController{}, "get:Stats"),
+            beego.NSRouter("/status", &controllers.HealthController{}, "get:Status"),
         ),
     )
     beego.AddNamespace(ns)
+
+    // Image proxy, outside /v1 so photo URLs stay stable across API versions
+    beego.Router("/img/:photo_id", &controllers.ImageProxyController{}, "get:Get")
+
+    // Liveness and readiness probes, unversioned like the scrape endpoint
+    beego.Router("/healthz", &controllers.HealthController{}, "get:Live")
+    beego.Router("/readyz", &controllers.HealthController{}, "get:Ready")
+
+    // Prometheus scrape endpoint, meant to be reachable from the internal network only
+    beego.Get("/metrics", serveMetrics)
 }
 
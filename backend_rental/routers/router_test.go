@@ -0,0 +1,139 @@
+package routers
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "backend_rental/services"
+    "backend_rental/utils/apperrors"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+const testAdminToken = "test-admin-token"
+
+func serve(t *testing.T, method, target string, header http.Header) *httptest.ResponseRecorder {
+    t.Helper()
+    if err := beego.AppConfig.Set("admintoken", testAdminToken); err != nil {
+        t.Fatal(err)
+    }
+    req := httptest.NewRequest(method, target, nil)
+    for name, values := range header {
+        req.Header[name] = values
+    }
+    rec := httptest.NewRecorder()
+    beego.BeeApp.Handlers.ServeHTTP(rec, req)
+    return rec
+}
+
+func TestProcessAliasRequiresAdminToken(t *testing.T) {
+    for _, target := range []string{"/v1/booking/process", "/v1/booking?action=process"} {
+        for name, header := range map[string]http.Header{
+            "no token":    nil,
+            "wrong token": {"X-Admin-Token": {"wrong"}},
+        } {
+            rec := serve(t, "GET", target, header)
+            if rec.Code != http.StatusUnauthorized {
+                t.Errorf("GET %s with %s: status = %d, want 401", target, name, rec.Code)
+            }
+            if rec.Header().Get("Deprecation") != "true" {
+                t.Errorf("GET %s with %s: Deprecation = %q, want true", target, name, rec.Header().Get("Deprecation"))
+            }
+            if want := `</v1/admin/crawls>; rel="successor-version"`; rec.Header().Get("Link") != want {
+                t.Errorf("GET %s with %s: Link = %q, want %q", target, name, rec.Header().Get("Link"), want)
+            }
+        }
+    }
+}
+
+func TestCityAliasValidatesRequest(t *testing.T) {
+    // An oversized country fails validation before the location is looked up
+    rec := serve(t, "GET", "/v1/booking?action=city&id=42&country="+strings.Repeat("a", 101), nil)
+    if rec.Code != http.StatusBadRequest {
+        t.Fatalf("status = %d, want 400", rec.Code)
+    }
+
+    rec = serve(t, "GET", "/v1/booking?action=city", nil)
+    if rec.Code != http.StatusBadRequest {
+        t.Errorf("city without id: status = %d, want 400", rec.Code)
+    }
+}
+
+func TestLocationRoutesValidateRequests(t *testing.T) {
+    for _, target := range []string{
+        "/v1/locations?limit=0",
+        "/v1/locations?limit=101",
+        "/v1/locations/" + strings.Repeat("1", 33),
+    } {
+        if rec := serve(t, "GET", target, nil); rec.Code != http.StatusBadRequest {
+            t.Errorf("GET %s: status = %d, want 400", target, rec.Code)
+        }
+    }
+}
+
+func TestAdminCrawlRoutesRequireAdminToken(t *testing.T) {
+    for _, route := range []struct{ method, target string }{
+        {"POST", "/v1/admin/crawls"},
+        {"GET", "/v1/admin/crawls/1"},
+    } {
+        if rec := serve(t, route.method, route.target, nil); rec.Code != http.StatusUnauthorized {
+            t.Errorf("%s %s without token: status = %d, want 401", route.method, route.target, rec.Code)
+        }
+    }
+
+    // With the token the request reaches the controller, which validates the ID
+    rec := serve(t, "GET", "/v1/admin/crawls/0", http.Header{"X-Admin-Token": {testAdminToken}})
+    if rec.Code != http.StatusBadRequest {
+        t.Errorf("GET /v1/admin/crawls/0 with token: status = %d, want 400", rec.Code)
+    }
+}
+
+func TestSubmitReviewListsScoreErrorsInFieldOrder(t *testing.T) {
+    beego.BConfig.CopyRequestBody = true
+    body := `{"dest_id": "42", "author_name": "Ana", "cleanliness": 0, "location": 11, "value": 0}`
+    for i := 0; i < 10; i++ {
+        req := httptest.NewRequest("POST", "/v1/property/reviews", strings.NewReader(body))
+        req.Header.Set("Content-Type", "application/json")
+        rec := httptest.NewRecorder()
+        beego.BeeApp.Handlers.ServeHTTP(rec, req)
+        if rec.Code != http.StatusBadRequest {
+            t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body)
+        }
+
+        var problem apperrors.Problem
+        if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+            t.Fatal(err)
+        }
+        var fields []string
+        for _, field := range problem.Errors {
+            fields = append(fields, field.Field)
+        }
+        if got := strings.Join(fields, ","); got != "cleanliness,location,value" {
+            t.Fatalf("error fields = %s, want cleanliness,location,value", got)
+        }
+    }
+}
+
+func TestReadyAnswers503WhileACheckFails(t *testing.T) {
+    // No database is connected in this package, so the database check fails
+    rec := serve(t, "GET", "/readyz", nil)
+    if rec.Code != http.StatusServiceUnavailable {
+        t.Fatalf("status = %d, want 503", rec.Code)
+    }
+    var readiness services.Readiness
+    if err := json.Unmarshal(rec.Body.Bytes(), &readiness); err != nil {
+        t.Fatal(err)
+    }
+    if readiness.Ready || readiness.Checks["database"].Status != services.CheckFailed {
+        t.Errorf("readiness = %+v, want not ready with a failed database check", readiness)
+    }
+    if readiness.Checks["database"].Error != "" {
+        t.Errorf("database check error %q is exposed on /readyz", readiness.Checks["database"].Error)
+    }
+
+    if rec := serve(t, "GET", "/healthz", nil); rec.Code != http.StatusOK {
+        t.Errorf("GET /healthz: status = %d, want 200", rec.Code)
+    }
+}
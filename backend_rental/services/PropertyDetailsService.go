@@ -4,26 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+    "log/slog"
 	"sync"
-	"time"
 	"backend_rental/models"
-	"backend_rental/utils/ratelimiter"
+	"backend_rental/utils"
+	"backend_rental/utils/apiclient"
+	"backend_rental/utils/apperrors"
 	"github.com/beego/beego/v2/client/orm"
     beego "github.com/beego/beego/v2/server/web"
+	"gorm.io/gorm"
 )
 
 type PropertyDetailsService struct {
-	rateLimiter *ratelimiter.APIRateLimiter
-	apiKey      string
+	apiClient    *apiclient.APIClient
+	translations *TranslationService
 }
 
 func NewPropertyDetailsService() *PropertyDetailsService {
 	apiKey, _ := beego.AppConfig.String("rapidapi.key") // Load API key from config
 	return &PropertyDetailsService{
-		rateLimiter: ratelimiter.GetInstance(),
-		apiKey:      apiKey,
+		apiClient:    apiclient.NewAPIClient(apiKey),
+		translations: NewTranslationService(),
 	}
 }
 
@@ -41,19 +42,15 @@ func (s *PropertyDetailsService) FetchAndProcessPropertyDetails(propertyIDs []st
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			// Wait for rate limiter
-			if err := s.rateLimiter.Wait(context.Background()); err != nil {
-				fmt.Printf("Rate limiter error for property %s: %v\n", propertyID, err)
-				return
-			}
-
 			// Fetch property details from API
 			details, err := s.fetchPropertyDetailsFromAPI(propertyID)
 			if err != nil {
-				fmt.Printf("Error fetching property %s: %v\n", propertyID, err)
+				slog.Error("error fetching property details", "property_id", propertyID, "error", err)
 				return
 			}
 
+			s.ingestAmenityTranslations(details)
+
 			results <- details
 		}(id)
 	}
@@ -70,7 +67,7 @@ func (s *PropertyDetailsService) FetchAndProcessPropertyDetails(propertyIDs []st
 
 			// Save to database
 			if err := s.savePropertyDetailsToDatabase(detail); err != nil {
-				fmt.Printf("Error saving property %s to database: %v\n", detail.PropertyID, err)
+				slog.Error("error saving property details", "property_id", detail.PropertyID, "error", err)
 			}
 		}
 	}
@@ -78,25 +75,95 @@ func (s *PropertyDetailsService) FetchAndProcessPropertyDetails(propertyIDs []st
 	return propertyDetails, nil
 }
 
-func (s *PropertyDetailsService) fetchPropertyDetailsFromAPI(propertyID string) (*models.PropertyDetails, error) {
-    url := fmt.Sprintf("https://booking-com18.p.rapidapi.com/stays/detail?hotelId=%s&checkinDate=2025-01-09&checkoutDate=2025-01-23&units=metric", propertyID)
+// ingestAmenityTranslations stores the amenity names in each configured
+// content language, keyed by the default-language name
+func (s *PropertyDetailsService) ingestAmenityTranslations(details *models.PropertyDetails) {
+	for _, language := range contentLanguages() {
+		names, err := s.fetchFacilityNames(details.PropertyID, language)
+		if err != nil {
+			slog.Error("error fetching amenity names", "language", language, "property_id", details.PropertyID, "error", err)
+			continue
+		}
 
-    req, err := http.NewRequest("GET", url, nil)
-    if err != nil {
-        return nil, err
-    }
+		// Facilities come back in the same order in every language
+		if len(names) != len(details.Amenities) {
+			slog.Warn("skipping amenity names that do not match the amenities", "language", language,
+				"property_id", details.PropertyID, "names", len(names), "amenities", len(details.Amenities))
+			continue
+		}
+		for i, amenity := range details.Amenities {
+			if err := s.translations.Save(models.TranslationEntityAmenity, amenity.Name,
+				models.TranslationFieldName, language, names[i]); err != nil {
+				slog.Error("error saving amenity translation", "error", err)
+			}
+		}
+	}
+}
 
-    req.Header.Add("x-rapidapi-host", "booking-com18.p.rapidapi.com")
-    req.Header.Add("x-rapidapi-key", s.apiKey)
+func (s *PropertyDetailsService) fetchFacilityNames(propertyID, language string) ([]string, error) {
+	body, err := s.requestPropertyDetail(propertyID, language)
+	if err != nil {
+		return nil, err
+	}
 
-    client := &http.Client{Timeout: 10 * time.Second}
-    resp, err := client.Do(req)
-    if err != nil {
-        return nil, err
+	var apiResponse struct {
+		Data struct {
+			FacilitiesBlock struct {
+				Facilities []struct {
+					Name string `json:"name"`
+				} `json:"facilities"`
+			} `json:"facilities_block"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(apiResponse.Data.FacilitiesBlock.Facilities))
+	for _, facility := range apiResponse.Data.FacilitiesBlock.Facilities {
+		names = append(names, facility.Name)
+	}
+	return names, nil
+}
+
+// LocalizeAmenities replaces amenity names with their translations in the
+// first available language of the fallback chain
+func (s *PropertyDetailsService) LocalizeAmenities(details map[string]*models.PropertyDetails, languages []string) error {
+	var names []string
+	for _, detail := range details {
+		for _, amenity := range detail.Amenities {
+			names = append(names, amenity.Name)
+		}
+	}
+
+	translated, err := s.translations.Lookup(models.TranslationEntityAmenity, names, models.TranslationFieldName, languages)
+	if err != nil {
+		return err
+	}
+
+	for _, detail := range details {
+		for i, amenity := range detail.Amenities {
+			if name, ok := translated[amenity.Name]; ok {
+				detail.Amenities[i].Name = name
+			}
+		}
+	}
+	return nil
+}
+
+// requestPropertyDetail calls the upstream stay detail endpoint, optionally in
+// a given language, through the response cache
+func (s *PropertyDetailsService) requestPropertyDetail(propertyID, language string) ([]byte, error) {
+    url := fmt.Sprintf("https://booking-com18.p.rapidapi.com/stays/detail?hotelId=%s&checkinDate=2025-01-09&checkoutDate=2025-01-23&units=metric", propertyID)
+    if language != "" {
+        url += "&languageCode=" + language
     }
-    defer resp.Body.Close()
 
-    body, err := io.ReadAll(resp.Body)
+    return s.apiClient.MakeRequest(context.Background(), url)
+}
+
+func (s *PropertyDetailsService) fetchPropertyDetailsFromAPI(propertyID string) (*models.PropertyDetails, error) {
+    body, err := s.requestPropertyDetail(propertyID, "")
     if err != nil {
         return nil, err
     }
@@ -118,6 +185,12 @@ func (s *PropertyDetailsService) fetchPropertyDetailsFromAPI(propertyID string)
             Location struct {
                 CityName string `json:"city_name"`
             } `json:"location"`
+            ProductPriceBreakdown struct {
+                GrossAmount struct {
+                    Value    float64 `json:"value"`
+                    Currency string  `json:"currency"`
+                } `json:"gross_amount"`
+            } `json:"product_price_breakdown"`
         } `json:"data"`
     }
 
@@ -138,6 +211,8 @@ func (s *PropertyDetailsService) fetchPropertyDetailsFromAPI(propertyID string)
         Bedrooms:     apiResponse.Data.BlockCount,
         Amenities:    []models.Facility{},
         CityID:       &cityID, // Use pointer to int here
+        Price:        apiResponse.Data.ProductPriceBreakdown.GrossAmount.Value,
+        Currency:     apiResponse.Data.ProductPriceBreakdown.GrossAmount.Currency,
     }
 
     // Extract bathroom count
@@ -184,6 +259,8 @@ func (s *PropertyDetailsService) savePropertyDetailsToDatabase(details *models.P
 		Bedrooms:    details.Bedrooms,
 		Bathroom:    details.Bathroom,
 		Amenities:   fmt.Sprintf("%v", details.Amenities), // Store amenities as a JSON string or CSV
+		Price:       details.Price,
+		Currency:    details.Currency,
 	}
 
 	// Insert the data into the database
@@ -192,7 +269,54 @@ func (s *PropertyDetailsService) savePropertyDetailsToDatabase(details *models.P
 		return fmt.Errorf("error inserting property details into the database: %v", err)
 	}
 
-	return nil
+	// Keep the listed property's searchable attributes in step with the detail
+	updates := map[string]interface{}{
+		"type":      details.Type,
+		"bedrooms":  details.Bedrooms,
+		"bathrooms": details.Bathroom,
+	}
+	if details.Currency != "" {
+		updates["price"] = details.Price
+		updates["currency"] = details.Currency
+	}
+	err = utils.GetDB().Model(&models.Property{}).
+		Where("dest_id = ?", details.PropertyID).
+		Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("error updating property %s: %v", details.PropertyID, err)
+	}
+
+	if err := s.savePropertyAmenities(details); err != nil {
+		return err
+	}
+
+	return NewSearchService(utils.GetDB()).IndexProperty(context.Background(), details.PropertyID)
+}
+
+// savePropertyAmenities replaces the property's amenity rows used for filtering and facets
+func (s *PropertyDetailsService) savePropertyAmenities(details *models.PropertyDetails) error {
+	return utils.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("dest_id = ?", details.PropertyID).Delete(&models.PropertyAmenity{}).Error; err != nil {
+			return fmt.Errorf("error clearing amenities for property %s: %v", details.PropertyID, err)
+		}
+
+		seen := make(map[string]bool)
+		amenities := make([]models.PropertyAmenity, 0, len(details.Amenities))
+		for _, amenity := range details.Amenities {
+			if amenity.Name == "" || seen[amenity.Name] {
+				continue
+			}
+			seen[amenity.Name] = true
+			amenities = append(amenities, models.PropertyAmenity{DestID: details.PropertyID, Name: amenity.Name})
+		}
+		if len(amenities) == 0 {
+			return nil
+		}
+		if err := tx.Create(&amenities).Error; err != nil {
+			return fmt.Errorf("error saving amenities for property %s: %v", details.PropertyID, err)
+		}
+		return nil
+	})
 }
 
 func (s *PropertyDetailsService) FetchStoredPropertyDetails(propertyIDs []string) (map[string]*models.PropertyDetails, error) {
@@ -202,6 +326,9 @@ func (s *PropertyDetailsService) FetchStoredPropertyDetails(propertyIDs []string
 	for _, id := range propertyIDs {
 		var property models.PropertyDetails
 		err := o.QueryTable("property_details").Filter("property_id", id).One(&property)
+		if err == orm.ErrNoRows {
+			return nil, apperrors.NotFound("property %s has no stored details", id)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch details for property ID %s: %v", id, err)
 		}
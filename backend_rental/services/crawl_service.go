@@ -0,0 +1,156 @@
+package services
+
+import (
+    "log/slog"
+    "sync"
+    "time"
+
+    "backend_rental/models"
+    "backend_rental/utils"
+    "backend_rental/utils/apiclient"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/metrics"
+    "github.com/beego/beego/v2/client/orm"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+// Crawl triggers
+const (
+    CrawlTriggerAdmin     = "admin"
+    CrawlTriggerStartup   = "startup"
+    CrawlTriggerLegacyAPI = "legacy_api"
+)
+
+var (
+    crawlMu      sync.Mutex
+    crawlRunning bool
+    // crawlWatchers are notified when the running crawl finishes
+    crawlWatchers = map[int64][]chan models.CrawlRun{}
+)
+
+// CrawlService runs the upstream location crawl in the background and records
+// each run; only one crawl runs at a time
+type CrawlService struct {
+    processing *LocationProcessingService
+}
+
+func NewCrawlService() *CrawlService {
+    rapidAPIKey, _ := beego.AppConfig.String("rapidapikey")
+    return &CrawlService{
+        processing: NewLocationProcessingService(apiclient.NewAPIClient(rapidAPIKey)),
+    }
+}
+
+// Start records a new run and crawls in the background
+func (s *CrawlService) Start(triggeredBy string) (*models.CrawlRun, error) {
+    crawlMu.Lock()
+    defer crawlMu.Unlock()
+    if crawlRunning {
+        return nil, apperrors.Conflict("a crawl is already running")
+    }
+
+    o := orm.NewOrm()
+    // Runs still marked running were cut short by a restart
+    _, err := o.QueryTable(new(models.CrawlRun)).
+        Filter("status", models.CrawlStatusRunning).
+        Update(orm.Params{
+            "status":      models.CrawlStatusFailed,
+            "error":       "interrupted",
+            "finished_at": time.Now(),
+        })
+    if err != nil {
+        return nil, err
+    }
+
+    run := &models.CrawlRun{TriggeredBy: triggeredBy, Status: models.CrawlStatusRunning}
+    if _, err := o.Insert(run); err != nil {
+        return nil, err
+    }
+
+    crawlRunning = true
+    go s.run(*run)
+    return run, nil
+}
+
+func (s *CrawlService) run(run models.CrawlRun) {
+    metrics.CrawlRunning.Set(1)
+    defer func() {
+        crawlMu.Lock()
+        crawlRunning = false
+        crawlMu.Unlock()
+        metrics.CrawlRunning.Set(0)
+    }()
+
+    run.Status = models.CrawlStatusSucceeded
+    err := s.processing.ProcessLocationsFromQueries(utils.GenerateLocationQueries())
+    if err != nil {
+        slog.Error("crawl failed", "crawl_id", run.Id, "error", err)
+        run.Status = models.CrawlStatusFailed
+        run.Error = err.Error()
+    }
+    RecordTask(TaskLocationCrawl, err)
+    run.FinishedAt = time.Now()
+    metrics.CrawlRuns.WithLabelValues(run.Status).Inc()
+    if run.Status == models.CrawlStatusSucceeded {
+        metrics.CrawlLastSuccess.Set(float64(run.FinishedAt.Unix()))
+    }
+
+    if _, err := orm.NewOrm().Update(&run, "Status", "Error", "FinishedAt"); err != nil {
+        slog.Error("error recording crawl", "crawl_id", run.Id, "error", err)
+    }
+
+    crawlMu.Lock()
+    for _, watcher := range crawlWatchers[run.Id] {
+        watcher <- run
+        close(watcher)
+    }
+    delete(crawlWatchers, run.Id)
+    crawlMu.Unlock()
+}
+
+// Watch returns a channel that yields the current state of a run and, while it
+// is running, its final state; the channel is closed after the final state.
+// Call stop to give up watching early.
+func (s *CrawlService) Watch(id int64) (states <-chan models.CrawlRun, stop func(), err error) {
+    crawlMu.Lock()
+    defer crawlMu.Unlock()
+
+    run, err := s.GetRun(id)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    // Both states fit in the buffer, so the crawl never waits on a watcher
+    watcher := make(chan models.CrawlRun, 2)
+    watcher <- *run
+    if run.Status != models.CrawlStatusRunning || !crawlRunning {
+        close(watcher)
+        return watcher, func() {}, nil
+    }
+    crawlWatchers[id] = append(crawlWatchers[id], watcher)
+
+    stop = func() {
+        crawlMu.Lock()
+        defer crawlMu.Unlock()
+        watchers := crawlWatchers[id]
+        for i, w := range watchers {
+            if w == watcher {
+                crawlWatchers[id] = append(watchers[:i], watchers[i+1:]...)
+                break
+            }
+        }
+    }
+    return watcher, stop, nil
+}
+
+// GetRun returns a recorded crawl run
+func (s *CrawlService) GetRun(id int64) (*models.CrawlRun, error) {
+    run := &models.CrawlRun{Id: id}
+    if err := orm.NewOrm().Read(run); err != nil {
+        if err == orm.ErrNoRows {
+            return nil, apperrors.NotFound("crawl %d not found", id)
+        }
+        return nil, err
+    }
+    return run, nil
+}
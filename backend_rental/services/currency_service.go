@@ -0,0 +1,232 @@
+// services/currency_service.go
+package services
+
+import (
+    "encoding/json"
+    "fmt"
+    "log/slog"
+    "math"
+    "os"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "backend_rental/models"
+    "backend_rental/utils/apperrors"
+    "github.com/beego/beego/v2/client/orm"
+)
+
+// currencyMinorUnits lists the ISO 4217 currencies whose minor unit is not 2 digits
+var currencyMinorUnits = map[string]int{
+    "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+    "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+    "BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+    "CLF": 4, "UYW": 4,
+}
+
+var (
+    exchangeRates = make(map[string]float64)
+    // ratesUpdatedAt is when the loaded rates were stored
+    ratesUpdatedAt time.Time
+    ratesMutex     sync.RWMutex
+)
+
+// FXRates is the file and admin payload format: units of each currency per one unit of Base
+type FXRates struct {
+    Base  string             `json:"base"`
+    Rates map[string]float64 `json:"rates"`
+}
+
+type CurrencyService struct{}
+
+func NewCurrencyService() *CurrencyService {
+    return &CurrencyService{}
+}
+
+// MinorUnits returns the number of decimal places used by an ISO 4217 currency
+func MinorUnits(currency string) int {
+    if units, ok := currencyMinorUnits[strings.ToUpper(currency)]; ok {
+        return units
+    }
+    return 2
+}
+
+// RoundToMinorUnits rounds half away from zero to the currency's minor units
+func RoundToMinorUnits(amount float64, currency string) float64 {
+    scale := math.Pow10(MinorUnits(currency))
+    // Trim binary noise first so 1.005 rounds like the decimal it represents
+    scaled, _ := strconv.ParseFloat(strconv.FormatFloat(amount*scale, 'f', 6, 64), 64)
+    return math.Round(scaled) / scale
+}
+
+// LoadRatesFromFile replaces the stored rates with the contents of a JSON file
+func (s *CurrencyService) LoadRatesFromFile(path string) error {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return fmt.Errorf("failed to read FX rates file: %v", err)
+    }
+
+    var rates FXRates
+    if err := json.Unmarshal(data, &rates); err != nil {
+        return fmt.Errorf("failed to parse FX rates file: %v", err)
+    }
+
+    return s.ReplaceRates(rates)
+}
+
+// ReplaceRates validates and stores a full rate table, then refreshes the in-memory copy
+func (s *CurrencyService) ReplaceRates(rates FXRates) error {
+    base := strings.ToUpper(strings.TrimSpace(rates.Base))
+    if len(base) != 3 {
+        return apperrors.Validation("invalid base currency: %q", rates.Base)
+    }
+
+    normalized := map[string]float64{base: 1}
+    for currency, rate := range rates.Rates {
+        currency = strings.ToUpper(strings.TrimSpace(currency))
+        if len(currency) != 3 {
+            return apperrors.Validation("invalid currency code: %q", currency)
+        }
+        if rate <= 0 {
+            return apperrors.Validation("invalid rate for %s: %v", currency, rate)
+        }
+        normalized[currency] = rate
+    }
+    if normalized[base] != 1 {
+        return apperrors.Validation("rate for base currency %s must be 1", base)
+    }
+
+    o := orm.NewOrm()
+    tx, err := o.Begin()
+    if err != nil {
+        return err
+    }
+
+    if _, err := tx.Raw("DELETE FROM exchange_rate").Exec(); err != nil {
+        tx.Rollback()
+        return fmt.Errorf("error clearing exchange rates: %v", err)
+    }
+
+    now := time.Now()
+    for currency, rate := range normalized {
+        row := models.ExchangeRate{Currency: currency, Base: base, Rate: rate, UpdatedAt: now}
+        if _, err := tx.Insert(&row); err != nil {
+            tx.Rollback()
+            return fmt.Errorf("error saving exchange rate %s: %v", currency, err)
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return err
+    }
+
+    setExchangeRates(normalized, now)
+    slog.Info("loaded exchange rates", "count", len(normalized), "base", base)
+    return nil
+}
+
+// LoadRates refreshes the in-memory rates from the database
+func (s *CurrencyService) LoadRates() error {
+    rows, err := s.ListRates()
+    if err != nil {
+        return err
+    }
+
+    rates := make(map[string]float64, len(rows))
+    var updatedAt time.Time
+    for _, row := range rows {
+        rates[row.Currency] = row.Rate
+        if row.UpdatedAt.After(updatedAt) {
+            updatedAt = row.UpdatedAt
+        }
+    }
+
+    setExchangeRates(rates, updatedAt)
+    return nil
+}
+
+// ListRates returns the stored exchange rates ordered by currency
+func (s *CurrencyService) ListRates() ([]models.ExchangeRate, error) {
+    var rows []models.ExchangeRate
+    _, err := orm.NewOrm().QueryTable(new(models.ExchangeRate)).OrderBy("currency").Limit(-1).All(&rows)
+    return rows, err
+}
+
+// IsSupportedCurrency reports whether a rate is known for the currency
+func (s *CurrencyService) IsSupportedCurrency(currency string) bool {
+    ratesMutex.RLock()
+    defer ratesMutex.RUnlock()
+    _, ok := exchangeRates[strings.ToUpper(currency)]
+    return ok
+}
+
+// Convert converts an amount between currencies via the base currency and
+// rounds the result to the target currency's minor units
+func (s *CurrencyService) Convert(amount float64, from, to string) (models.Money, error) {
+    from = strings.ToUpper(from)
+    to = strings.ToUpper(to)
+
+    if from == to {
+        return models.Money{Amount: RoundToMinorUnits(amount, to), Currency: to}, nil
+    }
+
+    ratesMutex.RLock()
+    fromRate, fromOK := exchangeRates[from]
+    toRate, toOK := exchangeRates[to]
+    ratesMutex.RUnlock()
+
+    if !fromOK {
+        return models.Money{}, apperrors.Validation("no exchange rate for currency %s", from)
+    }
+    if !toOK {
+        return models.Money{}, apperrors.Validation("no exchange rate for currency %s", to)
+    }
+
+    return models.Money{
+        Amount:   RoundToMinorUnits(amount/fromRate*toRate, to),
+        Currency: to,
+    }, nil
+}
+
+// ApplyDisplayPrices sets the display price of every priced property in the
+// target currency; properties priced in a currency without a rate keep none
+func (s *CurrencyService) ApplyDisplayPrices(properties []models.Property, currency string) error {
+    if !s.IsSupportedCurrency(currency) {
+        return apperrors.Validation("unsupported currency: %s", currency)
+    }
+    for i := range properties {
+        properties[i].DisplayPrice = s.DisplayPrice(properties[i].DestID, properties[i].Price,
+            properties[i].Currency, currency)
+    }
+    return nil
+}
+
+// DisplayPrice converts a property's price for display, or returns nil when
+// it is unpriced or its currency has no exchange rate
+func (s *CurrencyService) DisplayPrice(destID string, amount float64, from, to string) *models.Money {
+    if from == "" {
+        return nil
+    }
+    price, err := s.Convert(amount, from, to)
+    if err != nil {
+        slog.Warn("no display price for property", "dest_id", destID, "currency", from, "error", err)
+        return nil
+    }
+    return &price
+}
+
+// RatesUpdatedAt returns when the loaded exchange rates were stored, or the
+// zero time when none are loaded
+func (s *CurrencyService) RatesUpdatedAt() time.Time {
+    ratesMutex.RLock()
+    defer ratesMutex.RUnlock()
+    return ratesUpdatedAt
+}
+
+func setExchangeRates(rates map[string]float64, updatedAt time.Time) {
+    ratesMutex.Lock()
+    defer ratesMutex.Unlock()
+    exchangeRates = rates
+    ratesUpdatedAt = updatedAt
+}
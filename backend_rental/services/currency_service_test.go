@@ -0,0 +1,74 @@
+package services
+
+import (
+    "testing"
+    "time"
+
+    "backend_rental/models"
+    "backend_rental/utils/apperrors"
+)
+
+func TestRoundToMinorUnits(t *testing.T) {
+    cases := []struct {
+        amount   float64
+        currency string
+        want     float64
+    }{
+        {1.005, "USD", 1.01},
+        {-1.005, "EUR", -1.01},
+        {1234.5, "JPY", 1235},
+        {12.3455, "KWD", 12.346},
+        {0.125, "xyz", 0.13},
+    }
+
+    for _, tc := range cases {
+        if got := RoundToMinorUnits(tc.amount, tc.currency); got != tc.want {
+            t.Errorf("RoundToMinorUnits(%v, %s) = %v, want %v", tc.amount, tc.currency, got, tc.want)
+        }
+    }
+}
+
+func TestConvertViaBaseCurrency(t *testing.T) {
+    setExchangeRates(map[string]float64{"USD": 1, "EUR": 0.5, "JPY": 150}, time.Time{})
+    defer setExchangeRates(map[string]float64{}, time.Time{})
+
+    service := NewCurrencyService()
+    got, err := service.Convert(10, "EUR", "JPY")
+    if err != nil {
+        t.Fatalf("Convert returned error: %v", err)
+    }
+    if got.Amount != 3000 || got.Currency != "JPY" {
+        t.Errorf("Convert(10, EUR, JPY) = %+v, want 3000 JPY", got)
+    }
+
+    if _, err := service.Convert(10, "EUR", "GBP"); err == nil {
+        t.Error("Convert to a currency without a rate should fail")
+    }
+}
+
+func TestApplyDisplayPricesSkipsCurrenciesWithoutRate(t *testing.T) {
+    setExchangeRates(map[string]float64{"USD": 1, "EUR": 0.5}, time.Time{})
+    defer setExchangeRates(map[string]float64{}, time.Time{})
+
+    properties := []models.Property{
+        {DestID: "1", Price: 10, Currency: "EUR"},
+        {DestID: "2", Price: 10, Currency: "XYZ"},
+        {DestID: "3"},
+    }
+    service := NewCurrencyService()
+    if err := service.ApplyDisplayPrices(properties, "USD"); err != nil {
+        t.Fatalf("ApplyDisplayPrices returned error: %v", err)
+    }
+    if price := properties[0].DisplayPrice; price == nil || price.Amount != 20 || price.Currency != "USD" {
+        t.Errorf("display price of the EUR property = %+v, want 20 USD", price)
+    }
+    if properties[1].DisplayPrice != nil || properties[2].DisplayPrice != nil {
+        t.Errorf("display prices = %+v, %+v; want none without a rate or a price",
+            properties[1].DisplayPrice, properties[2].DisplayPrice)
+    }
+
+    err := service.ApplyDisplayPrices(properties, "GBP")
+    if apperrors.KindOf(err) != apperrors.KindValidation {
+        t.Errorf("ApplyDisplayPrices to a currency without a rate = %v, want a validation error", err)
+    }
+}
@@ -1,13 +1,13 @@
 package services
 
 import (
+    "log/slog"
     "backend_rental/common"
-    "log"
 )
 
 // Remove PropertyService declaration here if already in property_service.go
 func (ps *PropertyService) InitDatabase() error {
-    log.Println("Initializing database in services...")
+    slog.Debug("initializing database in services")
     return nil
 }
 
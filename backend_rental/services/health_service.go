@@ -0,0 +1,194 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+
+    "backend_rental/models"
+    "backend_rental/utils"
+    "backend_rental/utils/apiclient"
+    "github.com/beego/beego/v2/client/orm"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+// Readiness check outcomes
+const (
+    CheckOK     = "ok"
+    CheckFailed = "failed"
+)
+
+// readinessTimeout bounds each readiness check so a hung database fails the
+// probe instead of stalling it
+const readinessTimeout = 2 * time.Second
+
+// requiredSettings must be set in app.conf for requests to be served
+var requiredSettings = []string{"dbuser", "dbpassword", "dbhost", "dbport", "dbname", "rapidapikey"}
+
+// CheckResult is the outcome of one readiness check
+type CheckResult struct {
+    Status string `json:"status"`
+    // Error is only shown to admins; see Readiness.WithoutErrors
+    Error      string `json:"error,omitempty"`
+    DurationMs int64  `json:"duration_ms"`
+}
+
+// Readiness reports whether the service can serve requests
+type Readiness struct {
+    Ready  bool                   `json:"ready"`
+    Checks map[string]CheckResult `json:"checks"`
+}
+
+// UpstreamStatus reports on the calls to the upstream API
+type UpstreamStatus struct {
+    Endpoints map[string]apiclient.EndpointStatus `json:"endpoints"`
+    Quota     apiclient.QuotaStatus               `json:"quota"`
+}
+
+// QueueStatus is the depth of a background job queue
+type QueueStatus struct {
+    Pending  int `json:"pending"`
+    Capacity int `json:"capacity"`
+}
+
+// SystemStatus is the dependency and background work status shown to admins
+type SystemStatus struct {
+    // Readiness carries the errors of failed checks, which /readyz omits
+    Readiness *Readiness             `json:"readiness"`
+    Upstream  UpstreamStatus         `json:"upstream"`
+    Tasks     map[string]TaskStatus  `json:"tasks"`
+    Queues    map[string]QueueStatus `json:"queues"`
+    // CrawlRunning is set while a location crawl runs
+    CrawlRunning bool `json:"crawl_running"`
+}
+
+// HealthService answers the readiness probe and the admin status
+type HealthService struct{}
+
+func NewHealthService() *HealthService {
+    return &HealthService{}
+}
+
+// Readiness runs the readiness checks: the required settings are loaded, both
+// database pools answer a ping and the migrations have run
+func (s *HealthService) Readiness(ctx context.Context) *Readiness {
+    checks := []struct {
+        name  string
+        check func(context.Context) error
+    }{
+        {"config", checkConfig},
+        {"database", checkDatabase},
+        {"migrations", checkMigrations},
+    }
+
+    readiness := &Readiness{Ready: true, Checks: make(map[string]CheckResult, len(checks))}
+    for _, c := range checks {
+        checkCtx, cancel := context.WithTimeout(ctx, readinessTimeout)
+        start := time.Now()
+        err := c.check(checkCtx)
+        cancel()
+
+        result := CheckResult{Status: CheckOK, DurationMs: time.Since(start).Milliseconds()}
+        if err != nil {
+            result.Status = CheckFailed
+            result.Error = err.Error()
+            readiness.Ready = false
+        }
+        readiness.Checks[c.name] = result
+    }
+    return readiness
+}
+
+// WithoutErrors returns the readiness with only the status and duration of
+// each check, for the unauthenticated probe
+func (r *Readiness) WithoutErrors() *Readiness {
+    public := &Readiness{Ready: r.Ready, Checks: make(map[string]CheckResult, len(r.Checks))}
+    for name, result := range r.Checks {
+        result.Error = ""
+        public.Checks[name] = result
+    }
+    return public
+}
+
+func checkConfig(ctx context.Context) error {
+    var missing []string
+    for _, key := range requiredSettings {
+        if beego.AppConfig.DefaultString(key, "") == "" {
+            missing = append(missing, key)
+        }
+    }
+    if len(missing) > 0 {
+        return fmt.Errorf("missing settings: %s", strings.Join(missing, ", "))
+    }
+    return nil
+}
+
+func checkDatabase(ctx context.Context) error {
+    if utils.GetDB() == nil {
+        return errors.New("database not initialized")
+    }
+    gormDB, err := utils.GetDB().DB()
+    if err != nil {
+        return err
+    }
+    if err := gormDB.PingContext(ctx); err != nil {
+        return fmt.Errorf("GORM pool: %v", err)
+    }
+    beegoDB, err := orm.GetDB("default")
+    if err != nil {
+        return err
+    }
+    if err := beegoDB.PingContext(ctx); err != nil {
+        return fmt.Errorf("Beego ORM pool: %v", err)
+    }
+    return nil
+}
+
+func checkMigrations(ctx context.Context) error {
+    if !utils.MigrationsApplied() {
+        return errors.New("migrations have not run")
+    }
+    return nil
+}
+
+// Status returns the readiness checks, the upstream error rates and quota,
+// the last runs of the background tasks and the depth of the refresh queue. The last successful
+// crawl is read from the crawl runs when none finished since startup.
+func (s *HealthService) Status(ctx context.Context) (*SystemStatus, error) {
+    tasks := TaskStatuses()
+    if tasks[TaskLocationCrawl].LastSuccess == nil {
+        var run models.CrawlRun
+        err := orm.NewOrm().QueryTable(new(models.CrawlRun)).
+            Filter("status", models.CrawlStatusSucceeded).
+            OrderBy("-finished_at").
+            OneWithCtx(ctx, &run)
+        if err != nil && err != orm.ErrNoRows {
+            return nil, fmt.Errorf("error loading the last crawl: %v", err)
+        }
+        if err == nil {
+            crawl := tasks[TaskLocationCrawl]
+            crawl.LastSuccess = &run.FinishedAt
+            tasks[TaskLocationCrawl] = crawl
+        }
+    }
+
+    crawlMu.Lock()
+    running := crawlRunning
+    crawlMu.Unlock()
+
+    queue := DefaultRefreshQueue()
+    return &SystemStatus{
+        Readiness: s.Readiness(ctx),
+        Upstream: UpstreamStatus{
+            Endpoints: apiclient.UpstreamStatus(),
+            Quota:     apiclient.Quota(),
+        },
+        Tasks: tasks,
+        Queues: map[string]QueueStatus{
+            "refresh": {Pending: queue.Pending(), Capacity: queue.Capacity()},
+        },
+        CrawlRunning: running,
+    }, nil
+}
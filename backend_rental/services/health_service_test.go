@@ -0,0 +1,89 @@
+package services
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    "backend_rental/utils"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+// setSettings sets app.conf values for the duration of the test
+func setSettings(t *testing.T, settings map[string]string) {
+    t.Helper()
+    for key, value := range settings {
+        previous := beego.AppConfig.DefaultString(key, "")
+        if err := beego.AppConfig.Set(key, value); err != nil {
+            t.Fatal(err)
+        }
+        t.Cleanup(func() { beego.AppConfig.Set(key, previous) })
+    }
+}
+
+func TestReadinessFailsOnMissingSettings(t *testing.T) {
+    settings := map[string]string{}
+    for _, key := range requiredSettings {
+        settings[key] = "set"
+    }
+    settings["dbhost"] = ""
+    settings["rapidapikey"] = ""
+    setSettings(t, settings)
+
+    readiness := NewHealthService().Readiness(context.Background())
+    if readiness.Ready {
+        t.Fatal("ready with missing settings")
+    }
+    config := readiness.Checks["config"]
+    if config.Status != CheckFailed || config.Error != "missing settings: dbhost, rapidapikey" {
+        t.Errorf("config check = %+v, want failed for dbhost, rapidapikey", config)
+    }
+
+    public := readiness.WithoutErrors()
+    if public.Ready || public.Checks["config"].Status != CheckFailed || public.Checks["config"].Error != "" {
+        t.Errorf("public config check = %+v, want failed without the error", public.Checks["config"])
+    }
+    if readiness.Checks["config"].Error == "" {
+        t.Error("WithoutErrors cleared the errors of the original")
+    }
+}
+
+func TestReadinessFailsBeforeMigrations(t *testing.T) {
+    if utils.MigrationsApplied() {
+        t.Skip("the test database is already migrated")
+    }
+    settings := map[string]string{}
+    for _, key := range requiredSettings {
+        settings[key] = "set"
+    }
+    setSettings(t, settings)
+
+    readiness := NewHealthService().Readiness(context.Background())
+    if readiness.Ready {
+        t.Fatal("ready before the migrations ran")
+    }
+    if config := readiness.Checks["config"]; config.Status != CheckOK {
+        t.Errorf("config check = %+v, want ok", config)
+    }
+    if check := readiness.Checks["database"]; check.Status != CheckFailed {
+        t.Errorf("database check = %+v, want failed", check)
+    }
+    migrations := readiness.Checks["migrations"]
+    if migrations.Status != CheckFailed || !strings.Contains(migrations.Error, "migrations") {
+        t.Errorf("migrations check = %+v, want failed", migrations)
+    }
+}
+
+func TestReadinessPassesWithDatabase(t *testing.T) {
+    requireDB(t)
+    settings := map[string]string{}
+    for _, key := range requiredSettings {
+        settings[key] = "set"
+    }
+    setSettings(t, settings)
+
+    readiness := NewHealthService().Readiness(context.Background())
+    if !readiness.Ready {
+        t.Errorf("not ready with a migrated database: %+v", readiness.Checks)
+    }
+}
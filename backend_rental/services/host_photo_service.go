@@ -0,0 +1,328 @@
+package services
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "image"
+    "net/http"
+    "time"
+
+    "backend_rental/models"
+    "backend_rental/utils"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/imageproc"
+    "backend_rental/utils/tracing"
+    "github.com/beego/beego/v2/client/orm"
+    beego "github.com/beego/beego/v2/server/web"
+    "go.opentelemetry.io/otel/attribute"
+)
+
+// Upload limits, overridden by photouploadmaxsize (bytes) and photothumbnailwidth
+const (
+    DefaultPhotoUploadMaxSize  = 10 << 20
+    DefaultPhotoThumbnailWidth = 320
+    // maxPhotosPerUpload bounds the files of one upload request
+    maxPhotosPerUpload = 20
+    // maxPhotoPixels rejects images that would take too much memory to decode
+    maxPhotoPixels = 50_000_000
+)
+
+// uploadFormats are the sniffed content types accepted for uploads
+var uploadFormats = map[string]bool{
+    "image/jpeg": true,
+    "image/png":  true,
+    "image/gif":  true,
+    "image/webp": true,
+}
+
+// PhotoUpload is one uploaded file
+type PhotoUpload struct {
+    Filename string
+    Data     []byte
+}
+
+// HostPhotoService manages the photos hosts upload for their listings. Each
+// upload is sniffed, bounded in size, turned upright and re-encoded without
+// its metadata, then stored through the image proxy with a thumbnail.
+//
+// Hosts are identified by X-Host-ID until host accounts exist; a host
+// manages the photos they uploaded, an empty host ID manages all of them.
+type HostPhotoService struct {
+    proxy          *ImageProxyService
+    maxSize        int64
+    thumbnailWidth int
+}
+
+func NewHostPhotoService() (*HostPhotoService, error) {
+    proxy, err := DefaultImageProxy()
+    if err != nil {
+        return nil, err
+    }
+    return &HostPhotoService{
+        proxy:          proxy,
+        maxSize:        beego.AppConfig.DefaultInt64("photouploadmaxsize", DefaultPhotoUploadMaxSize),
+        thumbnailWidth: beego.AppConfig.DefaultInt("photothumbnailwidth", DefaultPhotoThumbnailWidth),
+    }, nil
+}
+
+// Upload stores uploaded photos of a property after its existing host photos
+func (s *HostPhotoService) Upload(ctx context.Context, hostID, destID, tag, caption string, uploads []PhotoUpload) (_ []models.PropertyPhoto, err error) {
+    ctx, span := tracing.Start(ctx, "HostPhotoService.Upload", attribute.String("dest_id", destID),
+        attribute.Int("photos", len(uploads)))
+    defer tracing.End(span, &err)
+
+    if len(uploads) == 0 || len(uploads) > maxPhotosPerUpload {
+        return nil, apperrors.Validation("upload between 1 and %d photos at a time", maxPhotosPerUpload)
+    }
+
+    var count int64
+    if err := utils.GetDB().WithContext(ctx).Model(&models.Property{}).Where("dest_id = ?", destID).Count(&count).Error; err != nil {
+        return nil, err
+    }
+    if count == 0 {
+        return nil, apperrors.NotFound("property %s not found", destID)
+    }
+
+    // Check every file before storing any
+    sanitized := make([][]byte, len(uploads))
+    contentTypes := make([]string, len(uploads))
+    for i, upload := range uploads {
+        var err error
+        if sanitized[i], contentTypes[i], err = s.sanitize(ctx, upload); err != nil {
+            return nil, err
+        }
+    }
+
+    photos := make([]models.PropertyPhoto, 0, len(uploads))
+    for i := range uploads {
+        photo, err := s.store(ctx, hostID, destID, tag, caption, sanitized[i], contentTypes[i])
+        if err != nil {
+            return photos, err
+        }
+        photos = append(photos, *photo)
+    }
+    return photos, nil
+}
+
+// sanitize checks an upload and re-encodes it upright and without metadata;
+// PNG stays PNG to keep transparency, everything else becomes JPEG
+func (s *HostPhotoService) sanitize(ctx context.Context, upload PhotoUpload) ([]byte, string, error) {
+    if int64(len(upload.Data)) > s.maxSize {
+        return nil, "", apperrors.Validation("%s is larger than %d MB", upload.Filename, s.maxSize>>20)
+    }
+    contentType := http.DetectContentType(upload.Data)
+    if !uploadFormats[contentType] {
+        return nil, "", apperrors.Validation("%s is %s; upload a JPEG, PNG, GIF or WebP image", upload.Filename, contentType)
+    }
+    config, _, err := image.DecodeConfig(bytes.NewReader(upload.Data))
+    if err != nil {
+        return nil, "", apperrors.Validation("%s is not a valid image", upload.Filename)
+    }
+    if config.Width*config.Height > maxPhotoPixels {
+        return nil, "", apperrors.Validation("%s is larger than %d megapixels", upload.Filename, maxPhotoPixels/1_000_000)
+    }
+
+    img, format, err := imageproc.Decode(upload.Data)
+    if err != nil {
+        return nil, "", apperrors.Validation("%s is not a valid image", upload.Filename)
+    }
+    img = imageproc.Orient(img, imageproc.Orientation(upload.Data))
+
+    if format != imageproc.PNG {
+        format = imageproc.JPEG
+    }
+    body, err := imageproc.Encode(ctx, img, format)
+    if err != nil {
+        return nil, "", err
+    }
+    return body, imageproc.ContentType(format), nil
+}
+
+// store records a photo and its blobs in one transaction; the photo ID is
+// derived from the row ID, so the row is inserted first
+func (s *HostPhotoService) store(ctx context.Context, hostID, destID, tag, caption string, body []byte, contentType string) (*models.PropertyPhoto, error) {
+    tx, err := orm.NewOrm().BeginWithCtx(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    position, err := tx.QueryTable(new(models.PropertyPhoto)).
+        Filter("dest_id", destID).
+        Filter("source", models.PhotoSourceHost).
+        Count()
+    if err != nil {
+        tx.Rollback()
+        return nil, err
+    }
+
+    photo := &models.PropertyPhoto{
+        DestID: destID,
+        // A placeholder unique per upload until the row ID is known
+        PhotoID:   -time.Now().UnixNano(),
+        Source:    models.PhotoSourceHost,
+        HostID:    hostID,
+        Tag:       tag,
+        Position:  int(position),
+        Caption:   caption,
+        URLs:      "{}",
+        FetchedAt: time.Now(),
+    }
+    if _, err := tx.InsertWithCtx(ctx, photo); err != nil {
+        tx.Rollback()
+        return nil, fmt.Errorf("error saving photo of property %s: %v", destID, err)
+    }
+
+    photo.PhotoID = models.HostPhotoIDBase + photo.Id
+    photo.Sizes = map[string]string{
+        "original": fmt.Sprintf("/img/%d", photo.PhotoID),
+        fmt.Sprintf("w%d", s.proxy.SnapWidth(s.thumbnailWidth)): fmt.Sprintf("/img/%d?w=%d", photo.PhotoID, s.thumbnailWidth),
+    }
+    urls, err := json.Marshal(photo.Sizes)
+    if err != nil {
+        tx.Rollback()
+        return nil, err
+    }
+    photo.URLs = string(urls)
+    photo.ProxyURL = photo.Sizes["original"]
+    if _, err := tx.UpdateWithCtx(ctx, photo, "PhotoID", "URLs"); err != nil {
+        tx.Rollback()
+        return nil, fmt.Errorf("error saving photo of property %s: %v", destID, err)
+    }
+
+    err = s.proxy.PutOriginal(ctx, photo.PhotoID, body, contentType)
+    if err == nil {
+        // Generating the thumbnail now stores it for the first request
+        _, err = s.proxy.Get(ctx, photo.PhotoID, s.thumbnailWidth, imageproc.JPEG)
+    }
+    if err != nil {
+        tx.Rollback()
+        s.proxy.Delete(ctx, photo.PhotoID)
+        return nil, fmt.Errorf("error storing photo of property %s: %w", destID, err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        s.proxy.Delete(ctx, photo.PhotoID)
+        return nil, err
+    }
+    return photo, nil
+}
+
+// get returns a host photo the host may manage
+func (s *HostPhotoService) get(hostID string, photoID int64) (*models.PropertyPhoto, error) {
+    var photo models.PropertyPhoto
+    err := orm.NewOrm().QueryTable(new(models.PropertyPhoto)).
+        Filter("photo_id", photoID).
+        Filter("source", models.PhotoSourceHost).
+        One(&photo)
+    if err == orm.ErrNoRows || (err == nil && hostID != "" && photo.HostID != hostID) {
+        return nil, apperrors.NotFound("photo %d not found", photoID)
+    }
+    if err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal([]byte(photo.URLs), &photo.Sizes); err != nil {
+        return nil, fmt.Errorf("invalid URLs of photo %d: %v", photoID, err)
+    }
+    photo.ProxyURL = fmt.Sprintf("/img/%d", photo.PhotoID)
+    return &photo, nil
+}
+
+// Update replaces the tag and caption of a host photo
+func (s *HostPhotoService) Update(hostID string, photoID int64, tag, caption string) (*models.PropertyPhoto, error) {
+    photo, err := s.get(hostID, photoID)
+    if err != nil {
+        return nil, err
+    }
+
+    photo.Tag, photo.Caption = tag, caption
+    if _, err := orm.NewOrm().Update(photo, "Tag", "Caption"); err != nil {
+        return nil, fmt.Errorf("error updating photo %d: %v", photoID, err)
+    }
+    return photo, nil
+}
+
+// Reorder sets the order of the host photos of a property; photoIDs must list
+// each of them exactly once
+func (s *HostPhotoService) Reorder(hostID, destID string, photoIDs []int64) ([]models.PropertyPhoto, error) {
+    o := orm.NewOrm()
+    query := o.QueryTable(new(models.PropertyPhoto)).
+        Filter("dest_id", destID).
+        Filter("source", models.PhotoSourceHost)
+    if hostID != "" {
+        query = query.Filter("host_id", hostID)
+    }
+    var photos []models.PropertyPhoto
+    if _, err := query.All(&photos); err != nil {
+        return nil, err
+    }
+
+    byID := make(map[int64]*models.PropertyPhoto, len(photos))
+    for i := range photos {
+        byID[photos[i].PhotoID] = &photos[i]
+    }
+    if len(photoIDs) != len(photos) {
+        return nil, apperrors.Validation("photo_ids must list the %d host photos of property %s", len(photos), destID)
+    }
+
+    tx, err := o.Begin()
+    if err != nil {
+        return nil, err
+    }
+    ordered := make([]models.PropertyPhoto, 0, len(photoIDs))
+    for position, id := range photoIDs {
+        photo, ok := byID[id]
+        if !ok {
+            tx.Rollback()
+            return nil, apperrors.Validation("photo %d is not a host photo of property %s or is listed twice", id, destID)
+        }
+        delete(byID, id)
+        photo.Position = position
+        if _, err := tx.Update(photo, "Position"); err != nil {
+            tx.Rollback()
+            return nil, fmt.Errorf("error ordering photo %d: %v", id, err)
+        }
+        ordered = append(ordered, *photo)
+    }
+    if err := tx.Commit(); err != nil {
+        return nil, err
+    }
+
+    for i := range ordered {
+        json.Unmarshal([]byte(ordered[i].URLs), &ordered[i].Sizes)
+        ordered[i].ProxyURL = fmt.Sprintf("/img/%d", ordered[i].PhotoID)
+    }
+    return ordered, nil
+}
+
+// Delete removes a host photo and its blobs, closing the gap in the order
+func (s *HostPhotoService) Delete(ctx context.Context, hostID string, photoID int64) (err error) {
+    ctx, span := tracing.Start(ctx, "HostPhotoService.Delete", attribute.Int64("photo_id", photoID))
+    defer tracing.End(span, &err)
+
+    photo, err := s.get(hostID, photoID)
+    if err != nil {
+        return err
+    }
+
+    tx, err := orm.NewOrm().BeginWithCtx(ctx)
+    if err != nil {
+        return err
+    }
+    if _, err := tx.DeleteWithCtx(ctx, photo); err != nil {
+        tx.Rollback()
+        return fmt.Errorf("error deleting photo %d: %v", photoID, err)
+    }
+    _, err = tx.RawWithCtx(ctx, "UPDATE property_photos SET position = position - 1 WHERE dest_id = ? AND source = ? AND position > ?",
+        photo.DestID, models.PhotoSourceHost, photo.Position).Exec()
+    if err != nil {
+        tx.Rollback()
+        return fmt.Errorf("error ordering photos of property %s: %v", photo.DestID, err)
+    }
+    if err := tx.Commit(); err != nil {
+        return err
+    }
+
+    return s.proxy.Delete(ctx, photoID)
+}
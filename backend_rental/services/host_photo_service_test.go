@@ -0,0 +1,47 @@
+package services
+
+import (
+    "bytes"
+    "context"
+    "image"
+    "testing"
+
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/imageproc"
+)
+
+func TestSanitizeUpload(t *testing.T) {
+    s := &HostPhotoService{maxSize: 1 << 20}
+    ctx := context.Background()
+
+    data, err := imageproc.Encode(ctx, image.NewRGBA(image.Rect(0, 0, 40, 20)), imageproc.JPEG)
+    if err != nil {
+        t.Fatal(err)
+    }
+    // A big-endian EXIF block whose IFD0 holds orientation 6
+    tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00\x00\x00\x00\x00")
+    segment := append([]byte("Exif\x00\x00"), tiff...)
+    app1 := append([]byte{0xFF, 0xE1, byte((len(segment) + 2) >> 8), byte(len(segment) + 2)}, segment...)
+    withExif := append(append(append([]byte{}, data[:2]...), app1...), data[2:]...)
+
+    body, contentType, err := s.sanitize(ctx, PhotoUpload{Filename: "sideways.jpg", Data: withExif})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if contentType != "image/jpeg" || bytes.Contains(body, []byte("Exif")) {
+        t.Errorf("sanitized upload is %s with EXIF %v", contentType, bytes.Contains(body, []byte("Exif")))
+    }
+    config, _, err := image.DecodeConfig(bytes.NewReader(body))
+    if err != nil || config.Width != 20 || config.Height != 40 {
+        t.Errorf("sanitized upload is %dx%d, %v; want it turned upright", config.Width, config.Height, err)
+    }
+
+    for name, upload := range map[string]PhotoUpload{
+        "not an image": {Filename: "notes.txt", Data: []byte("hello")},
+        "too large":    {Filename: "big.jpg", Data: append(data, make([]byte, 1<<20)...)},
+    } {
+        if _, _, err := s.sanitize(ctx, upload); apperrors.KindOf(err) != apperrors.KindValidation {
+            t.Errorf("%s: err = %v, want a validation error", name, err)
+        }
+    }
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "backend_rental/models"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/blobstore"
+    "backend_rental/utils/imageproc"
+    "backend_rental/utils/tracing"
+    "github.com/beego/beego/v2/client/orm"
+    beego "github.com/beego/beego/v2/server/web"
+    "go.opentelemetry.io/otel/attribute"
+    "golang.org/x/sync/singleflight"
+)
+
+// maxOriginalSize bounds the upstream photos the proxy downloads
+const maxOriginalSize = 20 << 20
+
+// DefaultImageWidths are the variant widths served when imagewidths is not set
+var DefaultImageWidths = []int{160, 320, 640, 1024, 1600}
+
+// ProxiedImage is a stored photo or variant
+type ProxiedImage struct {
+    Body        []byte
+    ContentType string
+    ModTime     time.Time
+}
+
+// ImageProxyService serves property photos from the blob store. It fetches
+// each original from upstream once and generates resized variants on demand;
+// concurrent requests for the same object share the work.
+type ImageProxyService struct {
+    store  blobstore.Store
+    client *http.Client
+    widths []int
+    flight singleflight.Group
+}
+
+// NewImageProxyService serves variants of the given widths, in ascending order
+func NewImageProxyService(store blobstore.Store, client *http.Client, widths []int) *ImageProxyService {
+    return &ImageProxyService{store: store, client: client, widths: widths}
+}
+
+var (
+    defaultImageProxy     *ImageProxyService
+    defaultImageProxyErr  error
+    defaultImageProxyOnce sync.Once
+)
+
+// DefaultImageProxy returns the shared proxy over the configured blob store;
+// imagewidths lists its variant widths, e.g. 320,640,1280
+func DefaultImageProxy() (*ImageProxyService, error) {
+    defaultImageProxyOnce.Do(func() {
+        store, err := blobstore.Default()
+        if err != nil {
+            defaultImageProxyErr = err
+            return
+        }
+        defaultImageProxy = NewImageProxyService(store, &http.Client{Timeout: 30 * time.Second}, configWidths())
+    })
+    return defaultImageProxy, defaultImageProxyErr
+}
+
+func configWidths() []int {
+    var widths []int
+    for _, raw := range strings.Split(beego.AppConfig.DefaultString("imagewidths", ""), ",") {
+        if width, err := strconv.Atoi(strings.TrimSpace(raw)); err == nil && width > 0 {
+            widths = append(widths, width)
+        }
+    }
+    if len(widths) == 0 {
+        return DefaultImageWidths
+    }
+    sort.Ints(widths)
+    return widths
+}
+
+// SnapWidth rounds a requested width up to the nearest served width, so
+// arbitrary widths cannot fill the store; zero stays zero, the original size
+func (s *ImageProxyService) SnapWidth(width int) int {
+    if width <= 0 {
+        return 0
+    }
+    for _, w := range s.widths {
+        if w >= width {
+            return w
+        }
+    }
+    return s.widths[len(s.widths)-1]
+}
+
+// Get returns a photo at width in format. A zero width with an empty format
+// is the original as downloaded; otherwise an empty format is JPEG, and WebP
+// falls back to JPEG when it cannot be encoded.
+func (s *ImageProxyService) Get(ctx context.Context, photoID int64, width int, format string) (_ *ProxiedImage, err error) {
+    width = s.SnapWidth(width)
+    ctx, span := tracing.Start(ctx, "ImageProxyService.Get", attribute.Int64("photo_id", photoID),
+        attribute.Int("width", width), attribute.String("format", format))
+    defer tracing.End(span, &err)
+
+    if width == 0 && format == "" {
+        return s.original(ctx, photoID)
+    }
+    if format == "" || (format == imageproc.WebP && !imageproc.WebPSupported()) {
+        format = imageproc.JPEG
+    }
+
+    key := fmt.Sprintf("photos/%d/w%d.%s", photoID, width, format)
+    // The work is shared, so one caller giving up must not fail the others
+    ctx = context.WithoutCancel(ctx)
+    image, err, _ := s.flight.Do(key, func() (interface{}, error) {
+        if image, err := s.read(ctx, key); err == nil || !errors.Is(err, blobstore.ErrNotFound) {
+            return image, err
+        }
+
+        original, err := s.original(ctx, photoID)
+        if err != nil {
+            return nil, err
+        }
+        img, _, err := imageproc.Decode(original.Body)
+        if err != nil {
+            return nil, apperrors.UpstreamUnavailable(err, "photo %d could not be decoded", photoID)
+        }
+        body, err := imageproc.Encode(ctx, imageproc.Resize(img, width), format)
+        if err != nil {
+            return nil, err
+        }
+        return s.write(ctx, key, body, imageproc.ContentType(format))
+    })
+    if err != nil {
+        return nil, err
+    }
+    return image.(*ProxiedImage), nil
+}
+
+// original returns the stored original of a photo, downloading it first
+func (s *ImageProxyService) original(ctx context.Context, photoID int64) (*ProxiedImage, error) {
+    key := fmt.Sprintf("photos/%d/original", photoID)
+    // The work is shared, so one caller giving up must not fail the others
+    ctx = context.WithoutCancel(ctx)
+    image, err, _ := s.flight.Do(key, func() (interface{}, error) {
+        if image, err := s.read(ctx, key); err == nil || !errors.Is(err, blobstore.ErrNotFound) {
+            return image, err
+        }
+
+        photoURL, err := s.photoURL(photoID)
+        if err != nil {
+            return nil, err
+        }
+        body, contentType, err := s.download(ctx, photoURL)
+        if err != nil {
+            return nil, apperrors.UpstreamUnavailable(err, "photo %d could not be downloaded", photoID)
+        }
+        return s.write(ctx, key, body, contentType)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return image.(*ProxiedImage), nil
+}
+
+// PutOriginal stores the original of a photo that has no upstream URL, such
+// as a host upload
+func (s *ImageProxyService) PutOriginal(ctx context.Context, photoID int64, body []byte, contentType string) error {
+    _, err := s.write(ctx, fmt.Sprintf("photos/%d/original", photoID), body, contentType)
+    return err
+}
+
+// Delete removes the original of a photo and every variant it may have
+func (s *ImageProxyService) Delete(ctx context.Context, photoID int64) error {
+    keys := []string{fmt.Sprintf("photos/%d/original", photoID)}
+    for _, width := range append([]int{0}, s.widths...) {
+        for _, format := range []string{imageproc.JPEG, imageproc.PNG, imageproc.WebP} {
+            keys = append(keys, fmt.Sprintf("photos/%d/w%d.%s", photoID, width, format))
+        }
+    }
+    for _, key := range keys {
+        if err := s.store.Delete(ctx, key); err != nil && !errors.Is(err, blobstore.ErrNotFound) {
+            return err
+        }
+    }
+    return nil
+}
+
+func (s *ImageProxyService) read(ctx context.Context, key string) (*ProxiedImage, error) {
+    reader, object, err := s.store.Get(ctx, key)
+    if err != nil {
+        return nil, err
+    }
+    defer reader.Close()
+    body, err := io.ReadAll(reader)
+    if err != nil {
+        return nil, err
+    }
+    return &ProxiedImage{Body: body, ContentType: object.ContentType, ModTime: object.ModTime}, nil
+}
+
+func (s *ImageProxyService) write(ctx context.Context, key string, body []byte, contentType string) (*ProxiedImage, error) {
+    if err := s.store.Put(ctx, key, bytes.NewReader(body), int64(len(body)), contentType); err != nil {
+        return nil, err
+    }
+    return &ProxiedImage{Body: body, ContentType: contentType, ModTime: time.Now()}, nil
+}
+
+// photoURL returns the URL of the largest upstream size of a stored photo
+func (s *ImageProxyService) photoURL(photoID int64) (string, error) {
+    var photo models.PropertyPhoto
+    err := orm.NewOrm().QueryTable(new(models.PropertyPhoto)).
+        Filter("photo_id", photoID).
+        Limit(1).
+        One(&photo)
+    if err == orm.ErrNoRows {
+        return "", apperrors.NotFound("photo %d not found", photoID)
+    }
+    if err != nil {
+        return "", err
+    }
+
+    var sizes map[string]string
+    if err := json.Unmarshal([]byte(photo.URLs), &sizes); err != nil {
+        return "", fmt.Errorf("invalid URLs of photo %d: %v", photoID, err)
+    }
+    if url := largestSize(sizes); url != "" {
+        return url, nil
+    }
+    return "", apperrors.NotFound("photo %d has no URL", photoID)
+}
+
+var sizeDimensions = regexp.MustCompile(`\d+`)
+
+// largestSize picks the URL whose size name has the largest dimensions, e.g.
+// max1280x900 over square60; sizes without dimensions are original uploads
+func largestSize(sizes map[string]string) string {
+    best, bestArea := "", -1
+    for size, url := range sizes {
+        area := 1
+        for _, dimension := range sizeDimensions.FindAllString(size, 2) {
+            n, _ := strconv.Atoi(dimension)
+            area *= n
+        }
+        if !sizeDimensions.MatchString(size) {
+            area = 1 << 62
+        }
+        if area > bestArea || (area == bestArea && url < best) {
+            best, bestArea = url, area
+        }
+    }
+    return best
+}
+
+func (s *ImageProxyService) download(ctx context.Context, url string) ([]byte, string, error) {
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+    if err != nil {
+        return nil, "", err
+    }
+    resp, err := s.client.Do(req)
+    if err != nil {
+        return nil, "", err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return nil, "", fmt.Errorf("upstream returned %s", resp.Status)
+    }
+
+    body, err := io.ReadAll(io.LimitReader(resp.Body, maxOriginalSize+1))
+    if err != nil {
+        return nil, "", err
+    }
+    if len(body) > maxOriginalSize {
+        return nil, "", fmt.Errorf("photo larger than %d bytes", maxOriginalSize)
+    }
+    contentType := http.DetectContentType(body)
+    if !strings.HasPrefix(contentType, "image/") {
+        return nil, "", fmt.Errorf("upstream returned %s, not an image", contentType)
+    }
+    return body, contentType, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+    "bytes"
+    "context"
+    "image"
+    "image/png"
+    "net/http"
+    "testing"
+
+    "backend_rental/utils/blobstore"
+    "backend_rental/utils/imageproc"
+)
+
+func TestImageProxyResizesStoredOriginal(t *testing.T) {
+    store, err := blobstore.NewLocal(t.TempDir())
+    if err != nil {
+        t.Fatal(err)
+    }
+    var original bytes.Buffer
+    png.Encode(&original, image.NewRGBA(image.Rect(0, 0, 800, 400)))
+    store.Put(context.Background(), "photos/1/original", bytes.NewReader(original.Bytes()), int64(original.Len()), "image/png")
+
+    proxy := NewImageProxyService(store, http.DefaultClient, []int{160, 320})
+    variant, err := proxy.Get(context.Background(), 1, 200, imageproc.PNG)
+    if err != nil {
+        t.Fatal(err)
+    }
+    img, _, err := imageproc.Decode(variant.Body)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if img.Bounds().Dx() != 320 || img.Bounds().Dy() != 160 || variant.ContentType != "image/png" {
+        t.Errorf("variant is %v %s", img.Bounds(), variant.ContentType)
+    }
+    if _, _, err := store.Get(context.Background(), "photos/1/w320.png"); err != nil {
+        t.Errorf("variant not stored: %v", err)
+    }
+}
+
+func TestLargestSize(t *testing.T) {
+    sizes := map[string]string{
+        "square60":    "a",
+        "max1280x900": "b",
+        "max500":      "c",
+    }
+    if got := largestSize(sizes); got != "b" {
+        t.Errorf("largestSize = %q", got)
+    }
+}
@@ -0,0 +1,133 @@
+// services/language_service.go
+package services
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "log/slog"
+    "strings"
+    "sync"
+    "time"
+
+    "backend_rental/models"
+    "backend_rental/utils/apiclient"
+    "github.com/beego/beego/v2/client/orm"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+var (
+    supportedLanguages = make(map[string]bool)
+    languagesMutex     sync.RWMutex
+)
+
+type LanguageService struct {
+    apiClient *apiclient.APIClient
+}
+
+func NewLanguageService() *LanguageService {
+    rapidAPIKey, _ := beego.AppConfig.String("rapidapikey")
+    return &LanguageService{
+        apiClient: apiclient.NewAPIClient(rapidAPIKey),
+    }
+}
+
+// SyncLanguages fetches the upstream language list and stores it
+func (s *LanguageService) SyncLanguages(ctx context.Context) error {
+    body, err := s.apiClient.MakeRequest(ctx, "https://booking-com18.p.rapidapi.com/languages")
+    if err != nil {
+        return fmt.Errorf("failed to fetch languages: %v", err)
+    }
+
+    count, err := s.saveLanguages(body)
+    if err != nil {
+        return err
+    }
+
+    slog.InfoContext(ctx, "synced languages", "count", count)
+    return s.LoadSupportedLanguages()
+}
+
+// saveLanguages stores the languages of an upstream /languages response,
+// updating the names of known codes, and returns how many it stored
+func (s *LanguageService) saveLanguages(body []byte) (int, error) {
+    var response struct {
+        Data []struct {
+            Code        string `json:"code"`
+            Name        string `json:"name"`
+            CountryFlag string `json:"countryFlag"`
+        } `json:"data"`
+    }
+    if err := json.Unmarshal(body, &response); err != nil {
+        return 0, fmt.Errorf("failed to parse languages response: %v", err)
+    }
+
+    o := orm.NewOrm()
+    count := 0
+    for _, item := range response.Data {
+        if item.Code == "" {
+            continue
+        }
+        code := strings.ToLower(item.Code)
+        // Beego's InsertOrUpdate cannot upsert a string key on Postgres
+        _, err := o.Raw(`
+            INSERT INTO language (code, name, country_flag, updated_at)
+            VALUES (?, ?, ?, ?)
+            ON CONFLICT (code) DO UPDATE SET
+                name = EXCLUDED.name,
+                country_flag = EXCLUDED.country_flag,
+                updated_at = EXCLUDED.updated_at
+        `, code, item.Name, item.CountryFlag, time.Now()).Exec()
+        if err != nil {
+            return count, fmt.Errorf("error saving language %s: %v", code, err)
+        }
+        count++
+    }
+    return count, nil
+}
+
+// LoadSupportedLanguages refreshes the in-memory set of supported language codes
+func (s *LanguageService) LoadSupportedLanguages() error {
+    languages, err := s.ListLanguages()
+    if err != nil {
+        return err
+    }
+
+    codes := make(map[string]bool, len(languages))
+    for _, language := range languages {
+        codes[language.Code] = true
+    }
+
+    languagesMutex.Lock()
+    supportedLanguages = codes
+    languagesMutex.Unlock()
+    return nil
+}
+
+// ListLanguages returns the stored languages ordered by code
+func (s *LanguageService) ListLanguages() ([]models.Language, error) {
+    var languages []models.Language
+    _, err := orm.NewOrm().QueryTable(new(models.Language)).OrderBy("code").Limit(-1).All(&languages)
+    return languages, err
+}
+
+// IsSupportedLanguage reports whether upstream serves content in the language
+func IsSupportedLanguage(code string) bool {
+    languagesMutex.RLock()
+    defer languagesMutex.RUnlock()
+    return supportedLanguages[code]
+}
+
+// contentLanguages returns the extra languages ingested alongside the default one
+func contentLanguages() []string {
+    configured, _ := beego.AppConfig.String("contentlanguages")
+
+    var languages []string
+    for _, code := range strings.Split(configured, ",") {
+        code = strings.ToLower(strings.TrimSpace(code))
+        if code != "" {
+            languages = append(languages, code)
+        }
+    }
+    return languages
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+    "fmt"
+    "testing"
+    "time"
+)
+
+func TestSaveLanguagesRejectsInvalidResponse(t *testing.T) {
+    if _, err := NewLanguageService().saveLanguages([]byte(`{"data": "none"}`)); err == nil {
+        t.Error("saveLanguages accepted a malformed response")
+    }
+}
+
+func TestSaveLanguagesUpsertsByCode(t *testing.T) {
+    requireDB(t)
+    service := NewLanguageService()
+    // Codes are at most 16 characters
+    code := fmt.Sprintf("t%d", time.Now().UnixNano()%1e12)
+
+    for _, name := range []string{"Test", "Test renamed"} {
+        body := fmt.Sprintf(`{"data": [{"code": %q, "name": %q, "countryFlag": "xx"}, {"code": ""}]}`,
+            "T"+code[1:], name)
+        count, err := service.saveLanguages([]byte(body))
+        if err != nil {
+            t.Fatalf("saveLanguages: %v", err)
+        }
+        if count != 1 {
+            t.Errorf("stored %d languages, want 1", count)
+        }
+    }
+
+    languages, err := service.ListLanguages()
+    if err != nil {
+        t.Fatalf("ListLanguages: %v", err)
+    }
+    found := false
+    for _, language := range languages {
+        if language.Code == code {
+            found = true
+            if language.Name != "Test renamed" {
+                t.Errorf("language %s named %q, want the synced name", code, language.Name)
+            }
+        }
+    }
+    if !found {
+        t.Fatalf("language %s not stored under its lower-case code", code)
+    }
+
+    if err := service.LoadSupportedLanguages(); err != nil {
+        t.Fatalf("LoadSupportedLanguages: %v", err)
+    }
+    if !IsSupportedLanguage(code) {
+        t.Errorf("%s not supported after the sync", code)
+    }
+}
@@ -4,27 +4,36 @@ import (
     "context"
     "encoding/json"
     "fmt"
-    "log"
+    "log/slog"
     "sync"
 
+    "net/url"
+
     "backend_rental/models"
+    "backend_rental/utils"
     "backend_rental/utils/apiclient"
+    "backend_rental/utils/metrics"
 )
 
 type LocationProcessingService struct {
     apiClient    *apiclient.APIClient
     locationSvc  *LocationService
+    translations *TranslationService
 }
 
 func NewLocationProcessingService(apiClient *apiclient.APIClient) *LocationProcessingService {
     return &LocationProcessingService{
-        apiClient:   apiClient,
-        locationSvc: &LocationService{},
+        apiClient:    apiClient,
+        locationSvc:  &LocationService{},
+        translations: NewTranslationService(),
     }
 }
 
 func (s *LocationProcessingService) ProcessLocationsFromQueries(queries []string) error {
-    log.Printf("Starting to process %d queries", len(queries))
+    slog.Info("processing location queries", "count", len(queries))
+    metrics.CrawlQueries.Set(float64(len(queries)))
+    metrics.CrawlQueriesDone.Set(0)
+    metrics.CrawlCitiesFetched.Set(0)
 
     var allCities []models.City
     var mu sync.Mutex
@@ -42,24 +51,25 @@ func (s *LocationProcessingService) ProcessLocationsFromQueries(queries []string
             defer wg.Done()
             defer func() { <-semaphore }()
 
-            cities, err := s.fetchCitiesForQuery(q)
+            cities, err := s.fetchCitiesForQuery(q, "")
             if err != nil {
-                log.Printf("Error fetching cities for query '%s': %v", q, err)
+                slog.Error("error fetching cities", "query", q, "error", err)
                 errChan <- err
                 return
             }
 
             // Add logging for city data validation
             for _, city := range cities {
-                log.Printf("Fetched city: ID='%s', Name='%s', Country='%s'",
-                    city.CityID, city.CityName, city.Country)
+                slog.Debug("fetched city", "city_id", city.CityID, "city", city.CityName, "country", city.Country)
             }
 
             mu.Lock()
             allCities = append(allCities, cities...)
             mu.Unlock()
+            metrics.CrawlQueriesDone.Inc()
+            metrics.CrawlCitiesFetched.Add(float64(len(cities)))
 
-            log.Printf("Fetched %d cities for query '%s'", len(cities), q)
+            slog.Info("fetched cities", "query", q, "count", len(cities))
         }(query)
     }
 
@@ -74,17 +84,73 @@ func (s *LocationProcessingService) ProcessLocationsFromQueries(queries []string
     }
 
     // Log raw data before processing
-    log.Printf("Total cities fetched before processing: %d", len(allCities))
+    slog.Info("fetched cities for all queries", "count", len(allCities))
+
+    if err := s.locationSvc.ProcessAndStoreCities(allCities); err != nil {
+        return err
+    }
 
-    return s.locationSvc.ProcessAndStoreCities(allCities)
+    s.ingestLocationTranslations(queries, allCities)
+    return nil
 }
 
-func (s *LocationProcessingService) fetchCitiesForQuery(query string) ([]models.City, error) {
+// ingestLocationTranslations re-runs the queries in each configured content
+// language and stores the translated city and country names, matching the
+// results to stored locations by upstream dest_id
+func (s *LocationProcessingService) ingestLocationTranslations(queries []string, cities []models.City) {
+    languages := contentLanguages()
+    if len(languages) == 0 {
+        return
+    }
+
+    locationIDs := make(map[string]string)
+    for _, city := range cities {
+        if city.DestID != "" && city.CityName != "" && city.Country != "" {
+            locationIDs[city.DestID] = utils.CityLocationID(city.CityName, city.Country)
+        }
+    }
+
+    for _, language := range languages {
+        stored := 0
+        for _, query := range queries {
+            translated, err := s.fetchCitiesForQuery(query, language)
+            if err != nil {
+                slog.Error("error fetching translated cities", "language", language, "query", query, "error", err)
+                continue
+            }
+
+            for _, city := range translated {
+                locationID, ok := locationIDs[city.DestID]
+                if !ok || city.CityName == "" {
+                    continue
+                }
+                if err := s.translations.Save(models.TranslationEntityLocation, locationID,
+                    models.TranslationFieldCityName, language, city.CityName); err != nil {
+                    slog.Error("error saving location translation", "error", err)
+                    continue
+                }
+                if city.Country != "" {
+                    if err := s.translations.Save(models.TranslationEntityLocation, locationID,
+                        models.TranslationFieldCountry, language, city.Country); err != nil {
+                        slog.Error("error saving location translation", "error", err)
+                    }
+                }
+                stored++
+            }
+        }
+        slog.Info("stored location translations", "language", language, "count", stored)
+    }
+}
+
+func (s *LocationProcessingService) fetchCitiesForQuery(query, language string) ([]models.City, error) {
     apiURL := fmt.Sprintf("https://booking-com18.p.rapidapi.com/stays/auto-complete?query=%s", query)
+    if language != "" {
+        apiURL += "&languageCode=" + url.QueryEscape(language)
+    }
     
     body, err := s.apiClient.MakeRequest(context.Background(), apiURL)
     if err != nil {
-        return nil, fmt.Errorf("API request failed: %v", err)
+        return nil, fmt.Errorf("API request failed: %w", err)
     }
 
     var citiesResp models.CityResponse
@@ -2,61 +2,88 @@
 package services
 
 import (
+    "context"
+    "database/sql"
     "fmt"
-	"log"
     "backend_rental/models"
     "backend_rental/utils"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/pagination"
+    "backend_rental/utils/tracing"
     "github.com/beego/beego/v2/client/orm"
+    "go.opentelemetry.io/otel/attribute"
+    "log/slog"
+    "sort"
     "time"
 )
 
 type LocationService struct{}
 
+// locationOrder lists locations alphabetically by city, breaking ties on the unique id
+var locationOrder = pagination.Order{{Column: "city_name"}, {Column: "id"}}
+
 func (s *LocationService) GetLocations(
-    page, 
-    pageSize int, 
+    ctx context.Context,
+    params pagination.Params,
     country, 
     cityName string,
-) ([]models.Location, int64, error) {
+) (_ []models.Location, _ pagination.Page, err error) {
+    ctx, span := tracing.Start(ctx, "LocationService.GetLocations")
+    defer tracing.End(span, &err)
+
     o := orm.NewOrm()
     
-    // Create a query table
-    qs := o.QueryTable(new(models.Location))
-    
     // Apply filters if provided
+    cond := orm.NewCondition()
     if country != "" {
-        qs = qs.Filter("country", country)
+        cond = cond.And("country", country)
     }
     
     if cityName != "" {
-        qs = qs.Filter("city_name__icontains", cityName)
+        cond = cond.And("city_name__icontains", cityName)
     }
-    
-    // Count total matching locations
-    totalCount, err := qs.Count()
-    if err != nil {
-        return nil, 0, err
+
+    qs := o.QueryTable(new(models.Location)).SetCond(cond)
+    if params.Cursor != nil {
+        keyset, err := locationOrder.Condition(params.Cursor)
+        if err != nil {
+            return nil, pagination.Page{}, err
+        }
+        qs = qs.SetCond(cond.AndCond(keyset))
     }
     
-    // Retrieve paginated locations
+    // Retrieve one row past the page to know whether another page follows
     var locations []models.Location
-    _, err = qs.Limit(pageSize, (page-1)*pageSize).All(&locations)
+    _, err = qs.OrderBy(locationOrder.Fields(params.Backward())...).Limit(params.Limit+1).AllWithCtx(ctx, &locations)
     if err != nil {
-        return nil, 0, err
+        return nil, pagination.Page{}, err
     }
+
+    locations, page := pagination.Paginate(locations, params, func(l models.Location) []string {
+        return []string{l.CityName, l.ID}
+    })
     
-    return locations, totalCount, nil
+    // Count total matching locations only when asked to
+    if params.IncludeTotal {
+        totalCount, err := o.QueryTable(new(models.Location)).SetCond(cond).CountWithCtx(ctx)
+        if err != nil {
+            return nil, pagination.Page{}, err
+        }
+        page.TotalCount = &totalCount
+    }
+    
+    return locations, page, nil
 }
 
 // Bulk create or update locations
 func (s *LocationService) BulkCreateLocations(locations []models.Location) error {
-    log.Printf("Starting to save locations. Total locations: %d", len(locations))
+    slog.Info("saving locations", "count", len(locations))
 
     o := orm.NewOrm()
 
     for _, location := range locations {
         if location.ID == "" || location.CityName == "" {
-            log.Printf("Skipping invalid location: %+v", location)
+            slog.Warn("skipping invalid location", "location", location)
             continue
         }
 
@@ -89,12 +116,11 @@ func (s *LocationService) BulkCreateLocations(locations []models.Location) error
         ).Exec()
 
         if err != nil {
-            log.Printf("Error inserting location %s: %v", location.ID, err)
             return fmt.Errorf("error inserting location %s: %v", location.ID, err)
         }
     }
 
-    log.Printf("Successfully saved %d locations", len(locations))
+    slog.Info("saved locations", "count", len(locations))
     return nil
 }
 // func (s *LocationService) BulkCreateLocations(locations []models.Location) error {
@@ -135,14 +161,17 @@ func (s *LocationService) BulkCreateLocations(locations []models.Location) error
 // }
 
 // Optional: Get a single location by ID
-func (s *LocationService) GetLocationByID(cityID string) (*models.Location, error) {
+func (s *LocationService) GetLocationByID(ctx context.Context, cityID string) (_ *models.Location, err error) {
+    ctx, span := tracing.Start(ctx, "LocationService.GetLocationByID", attribute.String("city_id", cityID))
+    defer tracing.End(span, &err)
+
     o := orm.NewOrm()
     
     location := &models.Location{ID: cityID}
-    err := o.Read(location)
+    err = o.ReadWithCtx(ctx, location)
     if err != nil {
         if err == orm.ErrNoRows {
-            return nil, fmt.Errorf("location not found")
+            return nil, apperrors.NotFound("location %s not found", cityID)
         }
         return nil, err
     }
@@ -150,17 +179,70 @@ func (s *LocationService) GetLocationByID(cityID string) (*models.Location, erro
     return location, nil
 }
 
+// GetLocationsByIDs returns the stored locations with the given IDs
+func (s *LocationService) GetLocationsByIDs(ctx context.Context, cityIDs []string) (_ []models.Location, err error) {
+    ctx, span := tracing.Start(ctx, "LocationService.GetLocationsByIDs", attribute.Int("city_ids", len(cityIDs)))
+    defer tracing.End(span, &err)
+
+    var locations []models.Location
+    if len(cityIDs) == 0 {
+        return locations, nil
+    }
+    _, err = orm.NewOrm().QueryTable(new(models.Location)).
+        Filter("id__in", cityIDs).
+        OrderBy("id").
+        Limit(-1).
+        AllWithCtx(ctx, &locations)
+    return locations, err
+}
+
+// GetLocalizedLocation returns a location with its names in the first
+// available language of the fallback chain
+func (s *LocationService) GetLocalizedLocation(ctx context.Context, cityID string, languages []string) (_ *models.Location, err error) {
+    ctx, span := tracing.Start(ctx, "LocationService.GetLocalizedLocation", attribute.String("city_id", cityID))
+    defer tracing.End(span, &err)
+
+    location, err := s.GetLocationByID(ctx, cityID)
+    if err != nil {
+        return nil, err
+    }
+
+    localized, err := s.LocalizeLocations(ctx, []models.Location{*location}, languages)
+    if err != nil {
+        return nil, err
+    }
+    return &localized[0], nil
+}
+
+// Summary groups the stored locations by country
+func (s *LocationService) Summary(ctx context.Context, languages []string) (_ *models.BookingSummary, err error) {
+    ctx, span := tracing.Start(ctx, "LocationService.Summary")
+    defer tracing.End(span, &err)
+
+    countryCities, err := s.GetUniqueCountriesAndCities(ctx, languages)
+    if err != nil {
+        return nil, err
+    }
+
+    return &models.BookingSummary{
+        Countries:      utils.ConvertToCountryMap(countryCities),
+        Cities:         utils.ConvertToCityMap(countryCities),
+        CountryCities:  countryCities,
+        CityProperties: map[string][]string{},
+    }, nil
+}
+
 func (s *LocationService) ProcessAndStoreCities(cities []models.City) error {
     // Add logging
-    log.Printf("Processing %d cities", len(cities))
+    slog.Info("processing cities", "count", len(cities))
     
     // Filter and clean cities
     cleanedCities := utils.FilterAndCleanCities(cities)
-    log.Printf("After cleaning: %d cities", len(cleanedCities))
+    slog.Debug("cleaned cities", "count", len(cleanedCities))
     
     // Convert to locations
     locations := s.convertCitiesToLocationsInternal(cleanedCities)
-    log.Printf("Converted to %d locations", len(locations))
+    slog.Debug("converted cities to locations", "count", len(locations))
     
     // Add validation
     if len(locations) == 0 {
@@ -170,11 +252,10 @@ func (s *LocationService) ProcessAndStoreCities(cities []models.City) error {
     // Bulk create with error handling
     err := s.BulkCreateLocations(locations)
     if err != nil {
-        log.Printf("Error storing locations: %v", err)
         return err
     }
     
-    log.Printf("Successfully stored %d locations", len(locations))
+    slog.Info("stored locations", "count", len(locations))
     return nil
 }
 // Internal method to convert cities to locations
@@ -202,30 +283,93 @@ func (s *LocationService) convertCitiesToLocationsInternal(cities []models.City)
     return locations
 }
 
+// LocalizeLocations replaces city and country names with their translations
+// in the first available language of the fallback chain
+func (s *LocationService) LocalizeLocations(ctx context.Context, locations []models.Location, languages []string) (_ []models.Location, err error) {
+    _, span := tracing.Start(ctx, "LocationService.LocalizeLocations", attribute.Int("locations", len(locations)))
+    defer tracing.End(span, &err)
+
+    if len(locations) == 0 || len(languages) == 0 {
+        return locations, nil
+    }
+
+    ids := make([]string, len(locations))
+    for i, location := range locations {
+        ids[i] = location.ID
+    }
+
+    translations := NewTranslationService()
+    cityNames, err := translations.Lookup(models.TranslationEntityLocation, ids, models.TranslationFieldCityName, languages)
+    if err != nil {
+        return nil, err
+    }
+    countries, err := translations.Lookup(models.TranslationEntityLocation, ids, models.TranslationFieldCountry, languages)
+    if err != nil {
+        return nil, err
+    }
+
+    for i := range locations {
+        if name, ok := cityNames[locations[i].ID]; ok {
+            locations[i].CityName = name
+        }
+        if country, ok := countries[locations[i].ID]; ok {
+            locations[i].Country = country
+        }
+    }
+
+    return locations, nil
+}
+
+// LastModified returns when a stored location or one of its translations
+// last changed, or the zero time when there are none
+func (s *LocationService) LastModified(ctx context.Context) (_ time.Time, err error) {
+    ctx, span := tracing.Start(ctx, "LocationService.LastModified")
+    defer tracing.End(span, &err)
+
+    var lastModified sql.NullTime
+    err = utils.GetDB().WithContext(ctx).Raw(`
+        SELECT GREATEST(
+            (SELECT MAX(updated_at) FROM location),
+            (SELECT MAX(fetched_at) FROM translation WHERE entity = ?))`,
+        models.TranslationEntityLocation).Row().Scan(&lastModified)
+    if err != nil {
+        return time.Time{}, fmt.Errorf("error loading the last location change: %v", err)
+    }
+    return lastModified.Time, nil
+}
+
 // Get unique countries and cities
-func (s *LocationService) GetUniqueCountriesAndCities() (map[string][]string, error) {
+func (s *LocationService) GetUniqueCountriesAndCities(ctx context.Context, languages []string) (_ map[string][]string, err error) {
+    ctx, span := tracing.Start(ctx, "LocationService.GetUniqueCountriesAndCities")
+    defer tracing.End(span, &err)
+
     o := orm.NewOrm()
-    
-    // Query to get unique countries and their cities
-    var results []orm.Params
-    _, err := o.Raw(`
-        SELECT DISTINCT country, 
-               ARRAY_AGG(DISTINCT city_name) AS cities 
-        FROM location 
-        GROUP BY country
-    `).Values(&results)
-    
+
+    var locations []models.Location
+    _, err = o.QueryTable(new(models.Location)).Limit(-1).AllWithCtx(ctx, &locations, "ID", "CityName", "Country")
     if err != nil {
         return nil, err
     }
-    
+
+    locations, err = s.LocalizeLocations(ctx, locations, languages)
+    if err != nil {
+        return nil, err
+    }
+
     countryCities := make(map[string][]string)
-    for _, result := range results {
-        country := result["country"].(string)
-        cities := result["cities"].([]string)
-        countryCities[country] = cities
+    seen := make(map[string]bool)
+    for _, location := range locations {
+        key := location.Country + "|" + location.CityName
+        if seen[key] {
+            continue
+        }
+        seen[key] = true
+        countryCities[location.Country] = append(countryCities[location.Country], location.CityName)
+    }
+    for _, cities := range countryCities {
+        sort.Strings(cities)
     }
-    log.Printf("Fetched countries and cities: %v", countryCities)
+    slog.Debug("listed countries and cities", "countries", len(countryCities))
 
     return countryCities, nil
 }
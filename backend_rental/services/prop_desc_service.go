@@ -2,33 +2,170 @@ package services
 
 import (
     "context"
+    "encoding/json"
+    "fmt"
+    "log/slog"
+    "net/url"
     "sync"
     "time"
     "backend_rental/models"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils"
+    "backend_rental/utils/apiclient"
+    "backend_rental/utils/tracing"
     "github.com/beego/beego/v2/client/orm"
-    "github.com/beego/beego/v2/core/logs"
-    "golang.org/x/time/rate"
+    beego "github.com/beego/beego/v2/server/web"
+    "go.opentelemetry.io/otel/attribute"
 )
 
+// mainDescriptionTypeID is the upstream descriptiontype_id of the main property text
+const mainDescriptionTypeID = 6
+
 type PropDescService struct {
-    mutex       sync.Mutex
-    rateLimiter *rate.Limiter
+    apiClient    *apiclient.APIClient
+    translations *TranslationService
+    language     string
 }
 
 func NewPropDescService() *PropDescService {
+    rapidAPIKey, _ := beego.AppConfig.String("rapidapikey")
+    language, _ := beego.AppConfig.String("defaultlanguage")
+    if language == "" {
+        language = "en-gb"
+    }
+
     return &PropDescService{
-        rateLimiter: rate.NewLimiter(rate.Every(time.Second), 1), // 1 request per second
+        apiClient:    apiclient.NewAPIClient(rapidAPIKey),
+        translations: NewTranslationService(),
+        language:     language,
     }
 }
 
+// GetPropertyDescription returns the stored description, ingesting it from
+// upstream first when it has never been fetched. Descriptions older than
+// DescriptionMaxAge are served while the refresh queue re-fetches them.
 func (s *PropDescService) GetPropertyDescription(destID string) (*models.PropertyDescription, error) {
     o := orm.NewOrm()
     details := models.PropertyDescription{DestID: destID}
     err := o.Read(&details)
-    if err == orm.ErrNoRows {
-        return nil, nil
+    if err != nil && err != orm.ErrNoRows {
+        return nil, err
+    }
+    if err == nil && !details.DescriptionFetchedAt.IsZero() {
+        if stale(details.DescriptionFetchedAt, DescriptionMaxAge()) {
+            DefaultRefreshQueue().Enqueue(RefreshDescription, destID)
+        }
+        return &details, nil
+    }
+
+    return s.IngestPropertyDescription(context.Background(), destID)
+}
+
+// GetLocalizedPropertyDescription returns the description in the first
+// language of the fallback chain that is available
+func (s *PropDescService) GetLocalizedPropertyDescription(ctx context.Context, destID string, languages []string) (_ *models.PropertyDescription, err error) {
+    ctx, span := tracing.Start(ctx, "PropDescService.GetLocalizedPropertyDescription", attribute.String("dest_id", destID))
+    defer tracing.End(span, &err)
+
+    details, err := s.GetPropertyDescription(destID)
+    if err != nil {
+        return nil, err
+    }
+    if err := s.LocalizeDescription(ctx, details, languages); err != nil {
+        return nil, err
+    }
+    return details, nil
+}
+
+// GetStoredDescriptions returns the stored descriptions of properties keyed by
+// dest ID, localized with stored translations only. Unlike
+// GetLocalizedPropertyDescription it never calls upstream, so it is safe to
+// use for many properties at once; descriptions never fetched are left out.
+func (s *PropDescService) GetStoredDescriptions(destIDs []string, languages []string) (map[string]*models.PropertyDescription, error) {
+    result := make(map[string]*models.PropertyDescription, len(destIDs))
+    if len(destIDs) == 0 {
+        return result, nil
+    }
+
+    var descriptions []*models.PropertyDescription
+    _, err := orm.NewOrm().QueryTable(new(models.PropertyDescription)).
+        Filter("dest_id__in", destIDs).
+        Filter("description_fetched_at__isnull", false).
+        Limit(-1).
+        All(&descriptions)
+    if err != nil {
+        return nil, fmt.Errorf("error loading property descriptions: %v", err)
+    }
+
+    translations, err := s.translations.LookupTranslations(models.TranslationEntityProperty, destIDs,
+        models.TranslationFieldDescription, languages)
+    if err != nil {
+        return nil, err
+    }
+
+    rank := make(map[string]int, len(languages))
+    for i, language := range languages {
+        rank[language] = i
+    }
+    for _, description := range descriptions {
+        if t, ok := translations[description.DestID]; ok {
+            // A translation only wins over a stored text earlier in the chain
+            if current, listed := rank[description.Language]; !listed || rank[t.Language] < current {
+                description.Description = t.Value
+                description.Language = t.Language
+            }
+        }
+        result[description.DestID] = description
+    }
+    return result, nil
+}
+
+// LocalizeDescription replaces the description with a stored translation,
+// fetching it from upstream on demand for supported languages
+func (s *PropDescService) LocalizeDescription(ctx context.Context, details *models.PropertyDescription, languages []string) (err error) {
+    ctx, span := tracing.Start(ctx, "PropDescService.LocalizeDescription", attribute.String("dest_id", details.DestID))
+    defer tracing.End(span, &err)
+
+    stored, err := s.translations.Values(models.TranslationEntityProperty, details.DestID,
+        models.TranslationFieldDescription, languages)
+    if err != nil {
+        return err
+    }
+
+    for _, language := range languages {
+        if language == details.Language {
+            return nil
+        }
+        if text, ok := stored[language]; ok {
+            details.Description = text
+            details.Language = language
+            return nil
+        }
+        if !IsSupportedLanguage(language) {
+            continue
+        }
+
+        text, err := s.fetchDescriptionText(ctx, details.DestID, language)
+        if err != nil {
+            slog.ErrorContext(ctx, "error fetching description", "language", language, "dest_id", details.DestID, "error", err)
+            continue
+        }
+        if text == "" {
+            continue
+        }
+        if err := s.translations.Save(models.TranslationEntityProperty, details.DestID,
+            models.TranslationFieldDescription, language, text); err != nil {
+            slog.ErrorContext(ctx, "error saving description translation", "error", err)
+        } else {
+            s.reindex(ctx, details.DestID)
+        }
+
+        details.Description = text
+        details.Language = language
+        return nil
     }
-    return &details, err
+
+    return nil
 }
 
 func (s *PropDescService) SavePropertyDescription(details *models.PropertyDescription) error {
@@ -37,51 +174,203 @@ func (s *PropDescService) SavePropertyDescription(details *models.PropertyDescri
     return err
 }
 
-// fetchPropertyDescriptionFromAPI fetches property description from external API
-func (s *PropDescService) fetchPropertyDescriptionFromAPI(destID string) (*models.PropertyDescription, error) {
-    return &models.PropertyDescription{
-        DestID:      destID,
-        Description: "Sample description",
-        Rating:      4.5,
-        Review:      "Sample review",
-    }, nil
+// RefreshPropertyDescription re-fetches the description and reviews from
+// upstream, bypassing the upstream response cache
+func (s *PropDescService) RefreshPropertyDescription(ctx context.Context, destID string) (*models.PropertyDescription, error) {
+    return s.IngestPropertyDescription(apiclient.WithRefresh(ctx), destID)
+}
+
+// IngestPropertyDescription fetches the description, review score, review count
+// and highlights from upstream and stores them without touching the images
+func (s *PropDescService) IngestPropertyDescription(ctx context.Context, destID string) (_ *models.PropertyDescription, err error) {
+    ctx, span := tracing.Start(ctx, "PropDescService.IngestPropertyDescription", attribute.String("dest_id", destID))
+    defer tracing.End(span, &err)
+
+    fetched, err := s.fetchPropertyDescriptionFromAPI(ctx, destID)
+    if err != nil {
+        return nil, err
+    }
+
+    o := orm.NewOrm()
+    existing := models.PropertyDescription{DestID: destID}
+    err = o.ReadWithCtx(ctx, &existing)
+    if err == orm.ErrNoRows {
+        if _, err := o.InsertWithCtx(ctx, fetched); err != nil {
+            return nil, fmt.Errorf("failed to insert property description %s: %v", destID, err)
+        }
+        if err := s.updatePropertyRating(ctx, fetched); err != nil {
+            return nil, err
+        }
+        return fetched, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    fetched.Images = existing.Images
+    fetched.ImagesFetchedAt = existing.ImagesFetchedAt
+    fetched.ImagesHash = existing.ImagesHash
+    if existing.DescriptionHash != "" && fetched.DescriptionHash != existing.DescriptionHash {
+        // Translations of the old text are fetched again on demand
+        slog.InfoContext(ctx, "description changed upstream", "dest_id", destID)
+        if err := s.translations.Delete(models.TranslationEntityProperty, destID,
+            models.TranslationFieldDescription); err != nil {
+            slog.ErrorContext(ctx, "error deleting description translations", "error", err)
+        }
+    }
+    _, err = o.UpdateWithCtx(ctx, fetched,
+        "Description", "DescriptionHash", "Rating", "Review", "ReviewCount", "Highlights",
+        "Language", "DescriptionFetchedAt", "ReviewsFetchedAt")
+    if err != nil {
+        return nil, fmt.Errorf("failed to update property description %s: %v", destID, err)
+    }
+
+    if err := s.updatePropertyRating(ctx, fetched); err != nil {
+        return nil, err
+    }
+
+    return fetched, nil
+}
+
+// updatePropertyRating copies the upstream review score onto the listed property for rating filters
+func (s *PropDescService) updatePropertyRating(ctx context.Context, description *models.PropertyDescription) error {
+    err := utils.GetDB().WithContext(ctx).Model(&models.Property{}).
+        Where("dest_id = ?", description.DestID).
+        Update("rating", description.Rating).Error
+    if err != nil {
+        return fmt.Errorf("failed to update rating for property %s: %v", description.DestID, err)
+    }
+
+    s.reindex(ctx, description.DestID)
+    return nil
+}
+
+// reindex refreshes the property's search documents; a failure only leaves the index stale
+func (s *PropDescService) reindex(ctx context.Context, destID string) {
+    if err := NewSearchService(utils.GetDB()).IndexProperty(ctx, destID); err != nil {
+        slog.ErrorContext(ctx, "error indexing property", "dest_id", destID, "error", err)
+    }
+}
+
+// fetchPropertyDescriptionFromAPI fetches property description and review data from external API
+func (s *PropDescService) fetchPropertyDescriptionFromAPI(ctx context.Context, destID string) (*models.PropertyDescription, error) {
+    description, err := s.fetchDescriptionText(ctx, destID, s.language)
+    if err != nil {
+        return nil, fmt.Errorf("failed to fetch description for %s: %w", destID, err)
+    }
+    descriptionFetchedAt := time.Now()
+
+    details := &models.PropertyDescription{
+        DestID:               destID,
+        Description:          description,
+        Language:             s.language,
+        DescriptionFetchedAt: descriptionFetchedAt,
+        DescriptionHash:      contentHash(description),
+    }
+
+    if err := s.fetchReviewData(ctx, details); err != nil {
+        return nil, fmt.Errorf("failed to fetch reviews for %s: %w", destID, err)
+    }
+    details.ReviewsFetchedAt = time.Now()
+
+    return details, nil
+}
+
+func (s *PropDescService) fetchDescriptionText(ctx context.Context, destID, language string) (string, error) {
+    apiURL := fmt.Sprintf("https://booking-com18.p.rapidapi.com/stays/get-description?hotelId=%s&languageCode=%s",
+        url.QueryEscape(destID), url.QueryEscape(language))
+
+    body, err := s.apiClient.MakeRequest(ctx, apiURL)
+    if err != nil {
+        return "", err
+    }
+
+    var response struct {
+        Data []struct {
+            Description       string `json:"description"`
+            DescriptionTypeID int    `json:"descriptiontype_id"`
+        } `json:"data"`
+    }
+    if err := json.Unmarshal(body, &response); err != nil {
+        return "", apperrors.UpstreamUnavailable(err, "upstream description response could not be parsed")
+    }
+
+    // Prefer the main description, fall back to the first non-empty text
+    var fallback string
+    for _, item := range response.Data {
+        if item.Description == "" {
+            continue
+        }
+        if item.DescriptionTypeID == mainDescriptionTypeID {
+            return item.Description, nil
+        }
+        if fallback == "" {
+            fallback = item.Description
+        }
+    }
+
+    return fallback, nil
+}
+
+func (s *PropDescService) fetchReviewData(ctx context.Context, details *models.PropertyDescription) error {
+    checkin := time.Now().AddDate(0, 0, 1)
+    checkout := checkin.AddDate(0, 0, 1)
+    apiURL := fmt.Sprintf("https://booking-com18.p.rapidapi.com/stays/detail?hotelId=%s&checkinDate=%s&checkoutDate=%s&languageCode=%s&units=metric",
+        url.QueryEscape(details.DestID), checkin.Format("2006-01-02"), checkout.Format("2006-01-02"),
+        url.QueryEscape(s.language))
+
+    body, err := s.apiClient.MakeRequest(ctx, apiURL)
+    if err != nil {
+        return err
+    }
+
+    var response struct {
+        Data struct {
+            ReviewScore     float64 `json:"review_score"`
+            ReviewCount     int     `json:"review_nr"`
+            ReviewScoreWord string  `json:"review_score_word"`
+            Highlights      []struct {
+                Name string `json:"name"`
+            } `json:"property_highlight_strip"`
+        } `json:"data"`
+    }
+    if err := json.Unmarshal(body, &response); err != nil {
+        return apperrors.UpstreamUnavailable(err, "upstream detail response could not be parsed")
+    }
+
+    highlights := make([]string, 0, len(response.Data.Highlights))
+    for _, highlight := range response.Data.Highlights {
+        if highlight.Name != "" {
+            highlights = append(highlights, highlight.Name)
+        }
+    }
+    highlightsJSON, err := json.Marshal(highlights)
+    if err != nil {
+        return fmt.Errorf("failed to marshal highlights: %v", err)
+    }
+
+    details.Rating = response.Data.ReviewScore
+    details.Review = response.Data.ReviewScoreWord
+    details.ReviewCount = response.Data.ReviewCount
+    details.Highlights = string(highlightsJSON)
+    return nil
 }
 
 func (s *PropDescService) ProcessPropertyDescriptions(destIDs []string) error {
-    results := make(chan *models.PropertyDescription, len(destIDs))
     var wg sync.WaitGroup
 
+    // Requests are serialised by the shared upstream rate limiter in the API client
     for _, destID := range destIDs {
         wg.Add(1)
         go func(id string) {
             defer wg.Done()
-            
-            err := s.rateLimiter.Wait(context.Background())
-            if err != nil {
-                logs.Error("Rate limiter error for property %s: %v", id, err)
-                return
-            }
 
-            details, err := s.fetchPropertyDescriptionFromAPI(id)
-            if err != nil {
-                logs.Error("Error fetching details for property %s: %v", id, err)
-                return
+            if _, err := s.IngestPropertyDescription(context.Background(), id); err != nil {
+                slog.Error("error ingesting description", "dest_id", id, "error", err)
             }
-
-            results <- details
         }(destID)
     }
 
-    go func() {
-        wg.Wait()
-        close(results)
-    }()
-
-    for details := range results {
-        if err := s.SavePropertyDescription(details); err != nil {
-            logs.Error("Error saving property details: %v", err)
-        }
-    }
-
+    wg.Wait()
     return nil
-}
\ No newline at end of file
+}
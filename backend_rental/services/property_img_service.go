@@ -5,17 +5,22 @@ import (
     "context"
     "encoding/json"
     "fmt"
-    "net/http"
+    neturl "net/url"
+    "log/slog"
+    "path"
+    "strings"
     "time"
     beego "github.com/beego/beego/v2/server/web"
     "github.com/beego/beego/v2/client/orm"
     "backend_rental/models"
-    "backend_rental/utils/ratelimiter"
+    "backend_rental/utils/apiclient"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/tracing"
+    "go.opentelemetry.io/otel/attribute"
 )
 
 type PropertyImageService struct {
-    rateLimiter *ratelimiter.APIRateLimiter
-    rapidAPIKey string
+    apiClient *apiclient.APIClient
 }
 
 // NewPropertyImageService creates and returns a new PropertyImageService instance
@@ -25,24 +30,48 @@ func NewPropertyImageService() (*PropertyImageService, error) {
         return nil, fmt.Errorf("failed to get rapidapikey from config: %v", err)
     }
     return &PropertyImageService{
-        rateLimiter: ratelimiter.GetInstance(),
-        rapidAPIKey: rapidAPIKey,
+        apiClient: apiclient.NewAPIClient(rapidAPIKey),
     }, nil
 }
 
-func (s *PropertyImageService) GetPropertyDetails(destID string) (*models.PropertyDescription, error) {
-    // First check if we have the data in our database
-    o := orm.NewOrm()
+// GetPropertyDetails returns the stored description of a property with its
+// photos. Stored photos older than ImagesMaxAge are still served while the
+// refresh queue re-fetches them; photos never fetched, or refresh set by an
+// admin, are fetched from upstream before answering.
+func (s *PropertyImageService) GetPropertyDetails(ctx context.Context, destID string, refresh bool) (_ *models.PropertyDescription, err error) {
+    ctx, span := tracing.Start(ctx, "PropertyImageService.GetPropertyDetails", attribute.String("dest_id", destID))
+    defer tracing.End(span, &err)
+
     propertyDesc := &models.PropertyDescription{DestID: destID}
-    err := o.Read(propertyDesc)
-    if err == nil {
+    err = orm.NewOrm().ReadWithCtx(ctx, propertyDesc)
+    if err != nil && err != orm.ErrNoRows {
+        return nil, fmt.Errorf("failed to read property description: %v", err)
+    }
+
+    if err == nil && propertyDesc.Images != "" && !refresh {
+        if stale(propertyDesc.ImagesFetchedAt, ImagesMaxAge()) {
+            DefaultRefreshQueue().Enqueue(RefreshImages, destID)
+        }
         return propertyDesc, nil
     }
 
-    // If not in database, fetch from API
-    images, err := s.fetchImagesFromAPI(destID)
+    if refresh {
+        ctx = apiclient.WithRefresh(ctx)
+    }
+    return s.RefreshImages(ctx, destID)
+}
+
+// RefreshImages fetches the photos of a property from upstream and stores
+// them with their fetch time and hash, ingesting the description too when
+// the property has no stored row yet. The categorized images of the
+// description are kept alongside the property_photos rows for older clients.
+func (s *PropertyImageService) RefreshImages(ctx context.Context, destID string) (_ *models.PropertyDescription, err error) {
+    ctx, span := tracing.Start(ctx, "PropertyImageService.RefreshImages", attribute.String("dest_id", destID))
+    defer tracing.End(span, &err)
+
+    photos, images, err := s.fetchPhotosFromAPI(ctx, destID)
     if err != nil {
-        return nil, fmt.Errorf("failed to fetch images: %v", err)
+        return nil, fmt.Errorf("failed to fetch images: %w", err)
     }
 
     // Convert images to JSON string for storage
@@ -50,60 +79,199 @@ func (s *PropertyImageService) GetPropertyDetails(destID string) (*models.Proper
     if err != nil {
         return nil, fmt.Errorf("failed to marshal images: %v", err)
     }
+    photosJSON, err := json.Marshal(photos)
+    if err != nil {
+        return nil, fmt.Errorf("failed to marshal photos: %v", err)
+    }
+    hash := contentHash(string(photosJSON))
 
-    // Create new property description
-    propertyDesc = &models.PropertyDescription{
-        DestID:      destID,
-        Images:      string(imagesJSON),
-        Description: "Default description",
-        Rating:      0,
-        Review:      "",
+    o := orm.NewOrm()
+    propertyDesc := &models.PropertyDescription{DestID: destID}
+    err = o.ReadWithCtx(ctx, propertyDesc)
+    if err == orm.ErrNoRows {
+        // Ingest the real description alongside the images; a failure here only
+        // leaves the description unfetched so the description endpoint retries it
+        propertyDesc, err = NewPropDescService().IngestPropertyDescription(ctx, destID)
+        if err != nil {
+            slog.ErrorContext(ctx, "error ingesting description", "dest_id", destID, "error", err)
+            propertyDesc = &models.PropertyDescription{DestID: destID}
+            if _, err := o.InsertWithCtx(ctx, propertyDesc); err != nil {
+                return nil, fmt.Errorf("failed to insert into database: %v", err)
+            }
+        }
+    } else if err != nil {
+        return nil, fmt.Errorf("failed to read property description: %v", err)
     }
 
-    // Save to database
-    _, err = o.Insert(propertyDesc)
-    if err != nil {
-        return nil, fmt.Errorf("failed to insert into database: %v", err)
+    propertyDesc.ImagesFetchedAt = time.Now()
+    if propertyDesc.ImagesHash == hash {
+        if _, err := o.UpdateWithCtx(ctx, propertyDesc, "ImagesFetchedAt"); err != nil {
+            return nil, fmt.Errorf("failed to update images in database: %v", err)
+        }
+        return propertyDesc, nil
     }
 
+    propertyDesc.Images = string(imagesJSON)
+    propertyDesc.ImagesHash = hash
+    if err := s.savePhotos(ctx, propertyDesc, photos); err != nil {
+        return nil, err
+    }
     return propertyDesc, nil
 }
 
-func (s *PropertyImageService) fetchImagesFromAPI(destID string) (*models.CategorizedImages, error) {
-    err := s.rateLimiter.Wait(context.Background())
+// savePhotos replaces the property_photos rows of a property and updates its
+// images in one transaction
+func (s *PropertyImageService) savePhotos(ctx context.Context, propertyDesc *models.PropertyDescription, photos []models.PropertyPhoto) error {
+    tx, err := orm.NewOrm().BeginWithCtx(ctx)
     if err != nil {
-        return nil, err
+        return err
     }
 
-    url := fmt.Sprintf("https://booking-com18.p.rapidapi.com/stays/get-photos?hotelId=%s", destID)
-    req, err := http.NewRequest("GET", url, nil)
+    _, err = tx.RawWithCtx(ctx, "DELETE FROM property_photos WHERE dest_id = ? AND source = ?",
+        propertyDesc.DestID, models.PhotoSourceUpstream).Exec()
     if err != nil {
-        return nil, err
+        tx.Rollback()
+        return fmt.Errorf("error clearing photos of property %s: %v", propertyDesc.DestID, err)
     }
+    for i := range photos {
+        photos[i].FetchedAt = propertyDesc.ImagesFetchedAt
+        if _, err := tx.InsertWithCtx(ctx, &photos[i]); err != nil {
+            tx.Rollback()
+            return fmt.Errorf("error saving photo %d of property %s: %v", photos[i].PhotoID, propertyDesc.DestID, err)
+        }
+    }
+    if _, err := tx.UpdateWithCtx(ctx, propertyDesc, "Images", "ImagesHash", "ImagesFetchedAt"); err != nil {
+        tx.Rollback()
+        return fmt.Errorf("failed to update images in database: %v", err)
+    }
+
+    return tx.Commit()
+}
 
-    req.Header.Add("x-rapidapi-host", "booking-com18.p.rapidapi.com")
-    req.Header.Add("x-rapidapi-key", s.rapidAPIKey)
+// GetPhotos returns the stored photos of a property grouped by tag, fetching
+// them first like GetPropertyDetails. Only the category tag is returned when
+// it is set; the cover is chosen from all photos either way.
+func (s *PropertyImageService) GetPhotos(ctx context.Context, destID, category string, refresh bool) (_ *models.PropertyPhotos, err error) {
+    ctx, span := tracing.Start(ctx, "PropertyImageService.GetPhotos", attribute.String("dest_id", destID))
+    defer tracing.End(span, &err)
 
-    client := &http.Client{Timeout: 10 * time.Second}
-    resp, err := client.Do(req)
+    propertyDesc, err := s.GetPropertyDetails(ctx, destID, refresh)
     if err != nil {
         return nil, err
     }
-    defer resp.Body.Close()
+
+    var photos []models.PropertyPhoto
+    o := orm.NewOrm()
+    load := func() error {
+        // Host uploads come first
+        _, err := o.QueryTable(new(models.PropertyPhoto)).
+            Filter("dest_id", destID).
+            OrderBy("source", "position").
+            AllWithCtx(ctx, &photos)
+        return err
+    }
+    if err := load(); err != nil {
+        return nil, fmt.Errorf("failed to read photos of property %s: %v", destID, err)
+    }
+    // Images stored before property_photos existed have no rows yet; a
+    // property without upstream photos has the hash of an empty list
+    upstream := 0
+    for _, photo := range photos {
+        if photo.Source == models.PhotoSourceUpstream {
+            upstream++
+        }
+    }
+    if upstream == 0 && propertyDesc.ImagesHash != contentHash("[]") && !refresh {
+        if _, err := s.RefreshImages(ctx, destID); err != nil {
+            return nil, err
+        }
+        if err := load(); err != nil {
+            return nil, fmt.Errorf("failed to read photos of property %s: %v", destID, err)
+        }
+    }
+
+    lastModified := propertyDesc.ImagesFetchedAt
+    for i := range photos {
+        if err := json.Unmarshal([]byte(photos[i].URLs), &photos[i].Sizes); err != nil {
+            return nil, fmt.Errorf("invalid URLs of photo %d of property %s: %v", photos[i].PhotoID, destID, err)
+        }
+        photos[i].ProxyURL = fmt.Sprintf("/img/%d", photos[i].PhotoID)
+        if photos[i].FetchedAt.After(lastModified) {
+            lastModified = photos[i].FetchedAt
+        }
+    }
+    grouped := groupPhotos(destID, photos, category)
+    grouped.LastModified = lastModified
+    return grouped, nil
+}
+
+// coverTags are the tags a cover photo is picked from, in order of preference
+var coverTags = []string{"Property building", "Property"}
+
+// groupPhotos groups photos by tag in the order each tag first appears. The
+// cover is the first host upload, or else the first upstream photo of the
+// coverTags.
+func groupPhotos(destID string, photos []models.PropertyPhoto, category string) *models.PropertyPhotos {
+    result := &models.PropertyPhotos{DestID: destID, Categories: []models.PhotoCategory{}}
+    index := map[string]int{}
+    for _, photo := range photos {
+        if category != "" && !strings.EqualFold(photo.Tag, category) {
+            continue
+        }
+        i, ok := index[photo.Tag]
+        if !ok {
+            i = len(result.Categories)
+            index[photo.Tag] = i
+            result.Categories = append(result.Categories, models.PhotoCategory{Tag: photo.Tag})
+        }
+        result.Categories[i].Photos = append(result.Categories[i].Photos, photo)
+    }
+
+    for i := range photos {
+        if photos[i].Source == models.PhotoSourceHost {
+            result.Cover = &photos[i]
+            return result
+        }
+    }
+    for _, tag := range coverTags {
+        for i := range photos {
+            if photos[i].Tag == tag {
+                result.Cover = &photos[i]
+                return result
+            }
+        }
+    }
+    if len(photos) > 0 {
+        result.Cover = &photos[0]
+    }
+    return result
+}
+
+// fetchPhotosFromAPI returns every upstream photo of a property in upstream
+// order, and the URLs of the three tags of CategorizedImages. Each upstream
+// item is one photo whose images are its URLs per size.
+func (s *PropertyImageService) fetchPhotosFromAPI(ctx context.Context, destID string) ([]models.PropertyPhoto, *models.CategorizedImages, error) {
+    url := fmt.Sprintf("https://booking-com18.p.rapidapi.com/stays/get-photos?hotelId=%s", destID)
+    body, err := s.apiClient.MakeRequest(ctx, url)
+    if err != nil {
+        return nil, nil, err
+    }
 
     var apiResponse struct {
         Data []struct {
-            ID     int      `json:"id"`
-            Tag    string   `json:"tag"`
-            Images []string `json:"images"`
+            ID      int64    `json:"id"`
+            Tag     string   `json:"tag"`
+            Caption string   `json:"caption"`
+            Images  []string `json:"images"`
         } `json:"data"`
     }
 
-    if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-        return nil, err
+    if err := json.Unmarshal(body, &apiResponse); err != nil {
+        return nil, nil, apperrors.UpstreamUnavailable(err, "upstream photo response could not be parsed")
     }
 
     images := &models.CategorizedImages{}
+    photos := make([]models.PropertyPhoto, 0, len(apiResponse.Data))
     for _, item := range apiResponse.Data {
         switch item.Tag {
         case "Property building":
@@ -113,7 +281,47 @@ func (s *PropertyImageService) fetchImagesFromAPI(destID string) (*models.Catego
         case "Room":
             images.Room = append(images.Room, item.Images...)
         }
+        if len(item.Images) == 0 {
+            continue
+        }
+        sizes := make(map[string]string, len(item.Images))
+        for _, imageURL := range item.Images {
+            sizes[photoSize(imageURL)] = imageURL
+        }
+        urls, err := json.Marshal(sizes)
+        if err != nil {
+            return nil, nil, err
+        }
+
+        tag := item.Tag
+        if tag == "" {
+            tag = "Other"
+        }
+        photos = append(photos, models.PropertyPhoto{
+            DestID:   destID,
+            PhotoID:  item.ID,
+            Source:   models.PhotoSourceUpstream,
+            Tag:      tag,
+            Position: len(photos),
+            Caption:  item.Caption,
+            URLs:     string(urls),
+            Sizes:    sizes,
+        })
     }
 
-    return images, nil
-}
\ No newline at end of file
+    return photos, images, nil
+}
+
+// photoSize names the size of a photo URL after the directory holding it,
+// e.g. max1280x900 in .../images/hotel/max1280x900/123.jpg
+func photoSize(imageURL string) string {
+    u, err := neturl.Parse(imageURL)
+    if err != nil {
+        return "original"
+    }
+    dir := path.Base(path.Dir(u.Path))
+    if dir == "." || dir == "/" {
+        return "original"
+    }
+    return dir
+}
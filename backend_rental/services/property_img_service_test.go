@@ -0,0 +1,42 @@
+package services
+
+import (
+    "testing"
+
+    "backend_rental/models"
+)
+
+func TestPhotoSize(t *testing.T) {
+    tests := map[string]string{
+        "https://cf.bstatic.com/xdata/images/hotel/max1280x900/123.jpg?k=abc": "max1280x900",
+        "https://cf.bstatic.com/xdata/images/hotel/square60/123.jpg":          "square60",
+        "https://example.com/123.jpg":                                         "original",
+    }
+    for url, want := range tests {
+        if got := photoSize(url); got != want {
+            t.Errorf("photoSize(%q) = %q, want %q", url, got, want)
+        }
+    }
+}
+
+func TestGroupPhotos(t *testing.T) {
+    photos := []models.PropertyPhoto{
+        {PhotoID: 1, Tag: "Room", Position: 0},
+        {PhotoID: 2, Tag: "Pool", Position: 1},
+        {PhotoID: 3, Tag: "Property", Position: 2},
+        {PhotoID: 4, Tag: "Room", Position: 3},
+    }
+
+    grouped := groupPhotos("7", photos, "")
+    if len(grouped.Categories) != 3 || grouped.Categories[0].Tag != "Room" || len(grouped.Categories[0].Photos) != 2 {
+        t.Errorf("categories = %+v", grouped.Categories)
+    }
+    if grouped.Cover == nil || grouped.Cover.PhotoID != 3 {
+        t.Errorf("cover = %+v", grouped.Cover)
+    }
+
+    rooms := groupPhotos("7", photos, "room")
+    if len(rooms.Categories) != 1 || rooms.Categories[0].Tag != "Room" || rooms.Cover.PhotoID != 3 {
+        t.Errorf("room photos = %+v", rooms)
+    }
+}
@@ -2,52 +2,320 @@
 package services
 
 import (
+    "context"
+    "database/sql"
     "encoding/json"
     "fmt"
-    "io"
-    "net/http"
     "net/url"
+    "strconv"
+    "strings"
+    "time"
     "backend_rental/models"
+    "backend_rental/utils/apiclient"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/pagination"
+    "backend_rental/utils/tracing"
+    "go.opentelemetry.io/otel/attribute"
     "gorm.io/gorm"
 )
 
+// propertySortOrders maps the public sort keys to keyset orderings ending in the unique id
+var propertySortOrders = map[string]pagination.Order{
+    "":           {{Column: "id"}},
+    "newest":     {{Column: "created_at", Desc: true}, {Column: "id", Desc: true}},
+    "name":       {{Column: "name"}, {Column: "id"}},
+    "price_asc":  {{Column: "price"}, {Column: "id"}},
+    "price_desc": {{Column: "price", Desc: true}, {Column: "id"}},
+    "rating":     {{Column: "rating", Desc: true}, {Column: "id"}},
+    "bedrooms":   {{Column: "bedrooms", Desc: true}, {Column: "id"}},
+}
+
 // PropertyServiceInterface defines the methods that PropertyService must implement
 type PropertyServiceInterface interface {
-    ListProperties(page, pageSize int) ([]models.Property, int64, error)
+    ListProperties(ctx context.Context, filter models.PropertyFilter, sort string, params pagination.Params) ([]models.Property, pagination.Page, error)
+    GetFacets(ctx context.Context, filter models.PropertyFilter) (*models.PropertyFacets, error)
+    LastModified(ctx context.Context, filter models.PropertyFilter) (time.Time, error)
+    SearchPropertyIDs(ctx context.Context, filter models.PropertyFilter) ([]string, error)
+    GetPropertiesByDestIDs(ctx context.Context, destIDs []string) ([]models.Property, error)
+    GetPropertiesByCities(ctx context.Context, cities []CityKey, limit int) (map[CityKey][]models.Property, error)
+    GetAmenities(ctx context.Context, destIDs []string) (map[string][]string, error)
     FetchAndStoreProperties() error
 }
 
 type PropertyService struct {
-    db          *gorm.DB
-    httpClient  *http.Client
-    rapidAPIKey string
+    db        *gorm.DB
+    apiClient *apiclient.APIClient
 }
 
 // NewPropertyService creates a new PropertyService with db connection and API key
 func NewPropertyService(db *gorm.DB, rapidAPIKey string) PropertyServiceInterface {
     return &PropertyService{
-        db:          db,
-        httpClient:  &http.Client{},
-        rapidAPIKey: rapidAPIKey,
+        db:        db,
+        apiClient: apiclient.NewAPIClient(rapidAPIKey),
     }
 }
 
-// ListProperties retrieves properties from the database with pagination
-func (s *PropertyService) ListProperties(page, pageSize int) ([]models.Property, int64, error) {
+// ListProperties retrieves a page of the properties matching the filter
+func (s *PropertyService) ListProperties(ctx context.Context, filter models.PropertyFilter, sort string, params pagination.Params) (_ []models.Property, _ pagination.Page, err error) {
+    ctx, span := tracing.Start(ctx, "PropertyService.ListProperties", attribute.String("sort", sort))
+    defer tracing.End(span, &err)
+
+    order, ok := propertySortOrders[sort]
+    if !ok {
+        return nil, pagination.Page{}, apperrors.Validation("invalid sort: %s", sort)
+    }
+
+    db := s.db.WithContext(ctx)
+    query := applyPropertyFilter(db.Model(&models.Property{}), filter)
+    if params.Cursor != nil {
+        where, args, err := order.Where(params.Cursor)
+        if err != nil {
+            return nil, pagination.Page{}, err
+        }
+        query = query.Where(where, args...)
+    }
+
     var properties []models.Property
-    var total int64
-    
-    if err := s.db.Model(&models.Property{}).Count(&total).Error; err != nil {
-        return nil, 0, err
+    err = query.Order(order.Clause(params.Backward())).Limit(params.Limit + 1).Find(&properties).Error
+    if err != nil {
+        return nil, pagination.Page{}, err
     }
-    
-    offset := (page - 1) * pageSize
-    err := s.db.Offset(offset).Limit(pageSize).Find(&properties).Error
+
+    properties, page := pagination.Paginate(properties, params, func(p models.Property) []string {
+        return propertyKeyValues(order, p)
+    })
+
+    if params.IncludeTotal {
+        var total int64
+        if err := applyPropertyFilter(db.Model(&models.Property{}), filter).Count(&total).Error; err != nil {
+            return nil, pagination.Page{}, err
+        }
+        page.TotalCount = &total
+    }
+
+    return properties, page, nil
+}
+
+// propertyKeyValues returns the property's values of the ordering columns in cursor form
+func propertyKeyValues(order pagination.Order, p models.Property) []string {
+    values := make([]string, len(order))
+    for i, key := range order {
+        switch key.Column {
+        case "id":
+            values[i] = strconv.FormatUint(uint64(p.ID), 10)
+        case "created_at":
+            values[i] = p.CreatedAt.Format(time.RFC3339Nano)
+        case "name":
+            values[i] = p.Name
+        case "price":
+            values[i] = strconv.FormatFloat(p.Price, 'f', -1, 64)
+        case "rating":
+            values[i] = strconv.FormatFloat(p.Rating, 'f', -1, 64)
+        case "bedrooms":
+            values[i] = strconv.Itoa(p.Bedrooms)
+        }
+    }
+    return values
+}
+
+// GetFacets counts the properties matching the filter per type, amenity and bedroom count
+func (s *PropertyService) GetFacets(ctx context.Context, filter models.PropertyFilter) (_ *models.PropertyFacets, err error) {
+    ctx, span := tracing.Start(ctx, "PropertyService.GetFacets")
+    defer tracing.End(span, &err)
+
+    db := s.db.WithContext(ctx)
+    facets := &models.PropertyFacets{
+        Types:     []models.FacetCount{},
+        Amenities: []models.FacetCount{},
+        Bedrooms:  []models.FacetCount{},
+    }
+
+    err = applyPropertyFilter(db.Model(&models.Property{}), filter).
+        Select("type AS value, COUNT(*) AS count").
+        Where("type <> ''").
+        Group("type").
+        Order("count DESC, value ASC").
+        Scan(&facets.Types).Error
     if err != nil {
-        return nil, 0, err
+        return nil, fmt.Errorf("error counting property types: %v", err)
     }
-    
-    return properties, total, nil
+
+    err = applyPropertyFilter(db.Model(&models.Property{}), filter).
+        Select("CAST(bedrooms AS TEXT) AS value, COUNT(*) AS count").
+        Group("bedrooms").
+        Order("bedrooms ASC").
+        Scan(&facets.Bedrooms).Error
+    if err != nil {
+        return nil, fmt.Errorf("error counting bedrooms: %v", err)
+    }
+
+    matching := applyPropertyFilter(db.Model(&models.Property{}), filter).Select("dest_id")
+    err = db.Model(&models.PropertyAmenity{}).
+        Select("name AS value, COUNT(*) AS count").
+        Where("dest_id IN (?)", matching).
+        Group("name").
+        Order("count DESC, value ASC").
+        Scan(&facets.Amenities).Error
+    if err != nil {
+        return nil, fmt.Errorf("error counting amenities: %v", err)
+    }
+
+    return facets, nil
+}
+
+// LastModified returns when a property matching the filter was last updated
+// or deleted, or the zero time when none matches
+func (s *PropertyService) LastModified(ctx context.Context, filter models.PropertyFilter) (_ time.Time, err error) {
+    ctx, span := tracing.Start(ctx, "PropertyService.LastModified")
+    defer tracing.End(span, &err)
+
+    // Deleted rows count too, so a listing that lost a property is modified
+    var lastModified sql.NullTime
+    err = applyPropertyFilter(s.db.WithContext(ctx).Unscoped().Model(&models.Property{}), filter).
+        Select("MAX(GREATEST(updated_at, deleted_at))").
+        Row().Scan(&lastModified)
+    if err != nil {
+        return time.Time{}, fmt.Errorf("error loading the last property change: %v", err)
+    }
+    return lastModified.Time, nil
+}
+
+// SearchPropertyIDs returns the dest IDs of every property matching the filter
+func (s *PropertyService) SearchPropertyIDs(ctx context.Context, filter models.PropertyFilter) (_ []string, err error) {
+    ctx, span := tracing.Start(ctx, "PropertyService.SearchPropertyIDs")
+    defer tracing.End(span, &err)
+
+    var destIDs []string
+    err = applyPropertyFilter(s.db.WithContext(ctx).Model(&models.Property{}), filter).
+        Order("dest_id").
+        Pluck("dest_id", &destIDs).Error
+    return destIDs, err
+}
+
+// GetPropertiesByDestIDs returns the stored properties with the given dest IDs
+func (s *PropertyService) GetPropertiesByDestIDs(ctx context.Context, destIDs []string) (_ []models.Property, err error) {
+    ctx, span := tracing.Start(ctx, "PropertyService.GetPropertiesByDestIDs", attribute.Int("dest_ids", len(destIDs)))
+    defer tracing.End(span, &err)
+
+    var properties []models.Property
+    if len(destIDs) == 0 {
+        return properties, nil
+    }
+    err = s.db.WithContext(ctx).Where("dest_id IN ?", destIDs).Order("dest_id").Find(&properties).Error
+    return properties, err
+}
+
+// CityKey identifies the properties of a city by their stored, untranslated names
+type CityKey struct {
+    CityName string
+    Country  string
+}
+
+// GetPropertiesByCities returns up to limit properties of each city in one
+// query, keyed by city
+func (s *PropertyService) GetPropertiesByCities(ctx context.Context, cities []CityKey, limit int) (_ map[CityKey][]models.Property, err error) {
+    ctx, span := tracing.Start(ctx, "PropertyService.GetPropertiesByCities", attribute.Int("cities", len(cities)))
+    defer tracing.End(span, &err)
+
+    result := make(map[CityKey][]models.Property, len(cities))
+    if len(cities) == 0 {
+        return result, nil
+    }
+
+    pairs := make([][]interface{}, len(cities))
+    for i, city := range cities {
+        pairs[i] = []interface{}{city.CityName, city.Country}
+    }
+
+    var properties []models.Property
+    err = s.db.WithContext(ctx).Raw(`
+        SELECT * FROM (
+            SELECT p.*, ROW_NUMBER() OVER (PARTITION BY city_name, country ORDER BY id) AS position
+            FROM properties p
+            WHERE deleted_at IS NULL AND (city_name, country) IN ?
+        ) ranked
+        WHERE position <= ?
+        ORDER BY city_name, country, position`, pairs, limit).Scan(&properties).Error
+    if err != nil {
+        return nil, fmt.Errorf("error loading properties by city: %v", err)
+    }
+
+    for _, property := range properties {
+        key := CityKey{CityName: property.CityName, Country: property.Country}
+        result[key] = append(result[key], property)
+    }
+    return result, nil
+}
+
+// GetAmenities returns the stored amenity names of properties keyed by dest ID
+func (s *PropertyService) GetAmenities(ctx context.Context, destIDs []string) (_ map[string][]string, err error) {
+    ctx, span := tracing.Start(ctx, "PropertyService.GetAmenities", attribute.Int("dest_ids", len(destIDs)))
+    defer tracing.End(span, &err)
+
+    result := make(map[string][]string, len(destIDs))
+    if len(destIDs) == 0 {
+        return result, nil
+    }
+
+    var amenities []models.PropertyAmenity
+    err = s.db.WithContext(ctx).Where("dest_id IN ?", destIDs).Order("dest_id, name").Find(&amenities).Error
+    if err != nil {
+        return nil, fmt.Errorf("error loading amenities: %v", err)
+    }
+    for _, amenity := range amenities {
+        result[amenity.DestID] = append(result[amenity.DestID], amenity.Name)
+    }
+    return result, nil
+}
+
+// likeEscaper escapes the LIKE wildcards so user input matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike escapes s for a LIKE pattern with ESCAPE '\'
+func escapeLike(s string) string {
+    return likeEscaper.Replace(s)
+}
+
+func applyPropertyFilter(query *gorm.DB, filter models.PropertyFilter) *gorm.DB {
+    if filter.CityID != "" {
+        query = query.Where("city_id = ?", filter.CityID)
+    }
+    if filter.CityName != "" {
+        query = query.Where(`city_name ILIKE ? ESCAPE '\'`, "%"+escapeLike(filter.CityName)+"%")
+    }
+    if filter.Name != "" {
+        query = query.Where(`name ILIKE ? ESCAPE '\'`, "%"+escapeLike(filter.Name)+"%")
+    }
+    if filter.Country != "" {
+        query = query.Where(`country ILIKE ? ESCAPE '\'`, escapeLike(filter.Country))
+    }
+    if filter.Type != "" {
+        query = query.Where(`type ILIKE ? ESCAPE '\'`, escapeLike(filter.Type))
+    }
+    if filter.MinBedrooms > 0 {
+        query = query.Where("bedrooms >= ?", filter.MinBedrooms)
+    }
+    if filter.MaxBedrooms > 0 {
+        query = query.Where("bedrooms <= ?", filter.MaxBedrooms)
+    }
+    if filter.MinBathrooms > 0 {
+        query = query.Where("bathrooms >= ?", filter.MinBathrooms)
+    }
+    if filter.MaxBathrooms > 0 {
+        query = query.Where("bathrooms <= ?", filter.MaxBathrooms)
+    }
+    if filter.MinRating > 0 {
+        query = query.Where("rating >= ?", filter.MinRating)
+    }
+    if len(filter.Amenities) > 0 {
+        query = query.Where(`dest_id IN (
+            SELECT dest_id FROM property_amenities
+            WHERE name IN ?
+            GROUP BY dest_id
+            HAVING COUNT(DISTINCT name) = ?
+        )`, filter.Amenities, len(filter.Amenities))
+    }
+    return query
 }
 
 // FetchAndStoreProperties fetches data from an external API and stores it in the database
@@ -57,7 +325,7 @@ func (s *PropertyService) FetchAndStoreProperties() error {
     for _, city := range cities {
         properties, err := s.fetchPropertiesForCity(city)
         if err != nil {
-            return fmt.Errorf("error fetching properties for %s: %v", city, err)
+            return fmt.Errorf("error fetching properties for %s: %w", city, err)
         }
         
         for _, prop := range properties {
@@ -78,22 +346,8 @@ func (s *PropertyService) FetchAndStoreProperties() error {
 func (s *PropertyService) fetchPropertiesForCity(city string) ([]models.Property, error) {
     encodedQuery := url.QueryEscape(city)
     apiURL := fmt.Sprintf("https://booking-com18.p.rapidapi.com/stays/auto-complete?query=%s", encodedQuery)
-    
-    req, err := http.NewRequest("GET", apiURL, nil)
-    if err != nil {
-        return nil, err
-    }
-    
-    req.Header.Add("x-rapidapi-host", "booking-com18.p.rapidapi.com")
-    req.Header.Add("x-rapidapi-key", s.rapidAPIKey)
-    
-    resp, err := s.httpClient.Do(req)
-    if err != nil {
-        return nil, err
-    }
-    defer resp.Body.Close()
-    
-    body, err := io.ReadAll(resp.Body)
+
+    body, err := s.apiClient.MakeRequest(context.Background(), apiURL)
     if err != nil {
         return nil, err
     }
@@ -104,6 +358,7 @@ func (s *PropertyService) fetchPropertiesForCity(city string) ([]models.Property
             Name     string `json:"name"`
             CityID   string `json:"city_id"`
             CityName string `json:"city_name"`
+            Country  string `json:"country"`
         } `json:"data"`
     }
     
@@ -118,6 +373,7 @@ func (s *PropertyService) fetchPropertiesForCity(city string) ([]models.Property
             Name:     item.Name,
             CityID:   item.CityID,
             CityName: item.CityName,
+            Country:  item.Country,
         }
     }
     
@@ -188,7 +444,7 @@ func (s *PropertyService) fetchPropertiesForCity(city string) ([]models.Property
 //     for _, city := range cities {
 //         properties, err := s.fetchPropertiesForCity(city)
 //         if err != nil {
-//             return fmt.Errorf("error fetching properties for %s: %v", city, err)
+//             return fmt.Errorf("error fetching properties for %s: %w", city, err)
 //         }
         
 //         // Store each property in database
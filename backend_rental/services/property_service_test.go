@@ -0,0 +1,18 @@
+package services
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+    cases := map[string]string{
+        "Lisbon":  "Lisbon",
+        "100%":    `100\%`,
+        "a_b":     `a\_b`,
+        `C:\temp`: `C:\\temp`,
+        `\%_`:     `\\\%\_`,
+    }
+    for input, want := range cases {
+        if got := escapeLike(input); got != want {
+            t.Errorf("escapeLike(%q) = %q, want %q", input, got, want)
+        }
+    }
+}
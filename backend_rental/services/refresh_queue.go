@@ -0,0 +1,176 @@
+package services
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "log/slog"
+    "sync"
+    "time"
+
+    "backend_rental/utils/apiclient"
+    "backend_rental/utils/metrics"
+    "backend_rental/utils/tracing"
+    beego "github.com/beego/beego/v2/server/web"
+    "go.opentelemetry.io/otel/attribute"
+)
+
+// Stored upstream content refreshed in the background
+const (
+    RefreshImages      = "images"
+    RefreshDescription = "description"
+)
+
+// Default freshness of stored upstream content, overridden by imagesmaxage
+// and descriptionmaxage
+const (
+    DefaultImagesMaxAge      = 7 * 24 * time.Hour
+    DefaultDescriptionMaxAge = 30 * 24 * time.Hour
+)
+
+// ImagesMaxAge is how long stored photos are served before they are refreshed
+func ImagesMaxAge() time.Duration {
+    return configDuration("imagesmaxage", DefaultImagesMaxAge)
+}
+
+// DescriptionMaxAge is how long a stored description is served before it is refreshed
+func DescriptionMaxAge() time.Duration {
+    return configDuration("descriptionmaxage", DefaultDescriptionMaxAge)
+}
+
+func configDuration(key string, def time.Duration) time.Duration {
+    raw := beego.AppConfig.DefaultString(key, "")
+    if raw == "" {
+        return def
+    }
+    d, err := time.ParseDuration(raw)
+    if err != nil || d <= 0 {
+        slog.Warn("invalid duration setting, using the default", "key", key, "value", raw, "default", def)
+        return def
+    }
+    return d
+}
+
+// stale reports whether content fetched at fetchedAt is older than maxAge;
+// content never fetched is stale
+func stale(fetchedAt time.Time, maxAge time.Duration) bool {
+    return fetchedAt.IsZero() || time.Since(fetchedAt) > maxAge
+}
+
+// contentHash identifies upstream content so refreshes can tell whether it changed
+func contentHash(content string) string {
+    sum := sha256.Sum256([]byte(content))
+    return hex.EncodeToString(sum[:])
+}
+
+type refreshJob struct {
+    kind   string
+    destID string
+}
+
+// RefreshQueue refreshes stale upstream content of properties in the
+// background. A single worker drains it so refreshes queue behind the
+// upstream rate limit instead of competing with each other; a property
+// already queued is not queued again.
+type RefreshQueue struct {
+    jobs    chan refreshJob
+    refresh func(ctx context.Context, kind, destID string) error
+
+    mu      sync.Mutex
+    pending map[refreshJob]bool
+}
+
+// NewRefreshQueue creates a queue holding up to size jobs and starts its
+// worker, which calls refresh for each job
+func NewRefreshQueue(size int, refresh func(ctx context.Context, kind, destID string) error) *RefreshQueue {
+    q := &RefreshQueue{
+        jobs:    make(chan refreshJob, size),
+        refresh: refresh,
+        pending: map[refreshJob]bool{},
+    }
+    go q.work()
+    return q
+}
+
+var (
+    defaultRefreshQueue     *RefreshQueue
+    defaultRefreshQueueOnce sync.Once
+)
+
+// DefaultRefreshQueue returns the shared queue refreshing property images and
+// descriptions; refreshqueuesize sets its capacity
+func DefaultRefreshQueue() *RefreshQueue {
+    defaultRefreshQueueOnce.Do(func() {
+        defaultRefreshQueue = NewRefreshQueue(beego.AppConfig.DefaultInt("refreshqueuesize", 256), refreshContent)
+        metrics.RegisterGauge("refresh_queue", "pending", "Content refreshes queued or running.",
+            func() float64 { return float64(defaultRefreshQueue.Pending()) })
+    })
+    return defaultRefreshQueue
+}
+
+// Enqueue schedules a refresh and reports whether it was queued. A full queue
+// drops the job; the next read of the stale content queues it again.
+func (q *RefreshQueue) Enqueue(kind, destID string) bool {
+    job := refreshJob{kind: kind, destID: destID}
+
+    q.mu.Lock()
+    defer q.mu.Unlock()
+    if q.pending[job] {
+        return false
+    }
+    select {
+    case q.jobs <- job:
+        q.pending[job] = true
+        return true
+    default:
+        slog.Warn("refresh queue full, dropping refresh", "kind", kind, "dest_id", destID)
+        return false
+    }
+}
+
+// Pending returns the number of queued or running refreshes
+func (q *RefreshQueue) Pending() int {
+    q.mu.Lock()
+    defer q.mu.Unlock()
+    return len(q.pending)
+}
+
+// Capacity returns the number of jobs the queue holds before dropping refreshes
+func (q *RefreshQueue) Capacity() int {
+    return cap(q.jobs)
+}
+
+func (q *RefreshQueue) work() {
+    for job := range q.jobs {
+        err := q.refresh(context.Background(), job.kind, job.destID)
+        if err != nil {
+            slog.Error("refresh failed", "kind", job.kind, "dest_id", job.destID, "error", err)
+        }
+        RecordTask("refresh_"+job.kind, err)
+        q.mu.Lock()
+        delete(q.pending, job)
+        q.mu.Unlock()
+    }
+}
+
+// refreshContent re-fetches stored content from upstream, bypassing the
+// upstream response cache. Each refresh is the root span of its own trace.
+func refreshContent(ctx context.Context, kind, destID string) (err error) {
+    ctx, span := tracing.Start(apiclient.WithRefresh(ctx), "refresh "+kind, attribute.String("dest_id", destID))
+    defer tracing.End(span, &err)
+
+    switch kind {
+    case RefreshImages:
+        images, err := NewPropertyImageService()
+        if err != nil {
+            return err
+        }
+        _, err = images.RefreshImages(ctx, destID)
+        return err
+    case RefreshDescription:
+        _, err := NewPropDescService().IngestPropertyDescription(ctx, destID)
+        return err
+    }
+    slog.Warn("unknown refresh kind", "kind", kind)
+    return nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+    "context"
+    "testing"
+    "time"
+)
+
+func TestRefreshQueueSkipsPendingJobs(t *testing.T) {
+    release := make(chan struct{})
+    done := make(chan string, 3)
+    queue := NewRefreshQueue(2, func(ctx context.Context, kind, destID string) error {
+        <-release
+        done <- kind + ":" + destID
+        return nil
+    })
+
+    if !queue.Enqueue(RefreshImages, "1") {
+        t.Fatal("first job not queued")
+    }
+    if queue.Enqueue(RefreshImages, "1") {
+        t.Error("pending job queued twice")
+    }
+    if !queue.Enqueue(RefreshDescription, "1") {
+        t.Error("other kind not queued")
+    }
+    close(release)
+
+    for i := 0; i < 2; i++ {
+        select {
+        case <-done:
+        case <-time.After(time.Second):
+            t.Fatal("refresh did not run")
+        }
+    }
+    for queue.Pending() > 0 {
+        time.Sleep(time.Millisecond)
+    }
+    if !queue.Enqueue(RefreshImages, "1") {
+        t.Error("finished job not queued again")
+    }
+}
+
+func TestStale(t *testing.T) {
+    if !stale(time.Time{}, time.Hour) {
+        t.Error("content never fetched is stale")
+    }
+    if stale(time.Now().Add(-time.Minute), time.Hour) || !stale(time.Now().Add(-2*time.Hour), time.Hour) {
+        t.Error("stale compares the age with maxAge")
+    }
+}
@@ -0,0 +1,332 @@
+// services/review_service.go
+package services
+
+import (
+    "context"
+    "fmt"
+    "math"
+    "strconv"
+    "strings"
+    "time"
+
+    "backend_rental/models"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/pagination"
+    "backend_rental/utils/tracing"
+    "github.com/beego/beego/v2/client/orm"
+    beego "github.com/beego/beego/v2/server/web"
+    "go.opentelemetry.io/otel/attribute"
+)
+
+const maxReviewTextLength = 5000
+
+// reviewSortOrders maps the public sort keys to keyset orderings ending in the unique id
+var reviewSortOrders = map[string]pagination.Order{
+    "newest":  {{Column: "created_at", Desc: true}, {Column: "id", Desc: true}},
+    "oldest":  {{Column: "created_at"}, {Column: "id"}},
+    "highest": {{Column: "overall", Desc: true}, {Column: "created_at", Desc: true}, {Column: "id", Desc: true}},
+    "lowest":  {{Column: "overall"}, {Column: "created_at", Desc: true}, {Column: "id", Desc: true}},
+}
+
+type ReviewService struct{}
+
+func NewReviewService() *ReviewService {
+    return &ReviewService{}
+}
+
+// SubmitReview validates a review and stores it pending moderation
+func (s *ReviewService) SubmitReview(ctx context.Context, review *models.Review) (err error) {
+    ctx, span := tracing.Start(ctx, "ReviewService.SubmitReview", attribute.String("dest_id", review.DestID))
+    defer tracing.End(span, &err)
+
+    review.DestID = strings.TrimSpace(review.DestID)
+    review.AuthorName = strings.TrimSpace(review.AuthorName)
+    review.StayReference = strings.TrimSpace(review.StayReference)
+    review.Text = strings.TrimSpace(review.Text)
+
+    if review.DestID == "" {
+        return apperrors.Validation("dest_id is required")
+    }
+    if review.AuthorName == "" {
+        return apperrors.Validation("author_name is required")
+    }
+    if len(review.Text) > maxReviewTextLength {
+        return apperrors.Validation("text must be at most %d characters", maxReviewTextLength)
+    }
+    for _, score := range []struct {
+        name  string
+        value float64
+    }{
+        {"cleanliness", review.Cleanliness},
+        {"location", review.Location},
+        {"value", review.Value},
+    } {
+        if score.value < 1 || score.value > 10 {
+            return apperrors.Validation("%s must be between 1 and 10", score.name)
+        }
+    }
+
+    o := orm.NewOrm()
+    if review.StayReference != "" {
+        exists := o.QueryTable(new(models.Review)).
+            Filter("dest_id", review.DestID).
+            Filter("stay_reference", review.StayReference).
+            ExistWithCtx(ctx)
+        if exists {
+            return apperrors.Conflict("a review for stay %s already exists", review.StayReference)
+        }
+    }
+
+    if review.Language == "" {
+        review.Language = beego.AppConfig.DefaultString("defaultlanguage", "en-gb")
+    }
+    review.Overall = roundScore((review.Cleanliness + review.Location + review.Value) / 3)
+    review.Status = models.ReviewStatusPending
+    review.Id = 0
+
+    if _, err := o.InsertWithCtx(ctx, review); err != nil {
+        return fmt.Errorf("error saving review: %v", err)
+    }
+    return nil
+}
+
+// ListReviews returns a page of a property's approved reviews
+func (s *ReviewService) ListReviews(ctx context.Context, destID, sort string, params pagination.Params) (_ []models.Review, _ pagination.Page, err error) {
+    ctx, span := tracing.Start(ctx, "ReviewService.ListReviews", attribute.String("dest_id", destID))
+    defer tracing.End(span, &err)
+
+    return s.listReviews(ctx, destID, models.ReviewStatusApproved, sort, params)
+}
+
+// ListLatestReviews returns up to limit approved reviews of each property in
+// the given order, keyed by dest ID, in one query
+func (s *ReviewService) ListLatestReviews(ctx context.Context, destIDs []string, sort string, limit int) (_ map[string][]models.Review, err error) {
+    ctx, span := tracing.Start(ctx, "ReviewService.ListLatestReviews", attribute.Int("dest_ids", len(destIDs)))
+    defer tracing.End(span, &err)
+
+    order, ok := reviewSortOrders[sort]
+    if !ok {
+        return nil, apperrors.Validation("invalid sort: %s", sort)
+    }
+
+    result := make(map[string][]models.Review, len(destIDs))
+    if len(destIDs) == 0 {
+        return result, nil
+    }
+
+    var reviews []models.Review
+    _, err = orm.NewOrm().RawWithCtx(ctx, `
+        SELECT id, dest_id, author_name, stay_reference, cleanliness, location, value, overall,
+               text, language, status, created_at, moderated_at
+        FROM (
+            SELECT r.*, ROW_NUMBER() OVER (PARTITION BY dest_id ORDER BY `+order.Clause(false)+`) AS position
+            FROM review r
+            WHERE status = ? AND dest_id IN (`+strings.TrimSuffix(strings.Repeat("?, ", len(destIDs)), ", ")+`)
+        ) ranked
+        WHERE position <= ?
+        ORDER BY dest_id, position`,
+        models.ReviewStatusApproved, destIDs, limit).QueryRows(&reviews)
+    if err != nil {
+        return nil, fmt.Errorf("error listing latest reviews: %v", err)
+    }
+
+    for _, review := range reviews {
+        result[review.DestID] = append(result[review.DestID], review)
+    }
+    return result, nil
+}
+
+// GetAggregates returns the review aggregates of properties keyed by dest ID;
+// properties without approved reviews are left out
+func (s *ReviewService) GetAggregates(ctx context.Context, destIDs []string) (_ map[string]*models.ReviewAggregate, err error) {
+    ctx, span := tracing.Start(ctx, "ReviewService.GetAggregates", attribute.Int("dest_ids", len(destIDs)))
+    defer tracing.End(span, &err)
+
+    result := make(map[string]*models.ReviewAggregate, len(destIDs))
+    if len(destIDs) == 0 {
+        return result, nil
+    }
+
+    var aggregates []*models.ReviewAggregate
+    _, err = orm.NewOrm().QueryTable(new(models.ReviewAggregate)).
+        Filter("dest_id__in", destIDs).
+        Limit(-1).
+        AllWithCtx(ctx, &aggregates)
+    if err != nil {
+        return nil, fmt.Errorf("error loading review aggregates: %v", err)
+    }
+    for _, aggregate := range aggregates {
+        result[aggregate.DestID] = aggregate
+    }
+    return result, nil
+}
+
+// ListReviewsByStatus returns a page of reviews in a moderation state, across all properties
+func (s *ReviewService) ListReviewsByStatus(ctx context.Context, status string, params pagination.Params) (_ []models.Review, _ pagination.Page, err error) {
+    ctx, span := tracing.Start(ctx, "ReviewService.ListReviewsByStatus", attribute.String("status", status))
+    defer tracing.End(span, &err)
+
+    return s.listReviews(ctx, "", status, "oldest", params)
+}
+
+func (s *ReviewService) listReviews(ctx context.Context, destID, status, sort string, params pagination.Params) ([]models.Review, pagination.Page, error) {
+    order, ok := reviewSortOrders[sort]
+    if !ok {
+        return nil, pagination.Page{}, apperrors.Validation("invalid sort: %s", sort)
+    }
+
+    cond := orm.NewCondition().And("status", status)
+    if destID != "" {
+        cond = cond.And("dest_id", destID)
+    }
+
+    o := orm.NewOrm()
+    qs := o.QueryTable(new(models.Review)).SetCond(cond)
+    if params.Cursor != nil {
+        keyset, err := order.Condition(params.Cursor)
+        if err != nil {
+            return nil, pagination.Page{}, err
+        }
+        qs = qs.SetCond(cond.AndCond(keyset))
+    }
+
+    var reviews []models.Review
+    _, err := qs.OrderBy(order.Fields(params.Backward())...).Limit(params.Limit+1).AllWithCtx(ctx, &reviews)
+    if err != nil {
+        return nil, pagination.Page{}, err
+    }
+
+    reviews, page := pagination.Paginate(reviews, params, func(r models.Review) []string {
+        values := make([]string, len(order))
+        for i, key := range order {
+            switch key.Column {
+            case "id":
+                values[i] = strconv.FormatInt(r.Id, 10)
+            case "created_at":
+                values[i] = r.CreatedAt.Format(time.RFC3339Nano)
+            case "overall":
+                values[i] = strconv.FormatFloat(r.Overall, 'f', -1, 64)
+            }
+        }
+        return values
+    })
+
+    if params.IncludeTotal {
+        total, err := o.QueryTable(new(models.Review)).SetCond(cond).CountWithCtx(ctx)
+        if err != nil {
+            return nil, pagination.Page{}, err
+        }
+        page.TotalCount = &total
+    }
+
+    return reviews, page, nil
+}
+
+// ModerateReview sets a review's moderation status and recomputes the property's aggregate
+func (s *ReviewService) ModerateReview(ctx context.Context, id int64, status string) (_ *models.Review, err error) {
+    ctx, span := tracing.Start(ctx, "ReviewService.ModerateReview", attribute.Int64("review_id", id),
+        attribute.String("status", status))
+    defer tracing.End(span, &err)
+
+    if status != models.ReviewStatusApproved && status != models.ReviewStatusRejected && status != models.ReviewStatusPending {
+        return nil, apperrors.Validation("invalid status: %s", status)
+    }
+
+    o := orm.NewOrm()
+    review := &models.Review{Id: id}
+    if err := o.ReadWithCtx(ctx, review); err != nil {
+        if err == orm.ErrNoRows {
+            return nil, apperrors.NotFound("review %d not found", id)
+        }
+        return nil, err
+    }
+
+    review.Status = status
+    review.ModeratedAt = time.Now()
+    if _, err := o.UpdateWithCtx(ctx, review, "Status", "ModeratedAt"); err != nil {
+        return nil, fmt.Errorf("error updating review %d: %v", id, err)
+    }
+
+    if _, err := s.RecomputeAggregate(ctx, review.DestID); err != nil {
+        return nil, err
+    }
+    return review, nil
+}
+
+// RecomputeAggregate recalculates the average scores over a property's approved reviews
+func (s *ReviewService) RecomputeAggregate(ctx context.Context, destID string) (_ *models.ReviewAggregate, err error) {
+    ctx, span := tracing.Start(ctx, "ReviewService.RecomputeAggregate", attribute.String("dest_id", destID))
+    defer tracing.End(span, &err)
+
+    o := orm.NewOrm()
+
+    var rows []orm.Params
+    _, err = o.RawWithCtx(ctx, `
+        SELECT COUNT(*) AS review_count,
+               COALESCE(AVG(overall), 0) AS average_score,
+               COALESCE(AVG(cleanliness), 0) AS cleanliness,
+               COALESCE(AVG(location), 0) AS location,
+               COALESCE(AVG(value), 0) AS value
+        FROM review
+        WHERE dest_id = ? AND status = ?
+    `, destID, models.ReviewStatusApproved).Values(&rows)
+    if err != nil {
+        return nil, fmt.Errorf("error aggregating reviews for %s: %v", destID, err)
+    }
+
+    aggregate := &models.ReviewAggregate{DestID: destID, UpdatedAt: time.Now()}
+    if len(rows) > 0 {
+        aggregate.ReviewCount = int(paramFloat(rows[0]["review_count"]))
+        aggregate.AverageScore = roundScore(paramFloat(rows[0]["average_score"]))
+        aggregate.Cleanliness = roundScore(paramFloat(rows[0]["cleanliness"]))
+        aggregate.Location = roundScore(paramFloat(rows[0]["location"]))
+        aggregate.Value = roundScore(paramFloat(rows[0]["value"]))
+    }
+
+    // Beego's InsertOrUpdate cannot upsert a string key on Postgres
+    _, err = o.RawWithCtx(ctx, `
+        INSERT INTO review_aggregate (dest_id, review_count, average_score, cleanliness, location, value, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (dest_id) DO UPDATE SET
+            review_count = EXCLUDED.review_count,
+            average_score = EXCLUDED.average_score,
+            cleanliness = EXCLUDED.cleanliness,
+            location = EXCLUDED.location,
+            value = EXCLUDED.value,
+            updated_at = EXCLUDED.updated_at
+    `, aggregate.DestID, aggregate.ReviewCount, aggregate.AverageScore, aggregate.Cleanliness,
+        aggregate.Location, aggregate.Value, aggregate.UpdatedAt).Exec()
+    if err != nil {
+        return nil, fmt.Errorf("error saving review aggregate for %s: %v", destID, err)
+    }
+    return aggregate, nil
+}
+
+// GetAggregate returns a property's review aggregate, or nil when it has no approved reviews
+func (s *ReviewService) GetAggregate(ctx context.Context, destID string) (_ *models.ReviewAggregate, err error) {
+    ctx, span := tracing.Start(ctx, "ReviewService.GetAggregate", attribute.String("dest_id", destID))
+    defer tracing.End(span, &err)
+
+    aggregate := &models.ReviewAggregate{DestID: destID}
+    err = orm.NewOrm().ReadWithCtx(ctx, aggregate)
+    if err == orm.ErrNoRows {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    return aggregate, nil
+}
+
+func roundScore(score float64) float64 {
+    return math.Round(score*10) / 10
+}
+
+// paramFloat converts a raw query value, which the driver returns as a string, to float64
+func paramFloat(value interface{}) float64 {
+    var f float64
+    if value != nil {
+        fmt.Sscan(fmt.Sprint(value), &f)
+    }
+    return f
+}
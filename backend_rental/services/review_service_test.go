@@ -0,0 +1,110 @@
+package services
+
+import (
+    "context"
+    "testing"
+
+    "backend_rental/models"
+    "backend_rental/utils/apperrors"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/codes"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSubmitReviewRejectsScoresOutsideOneToTen(t *testing.T) {
+    cases := []struct {
+        review models.Review
+        want   string
+    }{
+        {models.Review{Cleanliness: 0.9, Location: 5, Value: 5}, "cleanliness must be between 1 and 10"},
+        {models.Review{Cleanliness: 5, Location: 10.1, Value: 5}, "location must be between 1 and 10"},
+        {models.Review{Cleanliness: 5, Location: 5, Value: 0}, "value must be between 1 and 10"},
+        // The first invalid score is reported, in a fixed order
+        {models.Review{Cleanliness: 11, Location: 0, Value: -1}, "cleanliness must be between 1 and 10"},
+    }
+
+    for _, tc := range cases {
+        review := tc.review
+        review.DestID = "1"
+        review.AuthorName = "Guest"
+        err := NewReviewService().SubmitReview(context.Background(), &review)
+        if apperrors.KindOf(err) != apperrors.KindValidation || err.Error() != tc.want {
+            t.Errorf("SubmitReview(%+v) = %v, want validation error %q", tc.review, err, tc.want)
+        }
+    }
+}
+
+func TestSubmitReviewRecordsSpanInRequestTrace(t *testing.T) {
+    recorder := tracetest.NewSpanRecorder()
+    otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+    ctx, request := otel.Tracer("test").Start(context.Background(), "POST /v1/property/reviews")
+    err := NewReviewService().SubmitReview(ctx, &models.Review{DestID: "1", AuthorName: "Guest"})
+    request.End()
+    if apperrors.KindOf(err) != apperrors.KindValidation {
+        t.Fatalf("SubmitReview = %v, want a validation error", err)
+    }
+
+    spans := recorder.Ended()
+    if len(spans) != 2 {
+        t.Fatalf("%d spans, want the request and the service span", len(spans))
+    }
+    span := spans[0]
+    if span.Name() != "ReviewService.SubmitReview" {
+        t.Errorf("span named %q", span.Name())
+    }
+    if span.Parent().SpanID() != request.SpanContext().SpanID() {
+        t.Error("service span is not a child of the request span")
+    }
+    if span.Status().Code != codes.Error {
+        t.Errorf("status %v, want the validation error recorded", span.Status().Code)
+    }
+}
+
+func TestModerationRecomputesAggregate(t *testing.T) {
+    requireDB(t)
+    ctx := context.Background()
+    service := NewReviewService()
+    destID := uniqueID("review")
+
+    low := &models.Review{DestID: destID, AuthorName: "A", Cleanliness: 1, Location: 1, Value: 1}
+    high := &models.Review{DestID: destID, AuthorName: "B", Cleanliness: 10, Location: 10, Value: 10}
+    for _, review := range []*models.Review{low, high} {
+        if err := service.SubmitReview(ctx, review); err != nil {
+            t.Fatalf("SubmitReview at the score bounds: %v", err)
+        }
+        if review.Status != models.ReviewStatusPending {
+            t.Errorf("submitted review is %s, want pending", review.Status)
+        }
+    }
+
+    if aggregate, err := service.GetAggregate(ctx, destID); err != nil || aggregate != nil {
+        t.Fatalf("aggregate before moderation = %+v, %v; want none", aggregate, err)
+    }
+
+    for _, review := range []*models.Review{low, high} {
+        if _, err := service.ModerateReview(ctx, review.Id, models.ReviewStatusApproved); err != nil {
+            t.Fatalf("ModerateReview: %v", err)
+        }
+    }
+    aggregate, err := service.GetAggregate(ctx, destID)
+    if err != nil || aggregate == nil {
+        t.Fatalf("GetAggregate = %+v, %v", aggregate, err)
+    }
+    if aggregate.ReviewCount != 2 || aggregate.AverageScore != 5.5 || aggregate.Cleanliness != 5.5 {
+        t.Errorf("aggregate of two approved reviews = %+v, want 2 reviews averaging 5.5", aggregate)
+    }
+
+    // Rejecting a review updates the stored aggregate in place
+    if _, err := service.ModerateReview(ctx, low.Id, models.ReviewStatusRejected); err != nil {
+        t.Fatalf("ModerateReview: %v", err)
+    }
+    aggregate, err = service.GetAggregate(ctx, destID)
+    if err != nil || aggregate == nil {
+        t.Fatalf("GetAggregate = %+v, %v", aggregate, err)
+    }
+    if aggregate.ReviewCount != 1 || aggregate.AverageScore != 10 {
+        t.Errorf("aggregate after rejecting one = %+v, want 1 review scoring 10", aggregate)
+    }
+}
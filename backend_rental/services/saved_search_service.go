@@ -0,0 +1,198 @@
+// services/saved_search_service.go
+package services
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/url"
+    "strings"
+    "time"
+
+    "backend_rental/models"
+    "backend_rental/utils/apperrors"
+    "github.com/beego/beego/v2/client/orm"
+)
+
+var ErrSavedSearchNotFound = apperrors.NotFound("saved search not found")
+
+// SavedSearchRun is the outcome of re-running a saved search
+type SavedSearchRun struct {
+    Search         *models.SavedSearch `json:"search"`
+    PreviousRunAt  *time.Time          `json:"previous_run_at"`
+    PropertyIDs    []string            `json:"property_ids"`
+    NewPropertyIDs []string            `json:"new_property_ids"`
+    NewProperties  []models.Property   `json:"new_properties"`
+}
+
+type SavedSearchService struct {
+    propertyService PropertyServiceInterface
+}
+
+func NewSavedSearchService(propertyService PropertyServiceInterface) *SavedSearchService {
+    return &SavedSearchService{
+        propertyService: propertyService,
+    }
+}
+
+// ListSavedSearches returns the guest's saved searches, newest first
+func (s *SavedSearchService) ListSavedSearches(ownerID string) ([]models.SavedSearch, error) {
+    var searches []models.SavedSearch
+    _, err := orm.NewOrm().QueryTable(new(models.SavedSearch)).
+        Filter("owner_id", ownerID).
+        OrderBy("-created_at").
+        Limit(-1).
+        All(&searches)
+    return searches, err
+}
+
+// CreateSavedSearch stores the search parameters under a name with the
+// properties they match now, so the first run reports only later matches
+func (s *SavedSearchService) CreateSavedSearch(ctx context.Context, ownerID, name string, params map[string]string) (*models.SavedSearch, error) {
+    name = strings.TrimSpace(name)
+    if name == "" {
+        return nil, apperrors.Validation("name is required")
+    }
+
+    values := url.Values{}
+    for key, value := range params {
+        if value != "" {
+            values.Set(key, value)
+        }
+    }
+    filter, err := models.ParsePropertyFilter(values)
+    if err != nil {
+        return nil, err
+    }
+    if filter.IsEmpty() {
+        return nil, apperrors.Validation("at least one search parameter is required")
+    }
+
+    destIDs, err := s.propertyService.SearchPropertyIDs(ctx, filter)
+    if err != nil {
+        return nil, err
+    }
+    resultIDs, err := encodeResultIDs(destIDs)
+    if err != nil {
+        return nil, err
+    }
+
+    search := &models.SavedSearch{
+        OwnerID:       ownerID,
+        Name:          name,
+        Query:         values.Encode(),
+        LastResultIDs: resultIDs,
+    }
+    if _, err := orm.NewOrm().Insert(search); err != nil {
+        return nil, fmt.Errorf("error saving search: %v", err)
+    }
+    return search, nil
+}
+
+// GetSavedSearch returns one of the guest's saved searches
+func (s *SavedSearchService) GetSavedSearch(ownerID string, id int64) (*models.SavedSearch, error) {
+    search := &models.SavedSearch{}
+    err := orm.NewOrm().QueryTable(new(models.SavedSearch)).
+        Filter("id", id).
+        Filter("owner_id", ownerID).
+        One(search)
+    if err == orm.ErrNoRows {
+        return nil, ErrSavedSearchNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+    return search, nil
+}
+
+// DeleteSavedSearch removes one of the guest's saved searches
+func (s *SavedSearchService) DeleteSavedSearch(ownerID string, id int64) error {
+    search, err := s.GetSavedSearch(ownerID, id)
+    if err != nil {
+        return err
+    }
+    _, err = orm.NewOrm().Delete(search)
+    return err
+}
+
+// RunSavedSearch re-runs a saved search and reports the properties that did
+// not match on the previous run, or when the search was saved
+func (s *SavedSearchService) RunSavedSearch(ctx context.Context, ownerID string, id int64) (*SavedSearchRun, error) {
+    search, err := s.GetSavedSearch(ownerID, id)
+    if err != nil {
+        return nil, err
+    }
+
+    values, err := url.ParseQuery(search.Query)
+    if err != nil {
+        return nil, fmt.Errorf("invalid stored query for saved search %d: %v", id, err)
+    }
+
+    filter, err := models.ParsePropertyFilter(values)
+    if err != nil {
+        return nil, fmt.Errorf("invalid stored query for saved search %d: %v", id, err)
+    }
+
+    destIDs, err := s.propertyService.SearchPropertyIDs(ctx, filter)
+    if err != nil {
+        return nil, err
+    }
+    if destIDs == nil {
+        destIDs = []string{}
+    }
+
+    previous := make(map[string]bool)
+    if search.LastResultIDs != "" {
+        var previousIDs []string
+        if err := json.Unmarshal([]byte(search.LastResultIDs), &previousIDs); err != nil {
+            return nil, fmt.Errorf("invalid stored results for saved search %d: %v", id, err)
+        }
+        for _, destID := range previousIDs {
+            previous[destID] = true
+        }
+    }
+
+    newIDs := []string{}
+    for _, destID := range destIDs {
+        if !previous[destID] {
+            newIDs = append(newIDs, destID)
+        }
+    }
+
+    newProperties, err := s.propertyService.GetPropertiesByDestIDs(ctx, newIDs)
+    if err != nil {
+        return nil, err
+    }
+
+    run := &SavedSearchRun{
+        Search:         search,
+        PropertyIDs:    destIDs,
+        NewPropertyIDs: newIDs,
+        NewProperties:  newProperties,
+    }
+    if !search.LastRunAt.IsZero() {
+        previousRunAt := search.LastRunAt
+        run.PreviousRunAt = &previousRunAt
+    }
+
+    resultIDs, err := encodeResultIDs(destIDs)
+    if err != nil {
+        return nil, err
+    }
+    search.LastResultIDs = resultIDs
+    search.LastRunAt = time.Now()
+    if _, err := orm.NewOrm().Update(search, "LastResultIDs", "LastRunAt"); err != nil {
+        return nil, fmt.Errorf("error recording run of saved search %d: %v", id, err)
+    }
+
+    return run, nil
+}
+
+// encodeResultIDs stores the matches of a run as a JSON array
+func encodeResultIDs(destIDs []string) (string, error) {
+    if destIDs == nil {
+        destIDs = []string{}
+    }
+    encoded, err := json.Marshal(destIDs)
+    return string(encoded), err
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    "backend_rental/models"
+)
+
+// matchingProperties answers searches with a fixed set of dest IDs
+type matchingProperties struct {
+    PropertyServiceInterface
+    destIDs []string
+}
+
+func (m *matchingProperties) SearchPropertyIDs(ctx context.Context, filter models.PropertyFilter) ([]string, error) {
+    return m.destIDs, nil
+}
+
+func (m *matchingProperties) GetPropertiesByDestIDs(ctx context.Context, destIDs []string) ([]models.Property, error) {
+    properties := []models.Property{}
+    for _, destID := range destIDs {
+        properties = append(properties, models.Property{DestID: destID})
+    }
+    return properties, nil
+}
+
+func TestFirstRunReportsOnlyMatchesSinceSaving(t *testing.T) {
+    requireDB(t)
+    properties := &matchingProperties{destIDs: []string{"1", "2"}}
+    service := NewSavedSearchService(properties)
+    guest := uniqueID("guest")
+
+    search, err := service.CreateSavedSearch(context.Background(), guest, "Lisbon", map[string]string{"city_name": "Lisbon"})
+    if err != nil {
+        t.Fatalf("CreateSavedSearch: %v", err)
+    }
+
+    properties.destIDs = []string{"1", "2", "3"}
+    run, err := service.RunSavedSearch(context.Background(), guest, search.Id)
+    if err != nil {
+        t.Fatalf("RunSavedSearch: %v", err)
+    }
+    if got := strings.Join(run.NewPropertyIDs, ","); got != "3" {
+        t.Errorf("new property IDs = %q, want 3", got)
+    }
+    if run.PreviousRunAt != nil {
+        t.Errorf("previous run at %v, want none on the first run", run.PreviousRunAt)
+    }
+}
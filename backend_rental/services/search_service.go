@@ -0,0 +1,358 @@
+// services/search_service.go
+package services
+
+import (
+    "context"
+    "fmt"
+    "strconv"
+    "strings"
+
+    "backend_rental/models"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils"
+    "backend_rental/utils/pagination"
+    "backend_rental/utils/tracing"
+    "github.com/beego/beego/v2/client/orm"
+    beego "github.com/beego/beego/v2/server/web"
+    "go.opentelemetry.io/otel/attribute"
+    "gorm.io/gorm"
+)
+
+const maxSearchQueryLength = 200
+
+// searchOrder ranks the best matches first, breaking ties on the property
+var searchOrder = pagination.Order{{Column: "ranked.rank", Desc: true}, {Column: "ranked.dest_id"}}
+
+// searchConfigs maps base language codes to Postgres text search configurations;
+// other languages are indexed with the "simple" configuration
+var searchConfigs = map[string]string{
+    "da": "danish",
+    "de": "german",
+    "en": "english",
+    "es": "spanish",
+    "fi": "finnish",
+    "fr": "french",
+    "hu": "hungarian",
+    "it": "italian",
+    "nl": "dutch",
+    "no": "norwegian",
+    "pt": "portuguese",
+    "ro": "romanian",
+    "ru": "russian",
+    "sv": "swedish",
+    "tr": "turkish",
+}
+
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=\" … \""
+
+type searchRow struct {
+    DestID  string
+    Rank    float64
+    Snippet string
+}
+
+type SearchService struct {
+    db           *gorm.DB
+    translations *TranslationService
+}
+
+func NewSearchService(db *gorm.DB) *SearchService {
+    return &SearchService{
+        db:           db,
+        translations: NewTranslationService(),
+    }
+}
+
+// SearchConfig returns the Postgres text search configuration for a language code
+func SearchConfig(language string) string {
+    base := strings.SplitN(strings.ToLower(language), "-", 2)[0]
+    if config, ok := searchConfigs[base]; ok {
+        return config
+    }
+    return "simple"
+}
+
+// indexLanguages returns the default language followed by the content languages
+func indexLanguages() []string {
+    languages := []string{beego.AppConfig.DefaultString("defaultlanguage", "en-gb")}
+    for _, language := range contentLanguages() {
+        if language != languages[0] {
+            languages = append(languages, language)
+        }
+    }
+    return languages
+}
+
+// IndexProperty rebuilds the search documents of a property in every indexed language
+func (s *SearchService) IndexProperty(ctx context.Context, destID string) (err error) {
+    ctx, span := tracing.Start(ctx, "SearchService.IndexProperty", attribute.String("dest_id", destID))
+    defer tracing.End(span, &err)
+
+    db := s.db.WithContext(ctx)
+    var property models.Property
+    if err := db.Where("dest_id = ?", destID).First(&property).Error; err != nil {
+        return fmt.Errorf("error loading property %s for indexing: %v", destID, err)
+    }
+
+    description := models.PropertyDescription{DestID: destID}
+    if err := orm.NewOrm().ReadWithCtx(ctx, &description); err != nil && err != orm.ErrNoRows {
+        return fmt.Errorf("error loading description of %s for indexing: %v", destID, err)
+    }
+
+    var amenities []string
+    err = db.Model(&models.PropertyAmenity{}).
+        Where("dest_id = ?", destID).
+        Order("name").
+        Pluck("name", &amenities).Error
+    if err != nil {
+        return fmt.Errorf("error loading amenities of %s for indexing: %v", destID, err)
+    }
+
+    locationID := utils.CityLocationID(property.CityName, property.Country)
+    for _, language := range indexLanguages() {
+        document := models.PropertySearchDocument{
+            DestID:      destID,
+            Language:    language,
+            Config:      SearchConfig(language),
+            Name:        property.Name,
+            Description: description.Description,
+        }
+
+        descriptions, err := s.translations.Lookup(models.TranslationEntityProperty, []string{destID},
+            models.TranslationFieldDescription, []string{language})
+        if err != nil {
+            return err
+        }
+        if text, ok := descriptions[destID]; ok {
+            document.Description = text
+        }
+
+        cityName, country := property.CityName, property.Country
+        cityNames, err := s.translations.Lookup(models.TranslationEntityLocation, []string{locationID},
+            models.TranslationFieldCityName, []string{language})
+        if err != nil {
+            return err
+        }
+        if name, ok := cityNames[locationID]; ok {
+            cityName = name
+        }
+        countries, err := s.translations.Lookup(models.TranslationEntityLocation, []string{locationID},
+            models.TranslationFieldCountry, []string{language})
+        if err != nil {
+            return err
+        }
+        if name, ok := countries[locationID]; ok {
+            country = name
+        }
+        document.Location = strings.TrimSpace(cityName + " " + country)
+
+        translatedAmenities, err := s.translations.Lookup(models.TranslationEntityAmenity, amenities,
+            models.TranslationFieldName, []string{language})
+        if err != nil {
+            return err
+        }
+        names := make([]string, len(amenities))
+        for i, amenity := range amenities {
+            names[i] = amenity
+            if name, ok := translatedAmenities[amenity]; ok {
+                names[i] = name
+            }
+        }
+        document.Amenities = strings.Join(names, ", ")
+
+        if err := s.saveDocument(ctx, &document); err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// RebuildIndex reindexes every stored property and returns how many were indexed
+func (s *SearchService) RebuildIndex(ctx context.Context) (_ int, err error) {
+    ctx, span := tracing.Start(ctx, "SearchService.RebuildIndex")
+    defer tracing.End(span, &err)
+
+    var destIDs []string
+    if err := s.db.WithContext(ctx).Model(&models.Property{}).Order("dest_id").Pluck("dest_id", &destIDs).Error; err != nil {
+        return 0, err
+    }
+
+    for i, destID := range destIDs {
+        if err := s.IndexProperty(ctx, destID); err != nil {
+            return i, err
+        }
+    }
+    return len(destIDs), nil
+}
+
+func (s *SearchService) saveDocument(ctx context.Context, document *models.PropertySearchDocument) error {
+    document.Keywords = strings.Join([]string{document.Name, document.Location, document.Amenities}, " ")
+
+    err := s.db.WithContext(ctx).Exec(`
+        INSERT INTO property_search_documents
+            (dest_id, language, config, name, location, amenities, description, keywords, document, updated_at)
+        VALUES (@dest_id, @language, @config, @name, @location, @amenities, @description, @keywords,
+            setweight(to_tsvector(@config::regconfig, @name), 'A') ||
+            setweight(to_tsvector(@config::regconfig, @location), 'B') ||
+            setweight(to_tsvector(@config::regconfig, @amenities), 'C') ||
+            setweight(to_tsvector(@config::regconfig, @description), 'D'),
+            NOW())
+        ON CONFLICT (dest_id, language) DO UPDATE SET
+            config = EXCLUDED.config,
+            name = EXCLUDED.name,
+            location = EXCLUDED.location,
+            amenities = EXCLUDED.amenities,
+            description = EXCLUDED.description,
+            keywords = EXCLUDED.keywords,
+            document = EXCLUDED.document,
+            updated_at = EXCLUDED.updated_at
+    `, map[string]interface{}{
+        "dest_id":     document.DestID,
+        "language":    document.Language,
+        "config":      document.Config,
+        "name":        document.Name,
+        "location":    document.Location,
+        "amenities":   document.Amenities,
+        "description": document.Description,
+        "keywords":    document.Keywords,
+    }).Error
+    if err != nil {
+        return fmt.Errorf("error indexing %s document for property %s: %v", document.Language, document.DestID, err)
+    }
+    return nil
+}
+
+// Search ranks properties against a free-text query in the first indexed
+// language of the fallback chain. Documents match on the full-text query or,
+// to tolerate typos, on trigram word similarity with the name, location and
+// amenities.
+func (s *SearchService) Search(ctx context.Context, query string, languages []string, params pagination.Params) (_ []models.PropertySearchResult, _ pagination.Page, err error) {
+    ctx, span := tracing.Start(ctx, "SearchService.Search")
+    defer tracing.End(span, &err)
+
+    db := s.db.WithContext(ctx)
+    query = strings.TrimSpace(query)
+    if query == "" {
+        return nil, pagination.Page{}, apperrors.Validation("q is required")
+    }
+    if len(query) > maxSearchQueryLength {
+        return nil, pagination.Page{}, apperrors.Validation("q must be at most %d characters", maxSearchQueryLength)
+    }
+
+    language := searchLanguage(languages)
+    args := map[string]interface{}{
+        "q":        query,
+        "language": language,
+        "config":   SearchConfig(language),
+        "options":  searchHeadlineOptions,
+        "limit":    params.Limit + 1,
+    }
+
+    const matches = `
+        FROM property_search_documents d,
+             websearch_to_tsquery(@config::regconfig, @q) AS query
+        WHERE d.language = @language
+          AND (d.document @@ query OR @q <% d.keywords)`
+
+    keyset := "TRUE"
+    if params.Cursor != nil {
+        where, values, err := searchOrder.Where(params.Cursor)
+        if err != nil {
+            return nil, pagination.Page{}, err
+        }
+        keyset = namedPlaceholders(where, values, args)
+    }
+
+    // Rank every match, but only build snippets for the page being returned
+    var rows []searchRow
+    err = db.Raw(`
+        SELECT ranked.dest_id, ranked.rank,
+               ts_headline(@config::regconfig, COALESCE(NULLIF(d.description, ''), d.name),
+                   websearch_to_tsquery(@config::regconfig, @q), @options) AS snippet
+        FROM (
+            SELECT d.dest_id,
+                   (ts_rank_cd(d.document, query) + word_similarity(@q, d.keywords))::float8 AS rank`+
+        matches+`
+        ) ranked
+        JOIN property_search_documents d ON d.dest_id = ranked.dest_id AND d.language = @language
+        WHERE `+keyset+`
+        ORDER BY `+searchOrder.Clause(params.Backward())+`
+        LIMIT @limit`, args).Scan(&rows).Error
+    if err != nil {
+        return nil, pagination.Page{}, fmt.Errorf("error searching properties: %v", err)
+    }
+
+    rows, page := pagination.Paginate(rows, params, func(row searchRow) []string {
+        return []string{strconv.FormatFloat(row.Rank, 'f', -1, 64), row.DestID}
+    })
+
+    if params.IncludeTotal {
+        var total int64
+        if err := db.Raw("SELECT COUNT(*)"+matches, args).Scan(&total).Error; err != nil {
+            return nil, pagination.Page{}, fmt.Errorf("error counting search results: %v", err)
+        }
+        page.TotalCount = &total
+    }
+
+    destIDs := make([]string, len(rows))
+    for i, row := range rows {
+        destIDs[i] = row.DestID
+    }
+    var properties []models.Property
+    if len(destIDs) > 0 {
+        if err := db.Where("dest_id IN ?", destIDs).Find(&properties).Error; err != nil {
+            return nil, pagination.Page{}, err
+        }
+    }
+    byDestID := make(map[string]*models.Property, len(properties))
+    for i := range properties {
+        byDestID[properties[i].DestID] = &properties[i]
+    }
+
+    results := make([]models.PropertySearchResult, 0, len(rows))
+    for _, row := range rows {
+        property, ok := byDestID[row.DestID]
+        if !ok {
+            continue
+        }
+        results = append(results, models.PropertySearchResult{
+            Property: property,
+            Rank:     row.Rank,
+            Snippet:  row.Snippet,
+        })
+    }
+
+    return results, page, nil
+}
+
+// namedPlaceholders rewrites the ? placeholders of a keyset predicate as named
+// arguments so it can join a query that uses them
+func namedPlaceholders(where string, values []interface{}, args map[string]interface{}) string {
+    var b strings.Builder
+    n := 0
+    for _, r := range where {
+        if r == '?' && n < len(values) {
+            name := fmt.Sprintf("cursor%d", n)
+            args[name] = values[n]
+            b.WriteString("@" + name)
+            n++
+            continue
+        }
+        b.WriteRune(r)
+    }
+    return b.String()
+}
+
+// searchLanguage picks the first language of the fallback chain that is indexed
+func searchLanguage(languages []string) string {
+    indexed := indexLanguages()
+    for _, language := range languages {
+        for _, candidate := range indexed {
+            if language == candidate {
+                return language
+            }
+        }
+    }
+    return indexed[0]
+}
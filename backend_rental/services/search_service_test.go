@@ -0,0 +1,18 @@
+package services
+
+import "testing"
+
+func TestSearchConfig(t *testing.T) {
+    cases := map[string]string{
+        "en-gb": "english",
+        "pt-BR": "portuguese",
+        "de":    "german",
+        "ja":    "simple",
+        "":      "simple",
+    }
+    for language, want := range cases {
+        if got := SearchConfig(language); got != want {
+            t.Errorf("SearchConfig(%q) = %q, want %q", language, got, want)
+        }
+    }
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+    "sync"
+    "time"
+)
+
+// Background tasks reported by the admin status, besides the refresh kinds
+const (
+    TaskLocationCrawl = "location_crawl"
+    TaskLanguageSync  = "language_sync"
+)
+
+// TaskStatus tracks the runs of one background task since startup
+type TaskStatus struct {
+    Runs        int64      `json:"runs"`
+    Failures    int64      `json:"failures"`
+    LastSuccess *time.Time `json:"last_success,omitempty"`
+    LastFailure *time.Time `json:"last_failure,omitempty"`
+    LastError   string     `json:"last_error,omitempty"`
+}
+
+var (
+    tasksMu sync.Mutex
+    tasks   = map[string]*TaskStatus{}
+)
+
+// RecordTask records a finished run of a background task; err is nil when it
+// succeeded
+func RecordTask(task string, err error) {
+    now := time.Now()
+    tasksMu.Lock()
+    defer tasksMu.Unlock()
+    status, ok := tasks[task]
+    if !ok {
+        status = &TaskStatus{}
+        tasks[task] = status
+    }
+    status.Runs++
+    if err != nil {
+        status.Failures++
+        status.LastFailure = &now
+        status.LastError = err.Error()
+        return
+    }
+    status.LastSuccess = &now
+}
+
+// TaskStatuses returns the status of every task that has run since startup
+func TaskStatuses() map[string]TaskStatus {
+    tasksMu.Lock()
+    defer tasksMu.Unlock()
+    result := make(map[string]TaskStatus, len(tasks))
+    for task, status := range tasks {
+        result[task] = *status
+    }
+    return result
+}
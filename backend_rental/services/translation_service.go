@@ -0,0 +1,121 @@
+// services/translation_service.go
+package services
+
+import (
+    "fmt"
+    "time"
+
+    "backend_rental/models"
+    "github.com/beego/beego/v2/client/orm"
+)
+
+type TranslationService struct{}
+
+func NewTranslationService() *TranslationService {
+    return &TranslationService{}
+}
+
+// Save stores or replaces one translated field
+func (s *TranslationService) Save(entity, entityID, field, language, value string) error {
+    o := orm.NewOrm()
+    _, err := o.Raw(`
+        INSERT INTO translation (entity, entity_id, field, language, value, fetched_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+        ON CONFLICT (entity, entity_id, field, language) DO UPDATE SET
+            value = EXCLUDED.value,
+            fetched_at = EXCLUDED.fetched_at
+    `, entity, entityID, field, language, value, time.Now()).Exec()
+    if err != nil {
+        return fmt.Errorf("error saving %s translation for %s %s: %v", language, entity, entityID, err)
+    }
+    return nil
+}
+
+// Delete removes the translations of one field in every language
+func (s *TranslationService) Delete(entity, entityID, field string) error {
+    _, err := orm.NewOrm().QueryTable(new(models.Translation)).
+        Filter("entity", entity).
+        Filter("entity_id", entityID).
+        Filter("field", field).
+        Delete()
+    if err != nil {
+        return fmt.Errorf("error deleting %s translations for %s %s: %v", field, entity, entityID, err)
+    }
+    return nil
+}
+
+// Values returns the stored values of one field keyed by language code
+func (s *TranslationService) Values(entity, entityID, field string, languages []string) (map[string]string, error) {
+    values := make(map[string]string)
+    if len(languages) == 0 {
+        return values, nil
+    }
+
+    var translations []models.Translation
+    _, err := orm.NewOrm().QueryTable(new(models.Translation)).
+        Filter("entity", entity).
+        Filter("entity_id", entityID).
+        Filter("field", field).
+        Filter("language__in", languages).
+        All(&translations)
+    if err != nil {
+        return nil, err
+    }
+
+    for _, t := range translations {
+        values[t.Language] = t.Value
+    }
+    return values, nil
+}
+
+// Lookup returns, per entity ID, the field value in the first language of
+// the fallback chain that has a translation
+func (s *TranslationService) Lookup(entity string, entityIDs []string, field string, languages []string) (map[string]string, error) {
+    translations, err := s.LookupTranslations(entity, entityIDs, field, languages)
+    if err != nil {
+        return nil, err
+    }
+
+    result := make(map[string]string, len(translations))
+    for entityID, t := range translations {
+        result[entityID] = t.Value
+    }
+    return result, nil
+}
+
+// LookupTranslations is Lookup returning the chosen translations, so callers
+// can see which language of the chain each one is in
+func (s *TranslationService) LookupTranslations(entity string, entityIDs []string, field string, languages []string) (map[string]models.Translation, error) {
+    result := make(map[string]models.Translation)
+    if len(entityIDs) == 0 || len(languages) == 0 {
+        return result, nil
+    }
+
+    var translations []models.Translation
+    _, err := orm.NewOrm().QueryTable(new(models.Translation)).
+        Filter("entity", entity).
+        Filter("entity_id__in", entityIDs).
+        Filter("field", field).
+        Filter("language__in", languages).
+        Limit(-1).
+        All(&translations)
+    if err != nil {
+        return nil, err
+    }
+
+    rank := make(map[string]int, len(languages))
+    for i, language := range languages {
+        rank[language] = i
+    }
+
+    best := make(map[string]int)
+    for _, t := range translations {
+        if current, ok := best[t.EntityID]; ok && current <= rank[t.Language] {
+            continue
+        }
+        best[t.EntityID] = rank[t.Language]
+        result[t.EntityID] = t
+    }
+
+    return result, nil
+}
@@ -0,0 +1,223 @@
+// services/wishlist_service.go
+package services
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+    "strings"
+
+    "backend_rental/models"
+    "backend_rental/utils/apperrors"
+    "github.com/beego/beego/v2/client/orm"
+)
+
+var ErrWishlistNotFound = apperrors.NotFound("wishlist not found")
+
+type WishlistService struct {
+    propertyService PropertyServiceInterface
+}
+
+func NewWishlistService(propertyService PropertyServiceInterface) *WishlistService {
+    return &WishlistService{
+        propertyService: propertyService,
+    }
+}
+
+// ListWishlists returns the guest's wishlists, most recently updated first
+func (s *WishlistService) ListWishlists(ownerID string) ([]models.Wishlist, error) {
+    var wishlists []models.Wishlist
+    _, err := orm.NewOrm().QueryTable(new(models.Wishlist)).
+        Filter("owner_id", ownerID).
+        OrderBy("-updated_at").
+        Limit(-1).
+        All(&wishlists)
+    return wishlists, err
+}
+
+// CreateWishlist creates an empty wishlist for the guest
+func (s *WishlistService) CreateWishlist(ownerID, name string) (*models.Wishlist, error) {
+    name = strings.TrimSpace(name)
+    if name == "" {
+        return nil, apperrors.Validation("name is required")
+    }
+
+    wishlist := &models.Wishlist{OwnerID: ownerID, Name: name}
+    if _, err := orm.NewOrm().Insert(wishlist); err != nil {
+        return nil, fmt.Errorf("error creating wishlist: %v", err)
+    }
+    return wishlist, nil
+}
+
+// GetWishlist returns one of the guest's wishlists
+func (s *WishlistService) GetWishlist(ownerID string, id int64) (*models.Wishlist, error) {
+    wishlist := &models.Wishlist{}
+    err := orm.NewOrm().QueryTable(new(models.Wishlist)).
+        Filter("id", id).
+        Filter("owner_id", ownerID).
+        One(wishlist)
+    if err == orm.ErrNoRows {
+        return nil, ErrWishlistNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+    return wishlist, nil
+}
+
+// GetSharedWishlist returns the wishlist published under a share token
+func (s *WishlistService) GetSharedWishlist(token string) (*models.Wishlist, error) {
+    if token == "" {
+        return nil, ErrWishlistNotFound
+    }
+
+    wishlist := &models.Wishlist{}
+    err := orm.NewOrm().QueryTable(new(models.Wishlist)).Filter("share_token", token).One(wishlist)
+    if err == orm.ErrNoRows {
+        return nil, ErrWishlistNotFound
+    }
+    if err != nil {
+        return nil, err
+    }
+    return wishlist, nil
+}
+
+// RenameWishlist changes the name of one of the guest's wishlists
+func (s *WishlistService) RenameWishlist(ownerID string, id int64, name string) (*models.Wishlist, error) {
+    name = strings.TrimSpace(name)
+    if name == "" {
+        return nil, apperrors.Validation("name is required")
+    }
+
+    wishlist, err := s.GetWishlist(ownerID, id)
+    if err != nil {
+        return nil, err
+    }
+
+    wishlist.Name = name
+    if _, err := orm.NewOrm().Update(wishlist, "Name", "UpdatedAt"); err != nil {
+        return nil, fmt.Errorf("error renaming wishlist %d: %v", id, err)
+    }
+    return wishlist, nil
+}
+
+// DeleteWishlist removes one of the guest's wishlists with its items
+func (s *WishlistService) DeleteWishlist(ownerID string, id int64) error {
+    wishlist, err := s.GetWishlist(ownerID, id)
+    if err != nil {
+        return err
+    }
+
+    o := orm.NewOrm()
+    if _, err := o.QueryTable(new(models.WishlistItem)).Filter("wishlist_id", wishlist.Id).Delete(); err != nil {
+        return fmt.Errorf("error deleting items of wishlist %d: %v", id, err)
+    }
+    if _, err := o.Delete(wishlist); err != nil {
+        return fmt.Errorf("error deleting wishlist %d: %v", id, err)
+    }
+    return nil
+}
+
+// ListItems returns a wishlist's items with their stored property details
+func (s *WishlistService) ListItems(ctx context.Context, wishlist *models.Wishlist) ([]models.WishlistItem, error) {
+    var items []models.WishlistItem
+    _, err := orm.NewOrm().QueryTable(new(models.WishlistItem)).
+        Filter("wishlist_id", wishlist.Id).
+        OrderBy("-added_at").
+        Limit(-1).
+        AllWithCtx(ctx, &items)
+    if err != nil {
+        return nil, err
+    }
+
+    destIDs := make([]string, len(items))
+    for i, item := range items {
+        destIDs[i] = item.DestID
+    }
+    properties, err := s.propertyService.GetPropertiesByDestIDs(ctx, destIDs)
+    if err != nil {
+        return nil, err
+    }
+
+    byDestID := make(map[string]*models.Property, len(properties))
+    for i := range properties {
+        byDestID[properties[i].DestID] = &properties[i]
+    }
+    for i := range items {
+        items[i].Property = byDestID[items[i].DestID]
+    }
+
+    return items, nil
+}
+
+// AddItem saves a property to one of the guest's wishlists; adding it twice is a no-op
+func (s *WishlistService) AddItem(ownerID string, id int64, destID string) error {
+    destID = strings.TrimSpace(destID)
+    if destID == "" {
+        return apperrors.Validation("dest_id is required")
+    }
+
+    wishlist, err := s.GetWishlist(ownerID, id)
+    if err != nil {
+        return err
+    }
+
+    o := orm.NewOrm()
+    _, err = o.Raw(`
+        INSERT INTO wishlist_item (wishlist_id, dest_id, added_at)
+        VALUES (?, ?, NOW())
+        ON CONFLICT (wishlist_id, dest_id) DO NOTHING
+    `, wishlist.Id, destID).Exec()
+    if err != nil {
+        return fmt.Errorf("error adding %s to wishlist %d: %v", destID, id, err)
+    }
+
+    return s.touch(wishlist)
+}
+
+// RemoveItem removes a property from one of the guest's wishlists
+func (s *WishlistService) RemoveItem(ownerID string, id int64, destID string) error {
+    wishlist, err := s.GetWishlist(ownerID, id)
+    if err != nil {
+        return err
+    }
+
+    _, err = orm.NewOrm().QueryTable(new(models.WishlistItem)).
+        Filter("wishlist_id", wishlist.Id).
+        Filter("dest_id", destID).
+        Delete()
+    if err != nil {
+        return fmt.Errorf("error removing %s from wishlist %d: %v", destID, id, err)
+    }
+
+    return s.touch(wishlist)
+}
+
+// Share returns the wishlist's share token, generating one on first use
+func (s *WishlistService) Share(ownerID string, id int64) (*models.Wishlist, error) {
+    wishlist, err := s.GetWishlist(ownerID, id)
+    if err != nil {
+        return nil, err
+    }
+    if wishlist.ShareToken != nil && *wishlist.ShareToken != "" {
+        return wishlist, nil
+    }
+
+    token := make([]byte, 16)
+    if _, err := rand.Read(token); err != nil {
+        return nil, fmt.Errorf("error generating share token: %v", err)
+    }
+
+    shareToken := hex.EncodeToString(token)
+    wishlist.ShareToken = &shareToken
+    if _, err := orm.NewOrm().Update(wishlist, "ShareToken", "UpdatedAt"); err != nil {
+        return nil, fmt.Errorf("error sharing wishlist %d: %v", id, err)
+    }
+    return wishlist, nil
+}
+
+func (s *WishlistService) touch(wishlist *models.Wishlist) error {
+    _, err := orm.NewOrm().Update(wishlist, "UpdatedAt")
+    return err
+}
@@ -0,0 +1,45 @@
+package services
+
+import "testing"
+
+func TestShareOneOfSeveralWishlists(t *testing.T) {
+    requireDB(t)
+    service := NewWishlistService(nil)
+    guest := uniqueID("guest")
+
+    first, err := service.CreateWishlist(guest, "Summer")
+    if err != nil {
+        t.Fatalf("CreateWishlist: %v", err)
+    }
+    second, err := service.CreateWishlist(guest, "Winter")
+    if err != nil {
+        t.Fatalf("second unshared wishlist: %v", err)
+    }
+
+    shared, err := service.Share(guest, first.Id)
+    if err != nil {
+        t.Fatalf("Share: %v", err)
+    }
+    if shared.ShareToken == nil || *shared.ShareToken == "" {
+        t.Fatal("shared wishlist has no token")
+    }
+    again, err := service.Share(guest, first.Id)
+    if err != nil || *again.ShareToken != *shared.ShareToken {
+        t.Errorf("sharing again changed the token or failed: %v", err)
+    }
+
+    found, err := service.GetSharedWishlist(*shared.ShareToken)
+    if err != nil || found.Id != first.Id {
+        t.Errorf("GetSharedWishlist = %+v, %v; want wishlist %d", found, err, first.Id)
+    }
+    unshared, err := service.GetWishlist(guest, second.Id)
+    if err != nil {
+        t.Fatalf("GetWishlist: %v", err)
+    }
+    if unshared.ShareToken != nil {
+        t.Errorf("unshared wishlist has token %q, want NULL", *unshared.ShareToken)
+    }
+    if _, err := service.CreateWishlist(guest, "Spring"); err != nil {
+        t.Errorf("third wishlist: %v", err)
+    }
+}
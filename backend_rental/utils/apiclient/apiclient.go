@@ -4,16 +4,25 @@ import (
     "context"
     "fmt"
     "io"
+    "log/slog"
     "net/http"
-    "log"
+    neturl "net/url"
     "time"
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/metrics"
     "backend_rental/utils/ratelimiter"
+    "backend_rental/utils/tracing"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/trace"
 )
 
 type APIClient struct {
     client      *http.Client
     rateLimit   *ratelimiter.APIRateLimiter
     rapidAPIKey string
+    // cache serves GET responses; nil sends every request upstream
+    cache *Cache
 }
 
 func NewAPIClient(rapidAPIKey string) *APIClient {
@@ -23,18 +32,28 @@ func NewAPIClient(rapidAPIKey string) *APIClient {
         },
         rateLimit:   ratelimiter.GetInstance(),
         rapidAPIKey: rapidAPIKey,
+        cache:       DefaultCache(),
     }
 }
 
+// MakeRequest returns the response to an upstream GET, served from the cache
+// while it is fresh
 func (c *APIClient) MakeRequest(ctx context.Context, url string) ([]byte, error) {
-    if err := c.rateLimit.Wait(ctx); err != nil {
-        return nil, fmt.Errorf("rate limit error: %v", err)
+    if c.cache == nil {
+        return c.get(ctx, url)
     }
+    return c.cache.Get(ctx, url, func(ctx context.Context) ([]byte, error) {
+        return c.get(ctx, url)
+    })
+}
 
-    start := time.Now()
-    defer func() {
-        log.Printf("Request to %s took %v", url, time.Since(start))
-    }()
+func (c *APIClient) get(ctx context.Context, url string) (body []byte, err error) {
+    ctx, span := startUpstreamSpan(ctx, "GET", url)
+    defer tracing.End(span, &err)
+
+    if err := c.rateLimit.Wait(ctx); err != nil {
+        return nil, apperrors.RateLimited(err, "gave up waiting for an upstream request slot")
+    }
 
     req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
     if err != nil {
@@ -44,26 +63,28 @@ func (c *APIClient) MakeRequest(ctx context.Context, url string) ([]byte, error)
     req.Header.Add("x-rapidapi-host", "booking-com18.p.rapidapi.com")
     req.Header.Add("x-rapidapi-key", c.rapidAPIKey)
 
-    resp, err := c.client.Do(req)
+    resp, err := c.do(req)
     if err != nil {
-        return nil, fmt.Errorf("error sending request: %v", err)
+        return nil, apperrors.UpstreamUnavailable(err, "upstream request failed")
     }
     defer resp.Body.Close()
 
-    body, err := io.ReadAll(resp.Body)
+    body, err = io.ReadAll(resp.Body)
     if err != nil {
-        return nil, fmt.Errorf("error reading response body: %v", err)
+        return nil, apperrors.UpstreamUnavailable(err, "upstream response could not be read")
     }
 
-    if resp.StatusCode != http.StatusOK {
-        return nil, fmt.Errorf("API request failed with status code: %d, body: %s",
-            resp.StatusCode, string(body))
+    if err := statusError(resp.StatusCode, body); err != nil {
+        return nil, err
     }
 
     return body, nil
 }
 
-func (c *APIClient) MakePostRequest(ctx context.Context, url string, body io.Reader) ([]byte, error) {
+func (c *APIClient) MakePostRequest(ctx context.Context, url string, body io.Reader) (_ []byte, err error) {
+    ctx, span := startUpstreamSpan(ctx, "POST", url)
+    defer tracing.End(span, &err)
+
     req, err := http.NewRequestWithContext(ctx, "POST", url, body)
     if err != nil {
         return nil, fmt.Errorf("error creating POST request: %v", err)
@@ -74,28 +95,63 @@ func (c *APIClient) MakePostRequest(ctx context.Context, url string, body io.Rea
     req.Header.Add("Content-Type", "application/json")
 
     if err := c.rateLimit.Wait(ctx); err != nil {
-        return nil, fmt.Errorf("rate limit error: %v", err)
+        return nil, apperrors.RateLimited(err, "gave up waiting for an upstream request slot")
     }
 
-    resp, err := c.client.Do(req)
+    resp, err := c.do(req)
     if err != nil {
-        return nil, fmt.Errorf("error sending POST request: %v", err)
+        return nil, apperrors.UpstreamUnavailable(err, "upstream request failed")
     }
     defer resp.Body.Close()
 
     responseBody, err := io.ReadAll(resp.Body)
     if err != nil {
-        return nil, fmt.Errorf("error reading response body: %v", err)
+        return nil, apperrors.UpstreamUnavailable(err, "upstream response could not be read")
     }
 
-    if resp.StatusCode != http.StatusOK {
-        return nil, fmt.Errorf("API POST request failed with status code: %d, body: %s",
-            resp.StatusCode, string(responseBody))
+    if err := statusError(resp.StatusCode, responseBody); err != nil {
+        return nil, err
     }
 
     return responseBody, nil
 }
 
+// do sends a request upstream, recording its status, latency and the quota
+// headers of the response by endpoint path
+func (c *APIClient) do(req *http.Request) (*http.Response, error) {
+    start := time.Now()
+    resp, err := c.client.Do(req)
+    elapsed := time.Since(start)
+
+    status := 0
+    var header http.Header
+    if err == nil {
+        status = resp.StatusCode
+        header = resp.Header
+    }
+    metrics.ObserveUpstream(req.URL.Path, status, elapsed)
+    recordStatus(req.URL.Path, status, header)
+    trace.SpanFromContext(req.Context()).SetAttributes(attribute.Int("http.response.status_code", status))
+    slog.DebugContext(req.Context(), "upstream request",
+        "method", req.Method, "endpoint", req.URL.Path, "status", status, "duration_ms", elapsed.Milliseconds())
+    return resp, err
+}
+
+// startUpstreamSpan starts a client span around an upstream call, covering
+// its rate limiter wait. Trace headers are not sent upstream.
+func startUpstreamSpan(ctx context.Context, method, url string) (context.Context, trace.Span) {
+    endpoint := url
+    if u, err := neturl.Parse(url); err == nil {
+        endpoint = u.Path
+    }
+    return otel.Tracer(tracing.ServiceName).Start(ctx, "upstream "+method+" "+endpoint,
+        trace.WithSpanKind(trace.SpanKindClient),
+        trace.WithAttributes(
+            attribute.String("http.request.method", method),
+            attribute.String("upstream.endpoint", endpoint),
+        ))
+}
+
 func (c *APIClient) MakeRequestWithRetry(ctx context.Context, url string) ([]byte, error) {
     var lastErr error
     for retries := 0; retries < 3; retries++ {
@@ -106,7 +162,20 @@ func (c *APIClient) MakeRequestWithRetry(ctx context.Context, url string) ([]byt
         lastErr = err
         time.Sleep(time.Second * time.Duration(retries+1))
     }
-    return nil, fmt.Errorf("all retries failed: %v", lastErr)
+    return nil, fmt.Errorf("all retries failed: %w", lastErr)
+}
+
+// statusError classifies a non-200 upstream response
+func statusError(statusCode int, body []byte) error {
+    if statusCode == http.StatusOK {
+        return nil
+    }
+
+    cause := fmt.Errorf("status code: %d, body: %s", statusCode, string(body))
+    if statusCode == http.StatusTooManyRequests {
+        return apperrors.RateLimited(cause, "upstream rate limit exceeded")
+    }
+    return apperrors.UpstreamUnavailable(cause, "upstream request failed with status %d", statusCode)
 }
 // // utils/apiclient/apiclient.go
 // package apiclient
@@ -0,0 +1,342 @@
+package apiclient
+
+import (
+    "container/list"
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "log/slog"
+    "net/url"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+
+    "backend_rental/models"
+    "backend_rental/utils/metrics"
+    "github.com/beego/beego/v2/client/orm"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+// Policy sets how long the responses of an endpoint are served from the cache
+type Policy struct {
+    // TTL is how long a response is served as is
+    TTL time.Duration
+    // Stale is how long after the TTL a response is still served while a
+    // background request refreshes it
+    Stale time.Duration
+}
+
+// endpointPolicies are keyed by upstream path. Photos and descriptions rarely
+// change; stay detail carries prices and review scores.
+var endpointPolicies = map[string]Policy{
+    "/stays/auto-complete":   {TTL: 24 * time.Hour, Stale: 7 * 24 * time.Hour},
+    "/stays/detail":          {TTL: 6 * time.Hour, Stale: 24 * time.Hour},
+    "/stays/get-photos":      {TTL: 7 * 24 * time.Hour, Stale: 30 * 24 * time.Hour},
+    "/stays/get-description": {TTL: 7 * 24 * time.Hour, Stale: 30 * 24 * time.Hour},
+}
+
+var defaultPolicy = Policy{TTL: time.Hour}
+
+// Store is the persistent tier of the cache
+type Store interface {
+    // Get returns nil without an error when the key is not stored
+    Get(key string) (*models.UpstreamResponse, error)
+    Put(entry *models.UpstreamResponse) error
+}
+
+// CacheStats counts the lookups of one endpoint
+type CacheStats struct {
+    MemoryHits   int64 `json:"memory_hits"`
+    PostgresHits int64 `json:"postgres_hits"`
+    // StaleHits were served while a refresh ran in the background
+    StaleHits int64 `json:"stale_hits"`
+    Misses    int64 `json:"misses"`
+    // Errors counts failed upstream requests made on a miss or refresh
+    Errors int64 `json:"errors"`
+}
+
+// Cache is a read-through cache of upstream GET responses with an in-memory
+// LRU tier in front of a persistent tier. Concurrent lookups of a missing
+// key share one upstream request.
+type Cache struct {
+    store Store
+    now   func() time.Time
+
+    mu       sync.Mutex
+    size     int
+    entries  map[string]*list.Element
+    order    *list.List
+    inflight map[string]*flight
+    stats    map[string]*CacheStats
+}
+
+type flight struct {
+    done  chan struct{}
+    entry *models.UpstreamResponse
+    err   error
+}
+
+// NewCache creates a cache holding up to size responses in memory; store may be nil
+func NewCache(size int, store Store) *Cache {
+    return &Cache{
+        store:    store,
+        now:      time.Now,
+        size:     size,
+        entries:  map[string]*list.Element{},
+        order:    list.New(),
+        inflight: map[string]*flight{},
+        stats:    map[string]*CacheStats{},
+    }
+}
+
+var (
+    defaultCache     *Cache
+    defaultCacheOnce sync.Once
+)
+
+// DefaultCache returns the shared cache backed by the upstream_response table;
+// upstreamcachesize sets its in-memory capacity
+func DefaultCache() *Cache {
+    defaultCacheOnce.Do(func() {
+        defaultCache = NewCache(beego.AppConfig.DefaultInt("upstreamcachesize", 1000), postgresStore{})
+    })
+    return defaultCache
+}
+
+type refreshKey struct{}
+
+// WithRefresh marks lookups made with ctx to skip both tiers and fetch from
+// upstream, storing the fresh response as usual
+func WithRefresh(ctx context.Context) context.Context {
+    return context.WithValue(ctx, refreshKey{}, true)
+}
+
+func forcesRefresh(ctx context.Context) bool {
+    refresh, _ := ctx.Value(refreshKey{}).(bool)
+    return refresh
+}
+
+// Get returns the response for rawURL, calling fetch when it is not cached,
+// has expired past its stale window or ctx was made by WithRefresh
+func (c *Cache) Get(ctx context.Context, rawURL string, fetch func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+    normalized, endpoint := NormalizeURL(rawURL)
+    key := cacheKey(normalized)
+    policy := policyFor(endpoint)
+    now := c.now()
+
+    c.mu.Lock()
+    stats := c.statsFor(endpoint)
+    entry := c.lookup(key)
+    c.mu.Unlock()
+
+    if forcesRefresh(ctx) {
+        entry = nil
+    } else if entry != nil {
+        if now.Before(entry.ExpiresAt) {
+            c.count(endpoint, "memory_hit", func() { stats.MemoryHits++ })
+            return []byte(entry.Body), nil
+        }
+    } else if entry = c.load(key); entry != nil {
+        c.mu.Lock()
+        c.remember(entry)
+        c.mu.Unlock()
+        if now.Before(entry.ExpiresAt) {
+            c.count(endpoint, "postgres_hit", func() { stats.PostgresHits++ })
+            return []byte(entry.Body), nil
+        }
+    }
+
+    if entry != nil && now.Before(entry.StaleUntil) {
+        c.count(endpoint, "stale_hit", func() { stats.StaleHits++ })
+        go c.refresh(context.Background(), key, normalized, endpoint, policy, fetch)
+        return []byte(entry.Body), nil
+    }
+
+    c.count(endpoint, "miss", func() { stats.Misses++ })
+    fresh, err := c.refresh(ctx, key, normalized, endpoint, policy, fetch)
+    if err != nil {
+        return nil, err
+    }
+    return []byte(fresh.Body), nil
+}
+
+// Stats returns the lookup counts per endpoint
+func (c *Cache) Stats() map[string]CacheStats {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    stats := make(map[string]CacheStats, len(c.stats))
+    for endpoint, s := range c.stats {
+        stats[endpoint] = *s
+    }
+    return stats
+}
+
+// refresh fetches a response and stores it in both tiers; concurrent refreshes
+// of a key wait for the first
+func (c *Cache) refresh(ctx context.Context, key, normalized, endpoint string, policy Policy,
+    fetch func(ctx context.Context) ([]byte, error)) (*models.UpstreamResponse, error) {
+    c.mu.Lock()
+    if f, ok := c.inflight[key]; ok {
+        c.mu.Unlock()
+        select {
+        case <-f.done:
+            return f.entry, f.err
+        case <-ctx.Done():
+            return nil, ctx.Err()
+        }
+    }
+    f := &flight{done: make(chan struct{})}
+    c.inflight[key] = f
+    c.mu.Unlock()
+
+    body, err := fetch(ctx)
+    if err == nil {
+        fetchedAt := c.now()
+        f.entry = &models.UpstreamResponse{
+            Key:        key,
+            URL:        normalized,
+            Endpoint:   endpoint,
+            Body:       string(body),
+            FetchedAt:  fetchedAt,
+            ExpiresAt:  fetchedAt.Add(policy.TTL),
+            StaleUntil: fetchedAt.Add(policy.TTL + policy.Stale),
+        }
+        if c.store != nil {
+            if err := c.store.Put(f.entry); err != nil {
+                slog.Error("error storing cached upstream response", "url", normalized, "error", err)
+            }
+        }
+    }
+    f.err = err
+
+    c.mu.Lock()
+    if err == nil {
+        c.remember(f.entry)
+    } else {
+        c.statsFor(endpoint).Errors++
+    }
+    delete(c.inflight, key)
+    c.mu.Unlock()
+    close(f.done)
+
+    return f.entry, f.err
+}
+
+// load reads an entry from the persistent tier; errors are logged and treated as a miss
+func (c *Cache) load(key string) *models.UpstreamResponse {
+    if c.store == nil {
+        return nil
+    }
+    entry, err := c.store.Get(key)
+    if err != nil {
+        slog.Error("error reading cached upstream response", "key", key, "error", err)
+        return nil
+    }
+    return entry
+}
+
+// count records a lookup result in the endpoint's stats and in the
+// rental_upstream_cache_lookups_total metric
+func (c *Cache) count(endpoint, result string, increment func()) {
+    c.mu.Lock()
+    increment()
+    c.mu.Unlock()
+    metrics.UpstreamCacheLookups.WithLabelValues(endpoint, result).Inc()
+}
+
+func (c *Cache) statsFor(endpoint string) *CacheStats {
+    stats, ok := c.stats[endpoint]
+    if !ok {
+        stats = &CacheStats{}
+        c.stats[endpoint] = stats
+    }
+    return stats
+}
+
+// lookup returns a memory entry and marks it recently used; c.mu must be held
+func (c *Cache) lookup(key string) *models.UpstreamResponse {
+    element, ok := c.entries[key]
+    if !ok {
+        return nil
+    }
+    c.order.MoveToFront(element)
+    return element.Value.(*models.UpstreamResponse)
+}
+
+// remember stores a memory entry, evicting the least recently used beyond
+// the capacity; c.mu must be held
+func (c *Cache) remember(entry *models.UpstreamResponse) {
+    if element, ok := c.entries[entry.Key]; ok {
+        element.Value = entry
+        c.order.MoveToFront(element)
+        return
+    }
+    c.entries[entry.Key] = c.order.PushFront(entry)
+    for c.order.Len() > c.size {
+        oldest := c.order.Back()
+        c.order.Remove(oldest)
+        delete(c.entries, oldest.Value.(*models.UpstreamResponse).Key)
+    }
+}
+
+// NormalizeURL returns rawURL with a lower-case host and sorted query
+// parameters, so equivalent requests share a cache entry, and its path
+func NormalizeURL(rawURL string) (normalized, path string) {
+    u, err := url.Parse(rawURL)
+    if err != nil {
+        return rawURL, ""
+    }
+    query := u.Query()
+    for _, values := range query {
+        sort.Strings(values)
+    }
+    u.Scheme = strings.ToLower(u.Scheme)
+    u.Host = strings.ToLower(u.Host)
+    u.RawQuery = query.Encode()
+    u.Fragment = ""
+    return u.String(), u.Path
+}
+
+func cacheKey(normalized string) string {
+    sum := sha256.Sum256([]byte(normalized))
+    return hex.EncodeToString(sum[:])
+}
+
+func policyFor(endpoint string) Policy {
+    if policy, ok := endpointPolicies[endpoint]; ok {
+        return policy
+    }
+    return defaultPolicy
+}
+
+// postgresStore keeps cached responses in the upstream_response table
+type postgresStore struct{}
+
+func (postgresStore) Get(key string) (*models.UpstreamResponse, error) {
+    entry := &models.UpstreamResponse{Key: key}
+    if err := orm.NewOrm().Read(entry); err != nil {
+        if err == orm.ErrNoRows {
+            return nil, nil
+        }
+        return nil, err
+    }
+    return entry, nil
+}
+
+// Put upserts by hand: Beego's InsertOrUpdate asks the Postgres driver for a
+// last insert ID, which it cannot give for a string key, and fails every time
+func (postgresStore) Put(entry *models.UpstreamResponse) error {
+    _, err := orm.NewOrm().Raw(`
+        INSERT INTO upstream_response (key, url, endpoint, body, fetched_at, expires_at, stale_until)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (key) DO UPDATE SET
+            url = EXCLUDED.url,
+            endpoint = EXCLUDED.endpoint,
+            body = EXCLUDED.body,
+            fetched_at = EXCLUDED.fetched_at,
+            expires_at = EXCLUDED.expires_at,
+            stale_until = EXCLUDED.stale_until
+    `, entry.Key, entry.URL, entry.Endpoint, entry.Body, entry.FetchedAt, entry.ExpiresAt, entry.StaleUntil).Exec()
+    return err
+}
@@ -0,0 +1,155 @@
+package apiclient
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "backend_rental/models"
+    "backend_rental/utils/metrics"
+    "github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type memoryStore struct {
+    mu      sync.Mutex
+    entries map[string]models.UpstreamResponse
+}
+
+func (s *memoryStore) Get(key string) (*models.UpstreamResponse, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    entry, ok := s.entries[key]
+    if !ok {
+        return nil, nil
+    }
+    return &entry, nil
+}
+
+func (s *memoryStore) Put(entry *models.UpstreamResponse) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.entries[entry.Key] = *entry
+    return nil
+}
+
+func TestNormalizeURL(t *testing.T) {
+    a, path := NormalizeURL("https://Booking-com18.p.rapidapi.com/stays/detail?units=metric&hotelId=1#x")
+    b, _ := NormalizeURL("https://booking-com18.p.rapidapi.com/stays/detail?hotelId=1&units=metric")
+    if a != b || path != "/stays/detail" {
+        t.Errorf("normalized %q and %q, path %q", a, b, path)
+    }
+}
+
+func TestCacheTiersAndStaleWhileRevalidate(t *testing.T) {
+    store := &memoryStore{entries: map[string]models.UpstreamResponse{}}
+    cache := NewCache(1, store)
+    lookups := metrics.UpstreamCacheLookups.WithLabelValues("/stays/get-photos", "memory_hit")
+    memoryHitsBefore := testutil.ToFloat64(lookups)
+    now := time.Now()
+    cache.now = func() time.Time { return now }
+
+    var calls atomic.Int32
+    fetch := func(context.Context) ([]byte, error) {
+        return []byte{byte('0' + calls.Add(1))}, nil
+    }
+    const photos = "https://booking-com18.p.rapidapi.com/stays/get-photos?hotelId=1"
+    get := func(url string) string {
+        body, err := cache.Get(context.Background(), url, fetch)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return string(body)
+    }
+
+    if get(photos) != "1" || get(photos) != "1" {
+        t.Fatal("expected the first response to be cached")
+    }
+
+    // Evict from memory; the Postgres tier still has it
+    cache.Get(context.Background(), "https://booking-com18.p.rapidapi.com/other", func(context.Context) ([]byte, error) { return nil, nil })
+    if get(photos) != "1" {
+        t.Fatal("expected the stored response")
+    }
+
+    // Past the TTL the stale body is served while it refreshes
+    now = now.Add(endpointPolicies["/stays/get-photos"].TTL + time.Minute)
+    if get(photos) != "1" {
+        t.Fatal("expected the stale response")
+    }
+    for calls.Load() < 2 || refreshing(cache) {
+        time.Sleep(time.Millisecond)
+    }
+    if got := get(photos); got != "2" {
+        t.Fatalf("after refresh got %q", got)
+    }
+
+    stats := cache.Stats()["/stays/get-photos"]
+    if stats.Misses != 1 || stats.MemoryHits != 2 || stats.PostgresHits != 1 || stats.StaleHits != 1 {
+        t.Errorf("stats = %+v", stats)
+    }
+    if got := testutil.ToFloat64(lookups) - memoryHitsBefore; got != 2 {
+        t.Errorf("memory_hit lookups metric grew by %v", got)
+    }
+}
+
+func TestWithRefreshSkipsCachedResponse(t *testing.T) {
+    cache := NewCache(10, nil)
+    var calls atomic.Int32
+    fetch := func(context.Context) ([]byte, error) {
+        return []byte{byte('0' + calls.Add(1))}, nil
+    }
+    const detail = "https://booking-com18.p.rapidapi.com/stays/detail?hotelId=1"
+
+    cache.Get(context.Background(), detail, fetch)
+    body, err := cache.Get(WithRefresh(context.Background()), detail, fetch)
+    if err != nil || string(body) != "2" {
+        t.Fatalf("forced refresh = %q, %v", body, err)
+    }
+    if body, _ := cache.Get(context.Background(), detail, fetch); string(body) != "2" {
+        t.Errorf("after refresh got %q", body)
+    }
+}
+
+func refreshing(cache *Cache) bool {
+    cache.mu.Lock()
+    defer cache.mu.Unlock()
+    return len(cache.inflight) > 0
+}
+
+func TestCacheSharesConcurrentMisses(t *testing.T) {
+    cache := NewCache(10, nil)
+    release := make(chan struct{})
+    var calls atomic.Int32
+    fetch := func(context.Context) ([]byte, error) {
+        calls.Add(1)
+        <-release
+        return nil, errors.New("down")
+    }
+
+    var wg sync.WaitGroup
+    errs := make(chan error, 5)
+    for i := 0; i < 5; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            _, err := cache.Get(context.Background(), "https://example.com/stays/detail?hotelId=1", fetch)
+            errs <- err
+        }()
+    }
+    time.Sleep(20 * time.Millisecond)
+    close(release)
+    wg.Wait()
+    close(errs)
+
+    for err := range errs {
+        if err == nil {
+            t.Error("expected the fetch error")
+        }
+    }
+    if calls.Load() != 1 {
+        t.Errorf("fetched %d times", calls.Load())
+    }
+}
@@ -0,0 +1,117 @@
+package apiclient
+
+import (
+    "net/http"
+    "strconv"
+    "sync"
+    "time"
+)
+
+// recentWindow is the number of latest calls per endpoint the recent error
+// rate is computed over, so an outage shows even after a long healthy uptime
+const recentWindow = 100
+
+// EndpointStatus counts the upstream calls of one endpoint since startup.
+// Errors are calls without a response, 429s and 5xx; other 4xx answer for
+// unknown IDs and do not count.
+type EndpointStatus struct {
+    Requests        int64      `json:"requests"`
+    Errors          int64      `json:"errors"`
+    RecentErrorRate float64    `json:"recent_error_rate"`
+    LastStatus      int        `json:"last_status"`
+    LastErrorAt     *time.Time `json:"last_error_at,omitempty"`
+
+    recent []bool
+}
+
+// QuotaStatus is the upstream request quota as reported by the
+// X-RateLimit-Requests-* headers of the latest response that carried them
+type QuotaStatus struct {
+    Limit     *int64     `json:"limit,omitempty"`
+    Remaining *int64     `json:"remaining,omitempty"`
+    ResetsAt  *time.Time `json:"resets_at,omitempty"`
+    // Exhausted is set by a 429 or a zero remaining count and cleared by the
+    // next response without either
+    Exhausted bool       `json:"exhausted"`
+    UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+var (
+    statusMu  sync.Mutex
+    endpoints = map[string]*EndpointStatus{}
+    quota     QuotaStatus
+)
+
+// UpstreamStatus returns the call counts per upstream endpoint since startup
+func UpstreamStatus() map[string]EndpointStatus {
+    statusMu.Lock()
+    defer statusMu.Unlock()
+    result := make(map[string]EndpointStatus, len(endpoints))
+    for endpoint, s := range endpoints {
+        copied := *s
+        copied.recent = nil
+        result[endpoint] = copied
+    }
+    return result
+}
+
+// Quota returns the last known upstream request quota
+func Quota() QuotaStatus {
+    statusMu.Lock()
+    defer statusMu.Unlock()
+    return quota
+}
+
+// recordStatus records the outcome of an upstream call; status is zero when
+// it failed without a response
+func recordStatus(endpoint string, status int, header http.Header) {
+    now := time.Now()
+    failed := status == 0 || status == http.StatusTooManyRequests || status >= 500
+
+    statusMu.Lock()
+    defer statusMu.Unlock()
+    s, ok := endpoints[endpoint]
+    if !ok {
+        s = &EndpointStatus{}
+        endpoints[endpoint] = s
+    }
+    s.Requests++
+    s.LastStatus = status
+    if failed {
+        s.Errors++
+        s.LastErrorAt = &now
+    }
+    if len(s.recent) == recentWindow {
+        s.recent = s.recent[1:]
+    }
+    s.recent = append(s.recent, failed)
+    recentErrors := 0
+    for _, f := range s.recent {
+        if f {
+            recentErrors++
+        }
+    }
+    s.RecentErrorRate = float64(recentErrors) / float64(len(s.recent))
+
+    if status == 0 {
+        return
+    }
+    if limit, ok := headerInt(header, "X-RateLimit-Requests-Limit"); ok {
+        quota.Limit = &limit
+    }
+    remaining, hasRemaining := headerInt(header, "X-RateLimit-Requests-Remaining")
+    if hasRemaining {
+        quota.Remaining = &remaining
+    }
+    if reset, ok := headerInt(header, "X-RateLimit-Requests-Reset"); ok {
+        resetsAt := now.Add(time.Duration(reset) * time.Second)
+        quota.ResetsAt = &resetsAt
+    }
+    quota.Exhausted = status == http.StatusTooManyRequests || (hasRemaining && remaining <= 0)
+    quota.UpdatedAt = &now
+}
+
+func headerInt(header http.Header, name string) (int64, bool) {
+    value, err := strconv.ParseInt(header.Get(name), 10, 64)
+    return value, err == nil
+}
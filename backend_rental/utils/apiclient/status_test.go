@@ -0,0 +1,38 @@
+package apiclient
+
+import (
+    "net/http"
+    "testing"
+)
+
+func TestRecordStatusTracksErrorsAndQuota(t *testing.T) {
+    header := http.Header{}
+    header.Set("X-RateLimit-Requests-Limit", "500")
+    header.Set("X-RateLimit-Requests-Remaining", "0")
+    header.Set("X-RateLimit-Requests-Reset", "3600")
+
+    recordStatus("/status-test", http.StatusOK, header)
+    recordStatus("/status-test", http.StatusNotFound, http.Header{})
+    recordStatus("/status-test", 0, nil)
+    recordStatus("/status-test", http.StatusBadGateway, http.Header{})
+
+    status := UpstreamStatus()["/status-test"]
+    if status.Requests != 4 || status.Errors != 2 {
+        t.Errorf("%d requests, %d errors; want 4 and 2", status.Requests, status.Errors)
+    }
+    if status.RecentErrorRate != 0.5 {
+        t.Errorf("recent error rate %v, want 0.5", status.RecentErrorRate)
+    }
+
+    quota := Quota()
+    if quota.Limit == nil || *quota.Limit != 500 || quota.Remaining == nil || *quota.Remaining != 0 {
+        t.Fatalf("quota %+v, want the header values", quota)
+    }
+    if quota.Exhausted {
+        t.Error("quota still exhausted after responses without a zero remaining count")
+    }
+    recordStatus("/status-test", http.StatusTooManyRequests, http.Header{})
+    if !Quota().Exhausted {
+        t.Error("a 429 did not mark the quota exhausted")
+    }
+}
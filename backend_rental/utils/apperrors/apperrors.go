@@ -0,0 +1,159 @@
+// Package apperrors defines the typed domain errors services return and their
+// mapping to HTTP status codes and RFC 7807 problem details.
+package apperrors
+
+import (
+    "errors"
+    "fmt"
+    "net/http"
+    "strings"
+)
+
+// Kind classifies a domain error
+type Kind int
+
+const (
+    KindInternal Kind = iota
+    KindValidation
+    KindNotFound
+    KindConflict
+    KindUnauthorized
+    KindRateLimited
+    KindUpstreamUnavailable
+)
+
+var kindInfo = map[Kind]struct {
+    status int
+    slug   string
+    title  string
+}{
+    KindInternal:            {http.StatusInternalServerError, "internal", "Internal server error"},
+    KindValidation:          {http.StatusBadRequest, "validation", "Invalid request"},
+    KindNotFound:            {http.StatusNotFound, "not-found", "Resource not found"},
+    KindConflict:            {http.StatusConflict, "conflict", "Conflict"},
+    KindUnauthorized:        {http.StatusUnauthorized, "unauthorized", "Unauthorized"},
+    KindRateLimited:         {http.StatusTooManyRequests, "rate-limited", "Too many requests"},
+    KindUpstreamUnavailable: {http.StatusBadGateway, "upstream-unavailable", "Upstream service unavailable"},
+}
+
+// Error is a domain error; Message is safe to show to API clients while Err
+// keeps the underlying cause for logs
+type Error struct {
+    Kind    Kind
+    Message string
+    Err     error
+    // Fields lists the individual request fields that failed validation
+    Fields []FieldError
+}
+
+// FieldError describes one invalid request field
+type FieldError struct {
+    Field   string `json:"field"`
+    Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+    if e.Err != nil {
+        return e.Message + ": " + e.Err.Error()
+    }
+    return e.Message
+}
+
+func (e *Error) Unwrap() error {
+    return e.Err
+}
+
+func newError(kind Kind, err error, format string, args ...interface{}) *Error {
+    return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// Validation reports a request that failed validation
+func Validation(format string, args ...interface{}) *Error {
+    return newError(KindValidation, nil, format, args...)
+}
+
+// InvalidFields reports a request whose fields failed validation
+func InvalidFields(fields ...FieldError) *Error {
+    messages := make([]string, len(fields))
+    for i, field := range fields {
+        messages[i] = field.Field + " " + field.Message
+    }
+    err := newError(KindValidation, nil, "%s", strings.Join(messages, "; "))
+    err.Fields = fields
+    return err
+}
+
+// NotFound reports a missing resource
+func NotFound(format string, args ...interface{}) *Error {
+    return newError(KindNotFound, nil, format, args...)
+}
+
+// Conflict reports a request that clashes with the current state
+func Conflict(format string, args ...interface{}) *Error {
+    return newError(KindConflict, nil, format, args...)
+}
+
+// Unauthorized reports missing or invalid credentials
+func Unauthorized(format string, args ...interface{}) *Error {
+    return newError(KindUnauthorized, nil, format, args...)
+}
+
+// RateLimited reports that upstream or this API refused the request for rate reasons
+func RateLimited(err error, format string, args ...interface{}) *Error {
+    return newError(KindRateLimited, err, format, args...)
+}
+
+// UpstreamUnavailable reports a failed call to the upstream booking API
+func UpstreamUnavailable(err error, format string, args ...interface{}) *Error {
+    return newError(KindUpstreamUnavailable, err, format, args...)
+}
+
+// KindOf returns the kind of the first domain error in err's chain, or KindInternal
+func KindOf(err error) Kind {
+    var appErr *Error
+    if errors.As(err, &appErr) {
+        return appErr.Kind
+    }
+    return KindInternal
+}
+
+// Status returns the HTTP status code for err
+func Status(err error) int {
+    return kindInfo[KindOf(err)].status
+}
+
+// Problem is an RFC 7807 problem details body
+type Problem struct {
+    Type      string `json:"type"`
+    Title     string `json:"title"`
+    Status    int    `json:"status"`
+    Detail    string `json:"detail,omitempty"`
+    Instance  string `json:"instance,omitempty"`
+    RequestID string `json:"request_id,omitempty"`
+    // Errors holds field-level details of validation problems
+    Errors []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem describes err for a client; internal errors get a generic detail
+// so causes stay in the logs
+func NewProblem(err error, instance, requestID string) Problem {
+    kind := KindOf(err)
+    info := kindInfo[kind]
+
+    problem := Problem{
+        Type:      "/problems/" + info.slug,
+        Title:     info.title,
+        Status:    info.status,
+        Instance:  instance,
+        RequestID: requestID,
+    }
+
+    var appErr *Error
+    if kind != KindInternal && errors.As(err, &appErr) {
+        problem.Detail = appErr.Message
+        problem.Errors = appErr.Fields
+    } else {
+        problem.Detail = "An unexpected error occurred"
+    }
+    return problem
+}
@@ -0,0 +1,53 @@
+package apperrors
+
+import (
+    "errors"
+    "fmt"
+    "testing"
+)
+
+func TestStatusFollowsWrappedErrors(t *testing.T) {
+    cases := []struct {
+        err  error
+        want int
+    }{
+        {Validation("name is required"), 400},
+        {fmt.Errorf("loading wishlist: %w", NotFound("wishlist not found")), 404},
+        {Conflict("review exists"), 409},
+        {Unauthorized("token required"), 401},
+        {RateLimited(nil, "slow down"), 429},
+        {UpstreamUnavailable(errors.New("timeout"), "upstream failed"), 502},
+        {errors.New("boom"), 500},
+    }
+    for _, tc := range cases {
+        if got := Status(tc.err); got != tc.want {
+            t.Errorf("Status(%v) = %d, want %d", tc.err, got, tc.want)
+        }
+    }
+}
+
+func TestNewProblemHidesInternalDetail(t *testing.T) {
+    problem := NewProblem(errors.New("pq: connection refused"), "/v1/property/list", "abc")
+    if problem.Status != 500 || problem.Detail != "An unexpected error occurred" || problem.RequestID != "abc" {
+        t.Errorf("internal problem = %+v", problem)
+    }
+
+    problem = NewProblem(UpstreamUnavailable(errors.New("dial tcp: timeout"), "upstream description request failed"), "", "")
+    if problem.Type != "/problems/upstream-unavailable" || problem.Detail != "upstream description request failed" {
+        t.Errorf("upstream problem = %+v", problem)
+    }
+}
+
+func TestNewProblemListsInvalidFields(t *testing.T) {
+    err := InvalidFields(
+        FieldError{Field: "limit", Message: "must be between 1 and 100"},
+        FieldError{Field: "sort", Message: "must be one of name|rating"},
+    )
+    problem := NewProblem(fmt.Errorf("binding request: %w", err), "", "")
+    if problem.Status != 400 || len(problem.Errors) != 2 || problem.Errors[0].Field != "limit" {
+        t.Errorf("validation problem = %+v", problem)
+    }
+    if problem.Detail != "limit must be between 1 and 100; sort must be one of name|rating" {
+        t.Errorf("detail = %q", problem.Detail)
+    }
+}
@@ -0,0 +1,302 @@
+// Package binding fills request structs from the query string, route
+// parameters and JSON body, and validates them with beego's `valid` tags so
+// controllers get typed, checked input in one call.
+//
+// Fields are bound by tag:
+//
+//	form:"name"     query string parameter; slices also accept comma-separated values
+//	path:"name"     route parameter, ":name" in the router
+//	json:"name"     field of the JSON request body
+//	default:"value" value used when the parameter is not supplied
+//	valid:"rules"   beego validation rules, e.g. "Required;Range(1,100);Enum(a|b)"
+//
+// Rules other than Required only apply to supplied fields, so optional
+// parameters can be left out. Embedded structs are bound and validated too,
+// and structs implementing validation.ValidFormer get cross-field checks.
+package binding
+
+import (
+    "bytes"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/url"
+    "reflect"
+    "strconv"
+    "strings"
+    "time"
+
+    "backend_rental/utils/apperrors"
+    "github.com/beego/beego/v2/core/validation"
+    "github.com/beego/beego/v2/server/web/context"
+)
+
+// DateLayout is the format accepted by the Date rule
+const DateLayout = "2006-01-02"
+
+func init() {
+    // Client-facing messages; each is prefixed with the parameter name
+    for name, message := range map[string]string{
+        "Required": "is required",
+        "Min":      "must be at least %d",
+        "Max":      "must be at most %d",
+        "Range":    "must be between %d and %d",
+        "MinSize":  "must have a length of at least %d",
+        "MaxSize":  "must have a length of at most %d",
+        "Length":   "must have a length of %d",
+        "Alpha":    "must contain only letters",
+        "Numeric":  "must contain only digits",
+        "Match":    "must match %s",
+        "Enum":     "must be one of %s",
+    } {
+        validation.MessageTmpls[name] = message
+    }
+
+    validation.AddCustomFunc("Date", func(v *validation.Validation, obj interface{}, key string) {
+        if value, ok := obj.(string); ok {
+            if _, err := time.Parse(DateLayout, value); err == nil {
+                return
+            }
+        }
+        v.AddError(key, "must be a date in YYYY-MM-DD format")
+    })
+}
+
+type source struct {
+    query url.Values
+    param func(name string) string
+    body  map[string]json.RawMessage
+}
+
+// Bind fills dst, a pointer to a request struct, from the request and validates it
+func Bind(ctx *context.Context, dst interface{}) error {
+    src := source{
+        query: ctx.Request.URL.Query(),
+        param: func(name string) string { return ctx.Input.Param(":" + name) },
+    }
+
+    if body := bytes.TrimSpace(ctx.Input.RequestBody); len(body) > 0 {
+        if err := json.Unmarshal(body, &src.body); err != nil {
+            return apperrors.InvalidFields(apperrors.FieldError{Field: "body", Message: "must be a JSON object"})
+        }
+        if err := json.Unmarshal(body, dst); err != nil {
+            return apperrors.InvalidFields(bodyFieldError(err))
+        }
+    }
+
+    return bind(dst, src)
+}
+
+// BindValues fills dst from query parameters alone and validates it
+func BindValues(values url.Values, dst interface{}) error {
+    return bind(dst, source{query: values})
+}
+
+func bodyFieldError(err error) apperrors.FieldError {
+    var typeErr *json.UnmarshalTypeError
+    if errors.As(err, &typeErr) && typeErr.Field != "" {
+        return apperrors.FieldError{Field: typeErr.Field, Message: "must be of type " + typeErr.Type.String()}
+    }
+    return apperrors.FieldError{Field: "body", Message: "must be valid JSON"}
+}
+
+type binder struct {
+    src source
+    // names maps struct field names to the parameter names clients use
+    names    map[string]string
+    supplied map[string]bool
+    errs     []apperrors.FieldError
+}
+
+func bind(dst interface{}, src source) error {
+    v := reflect.ValueOf(dst)
+    if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+        return fmt.Errorf("binding: %T is not a pointer to a struct", dst)
+    }
+
+    b := &binder{src: src, names: map[string]string{}, supplied: map[string]bool{}}
+    b.fill(v.Elem())
+    if len(b.errs) > 0 {
+        return apperrors.InvalidFields(b.errs...)
+    }
+    return b.validate(v.Elem())
+}
+
+func (b *binder) fill(v reflect.Value) {
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        field, value := t.Field(i), v.Field(i)
+        if field.Anonymous && value.Kind() == reflect.Struct {
+            b.fill(value)
+            continue
+        }
+        if !field.IsExported() {
+            continue
+        }
+
+        name, values, supplied := b.lookup(field)
+        if name == "" {
+            continue
+        }
+        b.names[field.Name] = name
+        b.supplied[field.Name] = supplied
+
+        if !supplied {
+            def, ok := field.Tag.Lookup("default")
+            if !ok || !value.IsZero() {
+                continue
+            }
+            values = []string{def}
+        }
+        if values == nil {
+            continue
+        }
+        if message := setValue(value, values); message != "" {
+            b.errs = append(b.errs, apperrors.FieldError{Field: name, Message: message})
+        }
+    }
+}
+
+// lookup returns the parameter name of a field and, for query and route
+// parameters, its raw values; body fields are already decoded
+func (b *binder) lookup(field reflect.StructField) (string, []string, bool) {
+    if name := field.Tag.Get("path"); name != "" {
+        if b.src.param == nil {
+            return name, nil, false
+        }
+        value := b.src.param(name)
+        return name, []string{value}, value != ""
+    }
+    if name := field.Tag.Get("form"); name != "" && name != "-" {
+        values := b.src.query[name]
+        return name, values, len(values) > 0 && strings.TrimSpace(strings.Join(values, "")) != ""
+    }
+    if name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]; name != "" && name != "-" {
+        _, supplied := b.src.body[name]
+        return name, nil, supplied
+    }
+    return "", nil, false
+}
+
+// setValue parses raw parameter values into a field, returning a message when they do not fit its type
+func setValue(value reflect.Value, values []string) string {
+    raw := strings.TrimSpace(values[0])
+
+    switch value.Kind() {
+    case reflect.String:
+        value.SetString(raw)
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+        if err != nil {
+            return "must be an integer"
+        }
+        value.SetInt(n)
+    case reflect.Float32, reflect.Float64:
+        f, err := strconv.ParseFloat(raw, value.Type().Bits())
+        if err != nil {
+            return "must be a number"
+        }
+        value.SetFloat(f)
+    case reflect.Bool:
+        ok, err := strconv.ParseBool(raw)
+        if err != nil {
+            return "must be true or false"
+        }
+        value.SetBool(ok)
+    case reflect.Slice:
+        if value.Type().Elem().Kind() != reflect.String {
+            return "is not supported as a parameter"
+        }
+        var items []string
+        for _, v := range values {
+            for _, item := range strings.Split(v, ",") {
+                if item = strings.TrimSpace(item); item != "" {
+                    items = append(items, item)
+                }
+            }
+        }
+        value.Set(reflect.ValueOf(items))
+    default:
+        return "is not supported as a parameter"
+    }
+    return ""
+}
+
+func (b *binder) validate(v reflect.Value) error {
+    valid := validation.Validation{}
+    if err := check(&valid, v); err != nil {
+        return fmt.Errorf("binding: invalid rules on %s: %v", v.Type(), err)
+    }
+
+    fields := b.fieldErrors(valid.Errors)
+    if len(fields) == 0 && valid.HasErrors() {
+        // beego skips cross-field checks after any rule failure, including
+        // the ignored failures of optional fields that were left out
+        cross := validation.Validation{}
+        validForms(&cross, v)
+        fields = b.fieldErrors(cross.Errors)
+    }
+
+    if len(fields) > 0 {
+        return apperrors.InvalidFields(fields...)
+    }
+    return nil
+}
+
+// fieldErrors converts validation errors into client-facing field errors,
+// dropping those of optional fields that were not supplied. Duplicates are
+// dropped too: a cross-field check promoted from an embedded struct runs for
+// both the embedding and the embedded struct.
+func (b *binder) fieldErrors(errs []*validation.Error) []apperrors.FieldError {
+    var fields []apperrors.FieldError
+    seen := map[apperrors.FieldError]bool{}
+    for _, e := range errs {
+        name, bound := b.names[e.Field]
+        if bound && !b.supplied[e.Field] && e.Name != "Required" {
+            continue
+        }
+        if !bound {
+            // Cross-field checks name the parameter themselves
+            name = e.Field
+        }
+        message := e.Message
+        if e.Label != "" {
+            message = strings.TrimPrefix(message, e.Label+" ")
+        }
+        field := apperrors.FieldError{Field: name, Message: message}
+        if !seen[field] {
+            seen[field] = true
+            fields = append(fields, field)
+        }
+    }
+    return fields
+}
+
+// check validates a struct and the structs embedded in it
+func check(valid *validation.Validation, v reflect.Value) error {
+    if _, err := valid.Valid(v.Addr().Interface()); err != nil {
+        return err
+    }
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        if t.Field(i).Anonymous && v.Field(i).Kind() == reflect.Struct {
+            if err := check(valid, v.Field(i)); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
+
+// validForms runs the cross-field checks of a struct and the structs embedded in it
+func validForms(valid *validation.Validation, v reflect.Value) {
+    if form, ok := v.Addr().Interface().(validation.ValidFormer); ok {
+        form.Valid(valid)
+    }
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        if t.Field(i).Anonymous && v.Field(i).Kind() == reflect.Struct {
+            validForms(valid, v.Field(i))
+        }
+    }
+}
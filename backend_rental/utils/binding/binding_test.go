@@ -0,0 +1,90 @@
+package binding
+
+import (
+    "errors"
+    "net/url"
+    "testing"
+
+    "backend_rental/utils/apperrors"
+    "github.com/beego/beego/v2/core/validation"
+)
+
+type listRequest struct {
+    Limit     int      `form:"limit" default:"20" valid:"Range(1,100)"`
+    Sort      string   `form:"sort" default:"newest" valid:"Enum(newest|oldest)"`
+    Country   string   `form:"country" valid:"MaxSize(8)"`
+    CheckIn   string   `form:"checkin" valid:"Date"`
+    Amenities []string `form:"amenities"`
+    DestID    string   `form:"dest_id" valid:"Required"`
+    MinBeds   int      `form:"min_beds" valid:"Min(0)"`
+    MaxBeds   int      `form:"max_beds" valid:"Min(0)"`
+}
+
+func (r *listRequest) Valid(v *validation.Validation) {
+    if r.MaxBeds > 0 && r.MinBeds > r.MaxBeds {
+        v.SetError("min_beds", "must not exceed max_beds")
+    }
+}
+
+func fieldErrors(t *testing.T, err error) map[string]string {
+    t.Helper()
+    var appErr *apperrors.Error
+    if !errors.As(err, &appErr) || appErr.Kind != apperrors.KindValidation {
+        t.Fatalf("error = %v, want a validation error", err)
+    }
+    fields := map[string]string{}
+    for _, field := range appErr.Fields {
+        fields[field.Field] = field.Message
+    }
+    return fields
+}
+
+func TestBindValuesAppliesDefaultsAndParses(t *testing.T) {
+    var req listRequest
+    err := BindValues(url.Values{"dest_id": {"42"}, "amenities": {"wifi, pool", "parking"}, "checkin": {"2026-05-01"}}, &req)
+    if err != nil {
+        t.Fatalf("BindValues: %v", err)
+    }
+    if req.Limit != 20 || req.Sort != "newest" || req.DestID != "42" || len(req.Amenities) != 3 {
+        t.Errorf("bound request = %+v", req)
+    }
+}
+
+func TestBindValuesReportsEveryInvalidField(t *testing.T) {
+    var req listRequest
+    err := BindValues(url.Values{
+        "limit":   {"100000"},
+        "sort":    {"random"},
+        "checkin": {"01/05/2026"},
+        "min_beds": {"x"},
+    }, &req)
+    fields := fieldErrors(t, err)
+    if fields["min_beds"] != "must be an integer" || len(fields) != 1 {
+        t.Fatalf("parse errors = %v", fields)
+    }
+
+    err = BindValues(url.Values{"limit": {"-5"}, "sort": {"random"}, "checkin": {"01/05/2026"}}, &req)
+    fields = fieldErrors(t, err)
+    want := map[string]string{
+        "limit":   "must be between 1 and 100",
+        "sort":    "must be one of newest|oldest",
+        "checkin": "must be a date in YYYY-MM-DD format",
+        "dest_id": "is required",
+    }
+    for field, message := range want {
+        if fields[field] != message {
+            t.Errorf("%s: got %q, want %q", field, fields[field], message)
+        }
+    }
+    if _, ok := fields["country"]; ok {
+        t.Errorf("optional country was validated although absent: %v", fields)
+    }
+}
+
+func TestBindValuesRunsCrossFieldChecks(t *testing.T) {
+    var req listRequest
+    err := BindValues(url.Values{"dest_id": {"42"}, "min_beds": {"3"}, "max_beds": {"2"}}, &req)
+    if fields := fieldErrors(t, err); fields["min_beds"] != "must not exceed max_beds" {
+        t.Errorf("cross-field errors = %v", fields)
+    }
+}
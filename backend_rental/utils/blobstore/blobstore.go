@@ -0,0 +1,69 @@
+// Package blobstore stores binary objects such as photos under string keys,
+// on the local filesystem or in an S3-compatible object store.
+package blobstore
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "io"
+    "sync"
+    "time"
+
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+// ErrNotFound is returned by Get for keys that are not stored
+var ErrNotFound = errors.New("blobstore: object not found")
+
+// Object describes a stored object
+type Object struct {
+    Key         string
+    ContentType string
+    Size        int64
+    ModTime     time.Time
+}
+
+// Store keeps objects under slash-separated keys such as photos/123/original
+type Store interface {
+    // Get opens a stored object; the caller closes the reader
+    Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+    // Put stores size bytes of body, replacing any object under key
+    Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+    Delete(ctx context.Context, key string) error
+}
+
+var (
+    defaultStore     Store
+    defaultStoreErr  error
+    defaultStoreOnce sync.Once
+)
+
+// Default returns the store configured by blobstore: "local" (the default)
+// keeps objects under blobdir, "s3" in the s3bucket of an S3-compatible
+// server such as MinIO
+func Default() (Store, error) {
+    defaultStoreOnce.Do(func() {
+        defaultStore, defaultStoreErr = FromConfig()
+    })
+    return defaultStore, defaultStoreErr
+}
+
+// FromConfig creates the store described by app.conf
+func FromConfig() (Store, error) {
+    switch kind := beego.AppConfig.DefaultString("blobstore", "local"); kind {
+    case "local":
+        return NewLocal(beego.AppConfig.DefaultString("blobdir", "data/blobs"))
+    case "s3":
+        return NewS3(S3Config{
+            Endpoint:  beego.AppConfig.DefaultString("s3endpoint", "localhost:9000"),
+            Region:    beego.AppConfig.DefaultString("s3region", ""),
+            Bucket:    beego.AppConfig.DefaultString("s3bucket", "rental"),
+            AccessKey: beego.AppConfig.DefaultString("s3accesskey", ""),
+            SecretKey: beego.AppConfig.DefaultString("s3secretkey", ""),
+            UseSSL:    beego.AppConfig.DefaultBool("s3usessl", false),
+        })
+    default:
+        return nil, fmt.Errorf("blobstore: unknown store %q", kind)
+    }
+}
@@ -0,0 +1,106 @@
+package blobstore
+
+import (
+    "context"
+    "fmt"
+    "io"
+    "net/http"
+    "os"
+    "path/filepath"
+)
+
+// Local keeps objects as files under a directory. Content types are not
+// stored; Get detects them from the content.
+type Local struct {
+    dir string
+}
+
+// NewLocal creates a store under dir, creating the directory if needed
+func NewLocal(dir string) (*Local, error) {
+    if err := os.MkdirAll(dir, 0o755); err != nil {
+        return nil, fmt.Errorf("blobstore: creating %s: %w", dir, err)
+    }
+    return &Local{dir: dir}, nil
+}
+
+func (s *Local) path(key string) (string, error) {
+    // Cleaning the key as an absolute path keeps it inside dir
+    clean := filepath.Clean("/" + key)
+    if clean == "/" {
+        return "", fmt.Errorf("blobstore: invalid key %q", key)
+    }
+    return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
+
+func (s *Local) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+    path, err := s.path(key)
+    if err != nil {
+        return nil, Object{}, err
+    }
+    file, err := os.Open(path)
+    if os.IsNotExist(err) {
+        return nil, Object{}, ErrNotFound
+    }
+    if err != nil {
+        return nil, Object{}, err
+    }
+    info, err := file.Stat()
+    if err != nil {
+        file.Close()
+        return nil, Object{}, err
+    }
+
+    head := make([]byte, 512)
+    n, err := io.ReadFull(file, head)
+    if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+        file.Close()
+        return nil, Object{}, err
+    }
+    if _, err := file.Seek(0, io.SeekStart); err != nil {
+        file.Close()
+        return nil, Object{}, err
+    }
+
+    return file, Object{
+        Key:         key,
+        ContentType: http.DetectContentType(head[:n]),
+        Size:        info.Size(),
+        ModTime:     info.ModTime(),
+    }, nil
+}
+
+// Put writes to a temporary file first so readers never see a partial object
+func (s *Local) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+    path, err := s.path(key)
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return err
+    }
+    tmp, err := os.CreateTemp(filepath.Dir(path), ".put-*")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(tmp.Name())
+
+    if _, err := io.Copy(tmp, body); err != nil {
+        tmp.Close()
+        return fmt.Errorf("blobstore: writing %s: %w", key, err)
+    }
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+    return os.Rename(tmp.Name(), path)
+}
+
+func (s *Local) Delete(ctx context.Context, key string) error {
+    path, err := s.path(key)
+    if err != nil {
+        return err
+    }
+    if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+        return err
+    }
+    return nil
+}
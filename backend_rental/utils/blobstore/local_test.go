@@ -0,0 +1,52 @@
+package blobstore
+
+import (
+    "bytes"
+    "context"
+    "io"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestLocalRoundTrip(t *testing.T) {
+    dir := t.TempDir()
+    store, err := NewLocal(dir)
+    if err != nil {
+        t.Fatal(err)
+    }
+    ctx := context.Background()
+
+    if _, _, err := store.Get(ctx, "photos/1/original"); err != ErrNotFound {
+        t.Fatalf("Get of a missing key = %v", err)
+    }
+
+    png := []byte("\x89PNG\r\n\x1a\n0000")
+    if err := store.Put(ctx, "photos/1/original", bytes.NewReader(png), int64(len(png)), "image/png"); err != nil {
+        t.Fatal(err)
+    }
+    body, object, err := store.Get(ctx, "photos/1/original")
+    if err != nil {
+        t.Fatal(err)
+    }
+    data, _ := io.ReadAll(body)
+    body.Close()
+    if !bytes.Equal(data, png) || object.ContentType != "image/png" || object.Size != int64(len(png)) {
+        t.Errorf("got %q, %+v", data, object)
+    }
+
+    // Keys cannot escape the directory
+    if err := store.Put(ctx, "../../escape", bytes.NewReader(png), int64(len(png)), ""); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := os.Stat(filepath.Join(dir, "escape")); err != nil {
+        t.Errorf("escaping key not kept under the store: %v", err)
+    }
+
+    if err := store.Delete(ctx, "photos/1/original"); err != nil {
+        t.Fatal(err)
+    }
+    if _, _, err := store.Get(ctx, "photos/1/original"); err != ErrNotFound {
+        t.Errorf("Get after Delete = %v", err)
+    }
+}
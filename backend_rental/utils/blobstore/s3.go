@@ -0,0 +1,86 @@
+package blobstore
+
+import (
+    "context"
+    "fmt"
+    "io"
+
+    "github.com/minio/minio-go/v7"
+    "github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config locates a bucket of an S3-compatible server
+type S3Config struct {
+    // Endpoint is the host and port of the server, e.g. localhost:9000 for MinIO
+    Endpoint  string
+    Region    string
+    Bucket    string
+    AccessKey string
+    SecretKey string
+    UseSSL    bool
+}
+
+// S3 keeps objects in a bucket of an S3-compatible server
+type S3 struct {
+    client *minio.Client
+    bucket string
+}
+
+// NewS3 connects to the server and creates the bucket if it does not exist
+func NewS3(config S3Config) (*S3, error) {
+    client, err := minio.New(config.Endpoint, &minio.Options{
+        Creds:  credentials.NewStaticV4(config.AccessKey, config.SecretKey, ""),
+        Secure: config.UseSSL,
+        Region: config.Region,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("blobstore: connecting to %s: %w", config.Endpoint, err)
+    }
+
+    ctx := context.Background()
+    exists, err := client.BucketExists(ctx, config.Bucket)
+    if err != nil {
+        return nil, fmt.Errorf("blobstore: checking bucket %s: %w", config.Bucket, err)
+    }
+    if !exists {
+        err := client.MakeBucket(ctx, config.Bucket, minio.MakeBucketOptions{Region: config.Region})
+        if err != nil {
+            return nil, fmt.Errorf("blobstore: creating bucket %s: %w", config.Bucket, err)
+        }
+    }
+    return &S3{client: client, bucket: config.Bucket}, nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+    object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+    if err != nil {
+        return nil, Object{}, err
+    }
+    // GetObject is lazy; Stat makes the request
+    info, err := object.Stat()
+    if err != nil {
+        object.Close()
+        if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+            return nil, Object{}, ErrNotFound
+        }
+        return nil, Object{}, err
+    }
+    return object, Object{
+        Key:         key,
+        ContentType: info.ContentType,
+        Size:        info.Size,
+        ModTime:     info.LastModified,
+    }, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+    _, err := s.client.PutObject(ctx, s.bucket, key, body, size, minio.PutObjectOptions{ContentType: contentType})
+    if err != nil {
+        return fmt.Errorf("blobstore: storing %s: %w", key, err)
+    }
+    return nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+    return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
@@ -2,17 +2,23 @@ package utils
 
 import (
     "fmt"
-    "log"
+    "log/slog"
+    "sync/atomic"
     "github.com/beego/beego/v2/server/web"
     "github.com/beego/beego/v2/client/orm"
     "gorm.io/gorm"
     "gorm.io/driver/postgres"
     _ "github.com/lib/pq"
     "backend_rental/models"
+    "backend_rental/utils/metrics"
+    "backend_rental/utils/tracing"
 )
 
 var db *gorm.DB
 
+// migrated is set once both ORMs have migrated their tables
+var migrated atomic.Bool
+
 // InitDatabaseFromConfig initializes both GORM and Beego ORM
 func InitDatabaseFromConfig() error {
     // Load configuration from app.conf
@@ -47,21 +53,50 @@ func InitDatabaseFromConfig() error {
         return fmt.Errorf("dbname not found in config: %v", err)
     }
 
-    // Initialize GORM
     gormDSN := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=disable",
         dbUser, dbPassword, dbName, dbHost, dbPort)
-    
+    beegoConnStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+        dbUser, dbPassword, dbHost, dbPort, dbName)
+    if err := InitDatabase(gormDSN, beegoConnStr); err != nil {
+        return err
+    }
+
+    slog.Info("database connected", "host", dbHost, "name", dbName)
+    return nil
+}
+
+// InitDatabase connects GORM and Beego ORM, migrates the schema and exports
+// the pool stats. Both DSNs may be the same postgres:// URL, as in tests.
+func InitDatabase(gormDSN, beegoConnStr string) error {
+    // Initialize GORM
+    var err error
     db, err = gorm.Open(postgres.Open(gormDSN), &gorm.Config{})
     if err != nil {
         return fmt.Errorf("failed to connect to database with GORM: %v", err)
     }
+    if err := db.Use(tracing.GormPlugin{}); err != nil {
+        return fmt.Errorf("failed to install GORM tracing: %v", err)
+    }
+
+    // Trigram similarity backs the typo-tolerant property search
+    if err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+        return fmt.Errorf("failed to enable pg_trgm: %v", err)
+    }
+
+    if err := db.AutoMigrate(&models.Property{}, &models.PropertyAmenity{}, &models.PropertySearchDocument{}); err != nil {
+        return fmt.Errorf("failed to migrate GORM models: %v", err)
+    }
 
-    // Initialize Beego ORM
+    err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_property_search_documents_keywords
+        ON property_search_documents USING gin (keywords gin_trgm_ops)`).Error
+    if err != nil {
+        return fmt.Errorf("failed to create search keyword index: %v", err)
+    }
+
+    // Initialize Beego ORM; the filter must be added before the first NewOrm
     orm.RegisterDriver("postgres", orm.DRPostgres)
-    
-    beegoConnStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-        dbUser, dbPassword, dbHost, dbPort, dbName)
-    
+    orm.AddGlobalFilterChain(tracing.ORMFilterChain)
+
     err = orm.RegisterDataBase("default", "postgres", beegoConnStr)
     if err != nil {
         return fmt.Errorf("failed to register database with Beego ORM: %v", err)
@@ -87,11 +122,23 @@ func InitDatabaseFromConfig() error {
         return fmt.Errorf("failed to sync database: %v", err)
     }
 
+    migrated.Store(true)
+
     // Set up connection pool parameters for Beego ORM
     orm.SetMaxIdleConns("default", 10)
     orm.SetMaxOpenConns("default", 100)
 
-    log.Println("Database connected successfully (GORM and Beego ORM)")
+    // Export the pool stats of both handles
+    sqlDB, err := db.DB()
+    if err != nil {
+        return fmt.Errorf("failed to get the GORM connection pool: %v", err)
+    }
+    metrics.RegisterDB("gorm", sqlDB)
+    beegoDB, err := orm.GetDB("default")
+    if err != nil {
+        return fmt.Errorf("failed to get the Beego ORM connection pool: %v", err)
+    }
+    metrics.RegisterDB("beego", beegoDB)
     return nil
 }
 
@@ -100,6 +147,11 @@ func GetDB() *gorm.DB {
     return db
 }
 
+// MigrationsApplied reports whether the schema migrations ran successfully
+func MigrationsApplied() bool {
+    return migrated.Load()
+}
+
 // GetBeegoOrm returns a new Beego ORM object
 func GetBeegoOrm() orm.Ormer {
     return orm.NewOrm()
@@ -0,0 +1,102 @@
+// Package httpcache implements the validators and Cache-Control policies of
+// cacheable GET responses (RFC 9110 conditional requests, RFC 9111 caching).
+package httpcache
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Policy is the Cache-Control policy of a route
+type Policy struct {
+    // MaxAge is how long clients and shared caches may reuse a response
+    MaxAge time.Duration
+    // StaleWhileRevalidate lets caches serve an expired response while they revalidate it
+    StaleWhileRevalidate time.Duration
+    // Private keeps responses out of shared caches
+    Private bool
+    // Immutable tells caches the response never changes, so they need not
+    // revalidate it before MaxAge
+    Immutable bool
+    // NoStore keeps the response out of every cache; the other fields are ignored
+    NoStore bool
+}
+
+// NoStore is the policy of responses no cache may keep, such as those served
+// with an admin override
+var NoStore = Policy{NoStore: true}
+
+// Header returns the Cache-Control header value
+func (p Policy) Header() string {
+    if p.NoStore {
+        return "no-store"
+    }
+    directives := []string{"public"}
+    if p.Private {
+        directives[0] = "private"
+    }
+    directives = append(directives, "max-age="+strconv.Itoa(int(p.MaxAge.Seconds())))
+    if p.StaleWhileRevalidate > 0 {
+        directives = append(directives, "stale-while-revalidate="+strconv.Itoa(int(p.StaleWhileRevalidate.Seconds())))
+    }
+    if p.Immutable {
+        directives = append(directives, "immutable")
+    }
+    return strings.Join(directives, ", ")
+}
+
+// Latest returns the latest of the times a response is built from, for its
+// Last-Modified; zero times are unknown and ignored
+func Latest(times ...time.Time) time.Time {
+    var latest time.Time
+    for _, t := range times {
+        if t.After(latest) {
+            latest = t
+        }
+    }
+    return latest
+}
+
+// ETag returns a strong entity tag for a response body
+func ETag(body []byte) string {
+    sum := sha256.Sum256(body)
+    return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// NotModified reports whether a GET or HEAD request already holds the
+// representation with the given validators. If-None-Match takes precedence
+// over If-Modified-Since, which is only checked when lastModified is known.
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+    if r.Method != http.MethodGet && r.Method != http.MethodHead {
+        return false
+    }
+
+    if inm := r.Header.Get("If-None-Match"); inm != "" {
+        return matchesETag(inm, etag)
+    }
+
+    if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+        since, err := http.ParseTime(ims)
+        if err != nil {
+            return false
+        }
+        // HTTP dates have a resolution of one second
+        return !lastModified.Truncate(time.Second).After(since)
+    }
+    return false
+}
+
+// matchesETag compares an If-None-Match list with weak comparison
+func matchesETag(header, etag string) bool {
+    for _, candidate := range strings.Split(header, ",") {
+        candidate = strings.TrimSpace(candidate)
+        if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,61 @@
+package httpcache
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+)
+
+func TestNotModified(t *testing.T) {
+    etag := ETag([]byte(`{"a":1}`))
+    modified := time.Date(2025, 3, 1, 12, 0, 0, 500, time.UTC)
+
+    tests := []struct {
+        name    string
+        method  string
+        headers map[string]string
+        want    bool
+    }{
+        {"no validators", "GET", nil, false},
+        {"matching etag", "GET", map[string]string{"If-None-Match": `"x", ` + etag}, true},
+        {"weak etag", "GET", map[string]string{"If-None-Match": "W/" + etag}, true},
+        {"other etag wins over date", "GET", map[string]string{"If-None-Match": `"x"`, "If-Modified-Since": modified.Format(http.TimeFormat)}, false},
+        {"wildcard", "HEAD", map[string]string{"If-None-Match": "*"}, true},
+        {"not modified since", "GET", map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, true},
+        {"modified since", "GET", map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)}, false},
+        {"post", "POST", map[string]string{"If-None-Match": etag}, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            r := httptest.NewRequest(tt.method, "/", nil)
+            for name, value := range tt.headers {
+                r.Header.Set(name, value)
+            }
+            if got := NotModified(r, etag, modified); got != tt.want {
+                t.Errorf("NotModified() = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestPolicyHeader(t *testing.T) {
+    policy := Policy{MaxAge: 5 * time.Minute, StaleWhileRevalidate: time.Hour}
+    if got := policy.Header(); got != "public, max-age=300, stale-while-revalidate=3600" {
+        t.Errorf("Header() = %q", got)
+    }
+    if got := NoStore.Header(); got != "no-store" {
+        t.Errorf("NoStore.Header() = %q", got)
+    }
+}
+
+func TestLatest(t *testing.T) {
+    early := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+    late := early.Add(time.Hour)
+    if got := Latest(early, time.Time{}, late); !got.Equal(late) {
+        t.Errorf("Latest() = %v, want %v", got, late)
+    }
+    if got := Latest(); !got.IsZero() {
+        t.Errorf("Latest() of nothing = %v, want the zero time", got)
+    }
+}
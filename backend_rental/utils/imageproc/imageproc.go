@@ -0,0 +1,138 @@
+// Package imageproc decodes, resizes and re-encodes photos. JPEG and PNG are
+// encoded in process; WebP is encoded by the cwebp tool of libwebp when it is
+// installed, see WebPSupported.
+package imageproc
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "image"
+    "image/jpeg"
+    "image/png"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "sync"
+
+    // Decoders of the formats accepted as originals
+    _ "image/gif"
+    _ "golang.org/x/image/webp"
+
+    "golang.org/x/image/draw"
+)
+
+// Output formats
+const (
+    JPEG = "jpeg"
+    PNG  = "png"
+    WebP = "webp"
+)
+
+const (
+    jpegQuality = 85
+    webpQuality = 80
+)
+
+// ContentType returns the media type of a format
+func ContentType(format string) string {
+    switch format {
+    case PNG:
+        return "image/png"
+    case WebP:
+        return "image/webp"
+    }
+    return "image/jpeg"
+}
+
+// Decode decodes a JPEG, PNG, GIF or WebP image and returns its format name
+func Decode(data []byte) (image.Image, string, error) {
+    img, format, err := image.Decode(bytes.NewReader(data))
+    if err != nil {
+        return nil, "", fmt.Errorf("imageproc: decoding image: %w", err)
+    }
+    return img, format, nil
+}
+
+// Resize scales img down to width, keeping its aspect ratio; images already
+// narrower than width, and widths of zero, return img unchanged
+func Resize(img image.Image, width int) image.Image {
+    bounds := img.Bounds()
+    if width <= 0 || width >= bounds.Dx() {
+        return img
+    }
+    height := bounds.Dy() * width / bounds.Dx()
+    if height < 1 {
+        height = 1
+    }
+    dst := image.NewRGBA(image.Rect(0, 0, width, height))
+    draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+    return dst
+}
+
+// Fit scales img down so that it fits in a size by size square
+func Fit(img image.Image, size int) image.Image {
+    bounds := img.Bounds()
+    if bounds.Dy() > bounds.Dx() && bounds.Dy() > size {
+        return Resize(img, bounds.Dx()*size/bounds.Dy())
+    }
+    return Resize(img, size)
+}
+
+// Encode encodes img in format. Encoding drops all metadata of the original,
+// EXIF included.
+func Encode(ctx context.Context, img image.Image, format string) ([]byte, error) {
+    var out bytes.Buffer
+    var err error
+    switch format {
+    case JPEG:
+        err = jpeg.Encode(&out, img, &jpeg.Options{Quality: jpegQuality})
+    case PNG:
+        err = png.Encode(&out, img)
+    case WebP:
+        return encodeWebP(ctx, img)
+    default:
+        return nil, fmt.Errorf("imageproc: unsupported format %q", format)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("imageproc: encoding %s: %w", format, err)
+    }
+    return out.Bytes(), nil
+}
+
+var cwebpPath = sync.OnceValue(func() string {
+    path, _ := exec.LookPath("cwebp")
+    return path
+})
+
+// WebPSupported reports whether cwebp is installed to encode WebP
+func WebPSupported() bool {
+    return cwebpPath() != ""
+}
+
+// encodeWebP hands a lossless PNG of img to cwebp
+func encodeWebP(ctx context.Context, img image.Image) ([]byte, error) {
+    if !WebPSupported() {
+        return nil, fmt.Errorf("imageproc: cwebp is not installed")
+    }
+    dir, err := os.MkdirTemp("", "imageproc-*")
+    if err != nil {
+        return nil, err
+    }
+    defer os.RemoveAll(dir)
+
+    in, out := filepath.Join(dir, "in.png"), filepath.Join(dir, "out.webp")
+    var source bytes.Buffer
+    if err := png.Encode(&source, img); err != nil {
+        return nil, err
+    }
+    if err := os.WriteFile(in, source.Bytes(), 0o600); err != nil {
+        return nil, err
+    }
+
+    cmd := exec.CommandContext(ctx, cwebpPath(), "-quiet", "-q", fmt.Sprint(webpQuality), "-metadata", "none", in, "-o", out)
+    if output, err := cmd.CombinedOutput(); err != nil {
+        return nil, fmt.Errorf("imageproc: cwebp: %v: %s", err, output)
+    }
+    return os.ReadFile(out)
+}
@@ -0,0 +1,77 @@
+package imageproc
+
+import (
+    "context"
+    "image"
+    "image/color"
+    "testing"
+)
+
+func TestResizeKeepsAspectRatio(t *testing.T) {
+    src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+    for x := 0; x < 400; x++ {
+        src.Set(x, 50, color.RGBA{R: 255, A: 255})
+    }
+
+    if got := Resize(src, 100).Bounds(); got.Dx() != 100 || got.Dy() != 50 {
+        t.Errorf("Resize to 100 = %v", got)
+    }
+    if got := Resize(src, 800); got != image.Image(src) {
+        t.Error("Resize should not upscale")
+    }
+    if got := Fit(image.NewRGBA(image.Rect(0, 0, 200, 400)), 100).Bounds(); got.Dx() != 50 || got.Dy() != 100 {
+        t.Errorf("Fit to 100 = %v", got)
+    }
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+    src := image.NewRGBA(image.Rect(0, 0, 16, 8))
+    formats := []string{JPEG, PNG}
+    if WebPSupported() {
+        formats = append(formats, WebP)
+    }
+    for _, format := range formats {
+        data, err := Encode(context.Background(), src, format)
+        if err != nil {
+            t.Fatalf("Encode %s: %v", format, err)
+        }
+        img, decoded, err := Decode(data)
+        if err != nil {
+            t.Fatalf("Decode %s: %v", format, err)
+        }
+        if decoded != format || img.Bounds() != src.Bounds() {
+            t.Errorf("%s decoded as %s %v", format, decoded, img.Bounds())
+        }
+    }
+}
+
+func TestOrientation(t *testing.T) {
+    src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+    src.Set(0, 0, color.RGBA{R: 255, A: 255})
+    data, err := Encode(context.Background(), src, JPEG)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // A big-endian EXIF block whose IFD0 holds orientation 6
+    tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00\x00\x00\x00\x00")
+    segment := append([]byte("Exif\x00\x00"), tiff...)
+    app1 := append([]byte{0xFF, 0xE1, byte((len(segment) + 2) >> 8), byte(len(segment) + 2)}, segment...)
+    withExif := append(append(append([]byte{}, data[:2]...), app1...), data[2:]...)
+
+    if got := Orientation(withExif); got != 6 {
+        t.Fatalf("Orientation = %d", got)
+    }
+    if got := Orientation(data); got != 1 {
+        t.Errorf("Orientation without EXIF = %d", got)
+    }
+
+    upright := Orient(src, 6)
+    if upright.Bounds().Dx() != 2 || upright.Bounds().Dy() != 4 {
+        t.Fatalf("oriented bounds = %v", upright.Bounds())
+    }
+    // The top-left pixel ends up top-right after turning clockwise
+    if r, _, _, _ := upright.At(1, 0).RGBA(); r != 0xFFFF {
+        t.Errorf("pixel not rotated clockwise")
+    }
+}
@@ -0,0 +1,107 @@
+package imageproc
+
+import (
+    "bytes"
+    "encoding/binary"
+    "image"
+)
+
+// Orientation returns the EXIF orientation of a JPEG, 1 to 8, or 1 when it
+// has none. Re-encoding drops the EXIF block, so photos taken sideways must
+// be turned upright with Orient first.
+func Orientation(data []byte) int {
+    if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+        return 1
+    }
+    for i := 2; i+4 <= len(data); {
+        if data[i] != 0xFF {
+            return 1
+        }
+        marker := data[i+1]
+        length := int(binary.BigEndian.Uint16(data[i+2:]))
+        // Start of scan: the metadata segments are all before it
+        if marker == 0xDA || length < 2 || i+2+length > len(data) {
+            return 1
+        }
+        segment := data[i+4 : i+2+length]
+        if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+            return exifOrientation(segment[6:])
+        }
+        i += 2 + length
+    }
+    return 1
+}
+
+// exifOrientation reads tag 0x0112 of IFD0 of a TIFF structure
+func exifOrientation(tiff []byte) int {
+    if len(tiff) < 8 {
+        return 1
+    }
+    var order binary.ByteOrder
+    switch string(tiff[:2]) {
+    case "II":
+        order = binary.LittleEndian
+    case "MM":
+        order = binary.BigEndian
+    default:
+        return 1
+    }
+
+    ifd := int(order.Uint32(tiff[4:]))
+    if ifd+2 > len(tiff) {
+        return 1
+    }
+    entries := int(order.Uint16(tiff[ifd:]))
+    for n := 0; n < entries; n++ {
+        entry := ifd + 2 + n*12
+        if entry+12 > len(tiff) {
+            return 1
+        }
+        if order.Uint16(tiff[entry:]) == 0x0112 {
+            if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+                return value
+            }
+            return 1
+        }
+    }
+    return 1
+}
+
+// Orient turns an image with the given EXIF orientation upright
+func Orient(img image.Image, orientation int) image.Image {
+    if orientation <= 1 || orientation > 8 {
+        return img
+    }
+    bounds := img.Bounds()
+    w, h := bounds.Dx(), bounds.Dy()
+    // Orientations 5 to 8 swap the axes
+    dw, dh := w, h
+    if orientation >= 5 {
+        dw, dh = h, w
+    }
+    dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            var dx, dy int
+            switch orientation {
+            case 2: // mirrored horizontally
+                dx, dy = w-1-x, y
+            case 3: // rotated 180°
+                dx, dy = w-1-x, h-1-y
+            case 4: // mirrored vertically
+                dx, dy = x, h-1-y
+            case 5: // mirrored along the top-left diagonal
+                dx, dy = y, x
+            case 6: // rotated 90° clockwise to display
+                dx, dy = h-1-y, x
+            case 7: // mirrored along the top-right diagonal
+                dx, dy = h-1-y, w-1-x
+            case 8: // rotated 90° counter-clockwise to display
+                dx, dy = y, w-1-x
+            }
+            dst.Set(dx, dy, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+        }
+    }
+    return dst
+}
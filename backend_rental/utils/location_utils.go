@@ -2,25 +2,25 @@
 package utils
 
 import (
+    "log/slog"
     "strings"
     "backend_rental/models"
     "regexp"
-    "log"
 )
 
 // FilterAndCleanCities filters and cleans city data
 func FilterAndCleanCities(cities []models.City) []models.City {
-    log.Printf("Starting cleaning process with %d cities", len(cities))
+    slog.Debug("cleaning cities", "count", len(cities))
     var cleanedCities []models.City
     seen := make(map[string]bool)
     
     for i, city := range cities {
         // Log each city being processed
-        log.Printf("Processing city %d: Name='%s', Country='%s'", i, city.CityName, city.Country)
+        slog.Debug("processing city", "index", i, "city", city.CityName, "country", city.Country)
         
         // Skip if name or country is empty
         if city.CityName == "" || city.Country == "" {
-            log.Printf("Skipping city %d: empty name or country", i)
+            slog.Debug("skipping city with empty name or country", "index", i)
             continue
         }
         
@@ -35,16 +35,16 @@ func FilterAndCleanCities(cities []models.City) []models.City {
         key := strings.ToLower(cleanCity.CityName + "|" + cleanCity.Country)
         
         if seen[key] {
-            log.Printf("Skipping duplicate city: %s, %s", cleanCity.CityName, cleanCity.Country)
+            slog.Debug("skipping duplicate city", "city", cleanCity.CityName, "country", cleanCity.Country)
             continue
         }
         
         seen[key] = true
         cleanedCities = append(cleanedCities, cleanCity)
-        log.Printf("Added cleaned city: %s, %s, ID: %s", cleanCity.CityName, cleanCity.Country, cleanCity.CityID)
+        slog.Debug("added cleaned city", "city", cleanCity.CityName, "country", cleanCity.Country, "city_id", cleanCity.CityID)
     }
     
-    log.Printf("Finished cleaning process. %d cities retained", len(cleanedCities))
+    slog.Debug("cleaned cities", "retained", len(cleanedCities))
     return cleanedCities
 }
 // func FilterAndCleanCities(cities []models.City) []models.City {
@@ -78,7 +78,7 @@ func FilterAndCleanCities(cities []models.City) []models.City {
 //     return cleanedCities
 // }
 func ConvertCitiesToLocations(cities []models.City) []models.Location {
-    log.Printf("Starting conversion of %d cities to locations", len(cities))
+    slog.Debug("converting cities to locations", "count", len(cities))
     locations := make([]models.Location, 0, len(cities))
     
     for i, city := range cities {
@@ -91,10 +91,10 @@ func ConvertCitiesToLocations(cities []models.City) []models.Location {
             Longitude:   0.0,
         }
         locations = append(locations, location)
-        log.Printf("Converted city %d to location: %s, %s", i, location.CityName, location.Country)
+        slog.Debug("converted city", "index", i, "city", location.CityName, "country", location.Country)
     }
     
-    log.Printf("Finished conversion. Created %d locations", len(locations))
+    slog.Debug("converted cities", "locations", len(locations))
     return locations
 }
 // func ConvertCitiesToLocations(cities []models.City) []models.Location {
@@ -127,6 +127,11 @@ func generateCityID(cityName, country string) string {
     slug = reg.ReplaceAllString(slug, "-")
     return slug
 }
+// CityLocationID returns the location ID FilterAndCleanCities assigns to a city
+func CityLocationID(cityName, country string) string {
+    return generateCityID(strings.TrimSpace(cityName), strings.TrimSpace(country))
+}
+
 // GenerateUniqueLocationID creates a unique ID for a location
 func GenerateUniqueLocationID(city models.City) string {
     return strings.ToLower(strings.ReplaceAll(city.CityID, " ", "-"))
@@ -182,7 +187,7 @@ func ConvertToCityMap(countryCities map[string][]string) map[string]bool {
     return cities
 }
 func RemoveDuplicateLocations(locations []models.Location) []models.Location {
-    log.Printf("Starting duplicate removal from %d locations", len(locations))
+    slog.Debug("removing duplicate locations", "count", len(locations))
     seen := make(map[string]bool)
     result := make([]models.Location, 0)
 
@@ -196,7 +201,7 @@ func RemoveDuplicateLocations(locations []models.Location) []models.Location {
         }
     }
     
-    log.Printf("Finished removing duplicates. %d locations remaining", len(result))
+    slog.Debug("removed duplicate locations", "remaining", len(result))
     return result
 }
 // // RemoveDuplicateLocations removes duplicate locations based on ID
@@ -0,0 +1,47 @@
+package logging
+
+import (
+    "context"
+    "fmt"
+    "log/slog"
+
+    "github.com/beego/beego/v2/core/logs"
+)
+
+// beegoAdapter forwards the framework's own log lines to log/slog, so
+// routing and ORM messages come out in the same format
+type beegoAdapter struct{}
+
+func init() {
+    logs.Register("slog", func() logs.Logger { return beegoAdapter{} })
+}
+
+func (beegoAdapter) Init(config string) error       { return nil }
+func (beegoAdapter) Destroy()                       {}
+func (beegoAdapter) Flush()                         {}
+func (beegoAdapter) SetFormatter(logs.LogFormatter) {}
+
+func (beegoAdapter) WriteMsg(lm *logs.LogMsg) error {
+    msg := lm.Msg
+    if len(lm.Args) > 0 {
+        msg = fmt.Sprintf(lm.Msg, lm.Args...)
+    }
+
+    level := slog.LevelDebug
+    switch {
+    case lm.Level <= logs.LevelError:
+        level = slog.LevelError
+    case lm.Level == logs.LevelWarning:
+        level = slog.LevelWarn
+    case lm.Level <= logs.LevelInformational:
+        level = slog.LevelInfo
+    }
+    slog.Default().Log(context.Background(), level, msg, "component", "beego")
+    return nil
+}
+
+// routeBeegoLogs replaces the framework's console output with the adapter
+func routeBeegoLogs() {
+    logs.Reset()
+    logs.SetLogger("slog")
+}
@@ -0,0 +1,157 @@
+// Package logging configures the structured logger of the service. Code logs
+// through log/slog, passing the request context where it has one, so every
+// line carries the request ID; secrets are redacted before output.
+package logging
+
+import (
+    "context"
+    "io"
+    "log/slog"
+    "net/http"
+    "os"
+    "strings"
+    "sync"
+
+    "go.opentelemetry.io/otel/trace"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys and header names whose values are never logged
+var sensitiveKeys = map[string]bool{
+    "authorization":       true,
+    "proxy-authorization": true,
+    "cookie":              true,
+    "set-cookie":          true,
+    "x-admin-token":       true,
+    "x-rapidapi-key":      true,
+    "rapidapikey":         true,
+    "admintoken":          true,
+    "password":            true,
+    "dbpassword":          true,
+    "s3accesskey":         true,
+    "s3secretkey":         true,
+}
+
+var (
+    secretsMu sync.RWMutex
+    // secrets are configured values scrubbed from every logged string, such
+    // as the RapidAPI key inside an upstream error message
+    secrets []string
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose log lines carry the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+    return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any
+func RequestID(ctx context.Context) string {
+    id, _ := ctx.Value(requestIDKey{}).(string)
+    return id
+}
+
+// AddSecret registers a value to redact wherever it appears in a log line;
+// short values are ignored so they cannot mangle ordinary text
+func AddSecret(value string) {
+    if len(value) < 8 {
+        return
+    }
+    secretsMu.Lock()
+    defer secretsMu.Unlock()
+    for _, s := range secrets {
+        if s == value {
+            return
+        }
+    }
+    secrets = append(secrets, value)
+}
+
+// Redact replaces the registered secrets in s
+func Redact(s string) string {
+    secretsMu.RLock()
+    defer secretsMu.RUnlock()
+    for _, secret := range secrets {
+        s = strings.ReplaceAll(s, secret, redacted)
+    }
+    return s
+}
+
+// Headers returns a copy of h fit for logging, with sensitive headers redacted
+func Headers(h http.Header) http.Header {
+    copied := h.Clone()
+    for name := range copied {
+        if sensitiveKeys[strings.ToLower(name)] {
+            copied[name] = []string{redacted}
+        }
+    }
+    return copied
+}
+
+// New returns a logger writing to w at level, as JSON or, for format text,
+// as key=value pairs
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+    opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+    var handler slog.Handler
+    if format == "text" {
+        handler = slog.NewTextHandler(w, opts)
+    } else {
+        handler = slog.NewJSONHandler(w, opts)
+    }
+    return slog.New(contextHandler{handler})
+}
+
+// Init makes a logger writing to stderr the default of log/slog, of the
+// standard log package and of beego. level is debug, info, warn or error.
+func Init(level, format string) {
+    var l slog.Level
+    if err := l.UnmarshalText([]byte(level)); err != nil {
+        l = slog.LevelInfo
+    }
+    slog.SetDefault(New(os.Stderr, l, format))
+    routeBeegoLogs()
+}
+
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+    if sensitiveKeys[strings.ToLower(a.Key)] {
+        return slog.String(a.Key, redacted)
+    }
+    switch a.Value.Kind() {
+    case slog.KindString:
+        return slog.String(a.Key, Redact(a.Value.String()))
+    case slog.KindAny:
+        switch v := a.Value.Any().(type) {
+        case error:
+            return slog.String(a.Key, Redact(v.Error()))
+        case http.Header:
+            return slog.Any(a.Key, Headers(v))
+        }
+    }
+    return a
+}
+
+// contextHandler adds the request ID and the trace of the context to each
+// record, so log lines can be matched with spans
+type contextHandler struct {
+    slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+    if id := RequestID(ctx); id != "" {
+        r.AddAttrs(slog.String("request_id", id))
+    }
+    if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+        r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+    }
+    return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+    return contextHandler{h.Handler.WithGroup(name)}
+}
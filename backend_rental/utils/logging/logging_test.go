@@ -0,0 +1,36 @@
+package logging
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "log/slog"
+    "net/http"
+    "testing"
+)
+
+func TestLoggerRedactsSecretsAndCarriesRequestID(t *testing.T) {
+    AddSecret("rapid-api-key-123")
+    var out bytes.Buffer
+    logger := New(&out, slog.LevelInfo, "json")
+
+    header := http.Header{"X-Rapidapi-Key": {"rapid-api-key-123"}, "Accept": {"application/json"}}
+    ctx := WithRequestID(context.Background(), "req-1")
+    logger.ErrorContext(ctx, "upstream failed",
+        "error", errors.New("GET /v1?key=rapid-api-key-123: 403"), "headers", header, "x-admin-token", "secret")
+
+    var line map[string]interface{}
+    if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+        t.Fatalf("%v: %s", err, out.String())
+    }
+    if bytes.Contains(out.Bytes(), []byte("rapid-api-key-123")) || line["x-admin-token"] != redacted {
+        t.Errorf("secret logged: %s", out.String())
+    }
+    if line["request_id"] != "req-1" {
+        t.Errorf("request_id = %v", line["request_id"])
+    }
+    if headers, _ := line["headers"].(map[string]interface{}); headers["Accept"] == nil {
+        t.Errorf("headers = %v, want the other headers kept", line["headers"])
+    }
+}
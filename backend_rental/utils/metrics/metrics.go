@@ -0,0 +1,163 @@
+// Package metrics holds the Prometheus collectors of the service, served on
+// /metrics. Packages record into the collectors here rather than registering
+// their own, so the whole set is visible in one place.
+package metrics
+
+import (
+    "database/sql"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/collectors"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "rental"
+
+// Registry holds every collector; it is separate from the Prometheus default
+// registry so libraries cannot add metrics behind our back
+var Registry = prometheus.NewRegistry()
+
+var (
+    // HTTPRequestDuration observes API requests by route pattern, e.g.
+    // /v1/locations/:id, so IDs do not multiply the series
+    HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Subsystem: "http",
+        Name:      "request_duration_seconds",
+        Help:      "Duration of HTTP requests by method, route pattern and status code.",
+        Buckets:   prometheus.DefBuckets,
+    }, []string{"method", "route", "status"})
+
+    // UpstreamRequests counts upstream calls that were sent, by endpoint path
+    // and status code; status is "error" when no response arrived
+    UpstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Subsystem: "upstream",
+        Name:      "requests_total",
+        Help:      "Upstream API requests by endpoint and status code.",
+    }, []string{"endpoint", "status"})
+
+    UpstreamRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Subsystem: "upstream",
+        Name:      "request_duration_seconds",
+        Help:      "Duration of upstream API requests by endpoint, excluding rate limiter waits.",
+        Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+    }, []string{"endpoint"})
+
+    // UpstreamCacheLookups counts upstream cache lookups by endpoint path and
+    // result: memory_hit, postgres_hit, stale_hit or miss
+    UpstreamCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Subsystem: "upstream",
+        Name:      "cache_lookups_total",
+        Help:      "Upstream cache lookups by endpoint and result.",
+    }, []string{"endpoint", "result"})
+
+    // RateLimiterWait observes how long upstream calls queue for a slot; the
+    // limiter allows one call every few seconds, hence the long buckets
+    RateLimiterWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Subsystem: "ratelimiter",
+        Name:      "wait_seconds",
+        Help:      "Time upstream requests waited for the rate limiter.",
+        Buckets:   []float64{0.01, 0.1, 1, 5, 15, 30, 60, 120, 300},
+    })
+
+    CrawlRunning = prometheus.NewGauge(prometheus.GaugeOpts{
+        Namespace: namespace,
+        Subsystem: "crawl",
+        Name:      "running",
+        Help:      "1 while a location crawl runs.",
+    })
+    CrawlQueries = prometheus.NewGauge(prometheus.GaugeOpts{
+        Namespace: namespace,
+        Subsystem: "crawl",
+        Name:      "queries",
+        Help:      "Location queries of the current or last crawl.",
+    })
+    CrawlQueriesDone = prometheus.NewGauge(prometheus.GaugeOpts{
+        Namespace: namespace,
+        Subsystem: "crawl",
+        Name:      "queries_done",
+        Help:      "Location queries of the current or last crawl that have been fetched.",
+    })
+    CrawlCitiesFetched = prometheus.NewGauge(prometheus.GaugeOpts{
+        Namespace: namespace,
+        Subsystem: "crawl",
+        Name:      "cities_fetched",
+        Help:      "Cities fetched by the current or last crawl.",
+    })
+    CrawlRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Subsystem: "crawl",
+        Name:      "runs_total",
+        Help:      "Finished location crawls by status.",
+    }, []string{"status"})
+    CrawlLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+        Namespace: namespace,
+        Subsystem: "crawl",
+        Name:      "last_success_timestamp_seconds",
+        Help:      "Unix time the last successful crawl finished.",
+    })
+)
+
+func init() {
+    Registry.MustRegister(
+        collectors.NewGoCollector(),
+        collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+        HTTPRequestDuration,
+        UpstreamRequests,
+        UpstreamRequestDuration,
+        UpstreamCacheLookups,
+        RateLimiterWait,
+        CrawlRunning,
+        CrawlQueries,
+        CrawlQueriesDone,
+        CrawlCitiesFetched,
+        CrawlRuns,
+        CrawlLastSuccess,
+    )
+}
+
+// RegisterDB exports the connection pool stats of a database handle, labelled
+// with name; registering the same name twice is a no-op
+func RegisterDB(name string, db *sql.DB) {
+    err := Registry.Register(collectors.NewDBStatsCollector(db, name))
+    if _, ok := err.(prometheus.AlreadyRegisteredError); err != nil && !ok {
+        panic(err)
+    }
+}
+
+// RegisterGauge exports a value read at scrape time, such as a queue length
+func RegisterGauge(subsystem, name, help string, value func() float64) {
+    gauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+        Namespace: namespace,
+        Subsystem: subsystem,
+        Name:      name,
+        Help:      help,
+    }, value)
+    err := Registry.Register(gauge)
+    if _, ok := err.(prometheus.AlreadyRegisteredError); err != nil && !ok {
+        panic(err)
+    }
+}
+
+// ObserveUpstream records one upstream call; status is zero when it failed
+// without a response
+func ObserveUpstream(endpoint string, status int, elapsed time.Duration) {
+    label := "error"
+    if status != 0 {
+        label = strconv.Itoa(status)
+    }
+    UpstreamRequests.WithLabelValues(endpoint, label).Inc()
+    UpstreamRequestDuration.WithLabelValues(endpoint).Observe(elapsed.Seconds())
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+    return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
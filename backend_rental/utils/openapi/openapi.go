@@ -0,0 +1,498 @@
+// Package openapi builds the OpenAPI 3 document of the API from its routes and
+// the request and response types documented for them.
+//
+// Request types are the structs bound by package binding: `form` fields become
+// query parameters, `path` fields path parameters and `json` fields properties
+// of the request body, with constraints read from their `valid` rules.
+// Response types are described from their JSON encoding.
+package openapi
+
+import (
+    "encoding/json"
+    "net/http"
+    "reflect"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/pagination"
+)
+
+const Version = "3.0.3"
+
+// Document is an OpenAPI 3 document
+type Document struct {
+    OpenAPI    string              `json:"openapi"`
+    Info       Info                `json:"info"`
+    Paths      map[string]PathItem `json:"paths"`
+    Components Components          `json:"components"`
+}
+
+type Info struct {
+    Title   string `json:"title"`
+    Version string `json:"version"`
+}
+
+// PathItem maps lower-case HTTP methods to the operations of a path
+type PathItem map[string]*Operation
+
+type Operation struct {
+    OperationID string                `json:"operationId"`
+    Summary     string                `json:"summary,omitempty"`
+    Tags        []string              `json:"tags,omitempty"`
+    Deprecated  bool                  `json:"deprecated,omitempty"`
+    Parameters  []Parameter           `json:"parameters,omitempty"`
+    RequestBody *RequestBody          `json:"requestBody,omitempty"`
+    Responses   map[string]Response   `json:"responses"`
+    Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+    Name     string  `json:"name"`
+    In       string  `json:"in"`
+    Required bool    `json:"required,omitempty"`
+    Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+    Required bool                 `json:"required,omitempty"`
+    Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+    Description string               `json:"description"`
+    Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+    Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+    Ref                  string             `json:"$ref,omitempty"`
+    Type                 string             `json:"type,omitempty"`
+    Format               string             `json:"format,omitempty"`
+    Nullable             bool               `json:"nullable,omitempty"`
+    Items                *Schema            `json:"items,omitempty"`
+    Properties           map[string]*Schema `json:"properties,omitempty"`
+    AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+    Required             []string           `json:"required,omitempty"`
+    Enum                 []string           `json:"enum,omitempty"`
+    Default              interface{}        `json:"default,omitempty"`
+    Pattern              string             `json:"pattern,omitempty"`
+    Minimum              *float64           `json:"minimum,omitempty"`
+    Maximum              *float64           `json:"maximum,omitempty"`
+    MinLength            *int               `json:"minLength,omitempty"`
+    MaxLength            *int               `json:"maxLength,omitempty"`
+    MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+type Components struct {
+    Schemas         map[string]*Schema        `json:"schemas"`
+    SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+    Type string `json:"type"`
+    In   string `json:"in"`
+    Name string `json:"name"`
+}
+
+// Security schemes of the API
+const (
+    AdminToken = "adminToken"
+    GuestID    = "guestId"
+    HostID     = "hostId"
+)
+
+// Route documents one registered route
+type Route struct {
+    // Method and Path as registered with the router, e.g. GET /v1/locations/:id
+    Method  string
+    Path    string
+    Summary string
+    Tag     string
+    // Request is the request struct the handler binds, nil when it takes no input
+    Request interface{}
+    // Response is the success body; see List for paginated envelopes
+    Response interface{}
+    // ContentType documents a non-JSON success body, e.g. image/*, instead of Response
+    ContentType string
+    // Status of a successful response, 200 when zero
+    Status     int
+    Security   string
+    Deprecated bool
+}
+
+// List describes the paginated envelope of a list endpoint with items of the
+// given type; With adds sibling fields such as facets
+func List(item interface{}) *Envelope {
+    return &Envelope{item: item, extra: map[string]interface{}{}}
+}
+
+// Envelope is a pagination.Envelope whose data type is known
+type Envelope struct {
+    item  interface{}
+    extra map[string]interface{}
+}
+
+// With adds a field next to data and pagination
+func (e *Envelope) With(name string, value interface{}) *Envelope {
+    e.extra[name] = value
+    return e
+}
+
+// Builder accumulates operations and the component schemas they reference
+type Builder struct {
+    doc   *Document
+    types map[string]reflect.Type
+}
+
+// New starts a document
+func New(title, version string) *Builder {
+    b := &Builder{
+        doc: &Document{
+            OpenAPI: Version,
+            Info:    Info{Title: title, Version: version},
+            Paths:   map[string]PathItem{},
+            Components: Components{
+                Schemas: map[string]*Schema{},
+                SecuritySchemes: map[string]SecurityScheme{
+                    AdminToken: {Type: "apiKey", In: "header", Name: "X-Admin-Token"},
+                    GuestID:    {Type: "apiKey", In: "header", Name: "X-Guest-ID"},
+                    HostID:     {Type: "apiKey", In: "header", Name: "X-Host-ID"},
+                },
+            },
+        },
+        types: map[string]reflect.Type{},
+    }
+    b.schema(reflect.TypeOf(apperrors.Problem{}))
+    return b
+}
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// PathOf converts a router pattern such as /v1/locations/:id into an OpenAPI path
+func PathOf(pattern string) string {
+    return pathParam.ReplaceAllString(pattern, "{$1}")
+}
+
+// Add documents a route
+func (b *Builder) Add(route Route) {
+    path := PathOf(route.Path)
+    method := strings.ToLower(route.Method)
+
+    op := &Operation{
+        OperationID: operationID(method, path),
+        Summary:     route.Summary,
+        Deprecated:  route.Deprecated,
+        Responses:   map[string]Response{},
+    }
+    if route.Tag != "" {
+        op.Tags = []string{route.Tag}
+    }
+    if route.Security != "" {
+        op.Security = []map[string][]string{{route.Security: {}}}
+    }
+    if route.Request != nil {
+        b.request(op, reflect.TypeOf(route.Request))
+    }
+
+    status := route.Status
+    if status == 0 {
+        status = http.StatusOK
+    }
+    response := Response{Description: http.StatusText(status)}
+    if route.Response != nil {
+        response.Content = map[string]MediaType{"application/json": {Schema: b.response(route.Response)}}
+    } else if route.ContentType != "" {
+        response.Content = map[string]MediaType{route.ContentType: {Schema: &Schema{Type: "string", Format: "binary"}}}
+    }
+    op.Responses[strconv.Itoa(status)] = response
+    op.Responses["default"] = Response{
+        Description: "Problem details",
+        Content: map[string]MediaType{
+            "application/problem+json": {Schema: &Schema{Ref: "#/components/schemas/Problem"}},
+        },
+    }
+
+    if b.doc.Paths[path] == nil {
+        b.doc.Paths[path] = PathItem{}
+    }
+    b.doc.Paths[path][method] = op
+}
+
+// Document returns the document built so far
+func (b *Builder) Document() *Document {
+    return b.doc
+}
+
+func operationID(method, path string) string {
+    var id strings.Builder
+    id.WriteString(method)
+    for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == '{' || r == '}' || r == '_' }) {
+        id.WriteString(strings.ToUpper(part[:1]) + part[1:])
+    }
+    return id.String()
+}
+
+func (b *Builder) response(v interface{}) *Schema {
+    envelope, ok := v.(*Envelope)
+    if !ok {
+        return b.schema(reflect.TypeOf(v))
+    }
+    schema := &Schema{
+        Type: "object",
+        Properties: map[string]*Schema{
+            "data":       {Type: "array", Items: b.schema(reflect.TypeOf(envelope.item))},
+            "pagination": b.schema(reflect.TypeOf(pagination.Page{})),
+        },
+        Required: []string{"data", "pagination"},
+    }
+    for name, value := range envelope.extra {
+        schema.Properties[name] = b.schema(reflect.TypeOf(value))
+    }
+    return schema
+}
+
+// request adds the parameters and body of a request struct to an operation
+func (b *Builder) request(op *Operation, t reflect.Type) {
+    body := &Schema{Type: "object", Properties: map[string]*Schema{}}
+    b.requestFields(op, body, t)
+    if len(body.Properties) > 0 {
+        sort.Strings(body.Required)
+        op.RequestBody = &RequestBody{
+            Required: true,
+            Content:  map[string]MediaType{"application/json": {Schema: body}},
+        }
+    }
+}
+
+func (b *Builder) requestFields(op *Operation, body *Schema, t reflect.Type) {
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        if field.Anonymous && field.Type.Kind() == reflect.Struct {
+            b.requestFields(op, body, field.Type)
+            continue
+        }
+        if !field.IsExported() {
+            continue
+        }
+
+        schema := b.schema(field.Type)
+        required := constrain(schema, field)
+
+        switch {
+        case field.Tag.Get("path") != "":
+            op.Parameters = append(op.Parameters, Parameter{Name: field.Tag.Get("path"), In: "path", Required: true, Schema: schema})
+        case field.Tag.Get("form") != "" && field.Tag.Get("form") != "-":
+            op.Parameters = append(op.Parameters, Parameter{Name: field.Tag.Get("form"), In: "query", Required: required, Schema: schema})
+        default:
+            name, ok := jsonName(field)
+            if !ok {
+                continue
+            }
+            body.Properties[name] = schema
+            if required {
+                body.Required = append(body.Required, name)
+            }
+        }
+    }
+}
+
+var rule = regexp.MustCompile(`^(\w+)(?:\((.*)\))?$`)
+
+// constrain applies the `default` and `valid` tags of a request field to its
+// schema and reports whether the field is required
+func constrain(schema *Schema, field reflect.StructField) bool {
+    if def, ok := field.Tag.Lookup("default"); ok {
+        schema.Default = def
+        switch schema.Type {
+        case "integer", "number":
+            if n := number(def); n != nil {
+                schema.Default = *n
+            }
+        case "boolean":
+            schema.Default = def == "true"
+        }
+    }
+
+    required := false
+    for _, r := range strings.Split(field.Tag.Get("valid"), ";") {
+        match := rule.FindStringSubmatch(strings.TrimSpace(r))
+        if match == nil {
+            continue
+        }
+        args := strings.Split(match[2], ",")
+        switch match[1] {
+        case "Required":
+            required = true
+        case "Min":
+            schema.Minimum = number(args[0])
+        case "Max":
+            schema.Maximum = number(args[0])
+        case "Range":
+            schema.Minimum, schema.Maximum = number(args[0]), number(args[1])
+        case "MinSize":
+            schema.MinLength = length(args[0])
+        case "MaxSize":
+            if schema.Type == "array" {
+                schema.MaxItems = length(args[0])
+            } else {
+                schema.MaxLength = length(args[0])
+            }
+        case "Length":
+            schema.MinLength, schema.MaxLength = length(args[0]), length(args[0])
+        case "Alpha":
+            schema.Pattern = "^[A-Za-z]*$"
+        case "Numeric":
+            schema.Pattern = "^[0-9]*$"
+        case "Enum":
+            schema.Enum = strings.Split(match[2], "|")
+        case "Date":
+            schema.Format = "date"
+        }
+    }
+    return required
+}
+
+func number(s string) *float64 {
+    f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+    if err != nil {
+        return nil
+    }
+    return &f
+}
+
+func length(s string) *int {
+    n, err := strconv.Atoi(strings.TrimSpace(s))
+    if err != nil {
+        return nil
+    }
+    return &n
+}
+
+// jsonName returns the name a field is encoded under, false when it is skipped
+func jsonName(field reflect.StructField) (string, bool) {
+    name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+    if name == "-" {
+        return "", false
+    }
+    if name == "" {
+        name = field.Name
+    }
+    return name, true
+}
+
+var (
+    timeType      = reflect.TypeOf(time.Time{})
+    rawJSONType   = reflect.TypeOf(json.RawMessage{})
+    marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schema describes the JSON encoding of a type; named structs become
+// component schemas and are referenced
+func (b *Builder) schema(t reflect.Type) *Schema {
+    if t == nil {
+        return &Schema{}
+    }
+    if t.Kind() == reflect.Ptr {
+        schema := b.schema(t.Elem())
+        if schema.Ref == "" {
+            schema.Nullable = true
+        }
+        return schema
+    }
+
+    switch t {
+    case timeType:
+        return &Schema{Type: "string", Format: "date-time"}
+    case rawJSONType:
+        return &Schema{}
+    }
+    if t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(marshalerType) {
+        // Custom encodings, e.g. gorm.DeletedAt, cannot be described by reflection
+        return &Schema{}
+    }
+
+    switch t.Kind() {
+    case reflect.String:
+        return &Schema{Type: "string"}
+    case reflect.Bool:
+        return &Schema{Type: "boolean"}
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+        return &Schema{Type: "integer", Format: "int32"}
+    case reflect.Int64, reflect.Uint64:
+        return &Schema{Type: "integer", Format: "int64"}
+    case reflect.Float32:
+        return &Schema{Type: "number", Format: "float"}
+    case reflect.Float64:
+        return &Schema{Type: "number", Format: "double"}
+    case reflect.Slice, reflect.Array:
+        if t.Elem().Kind() == reflect.Uint8 {
+            return &Schema{Type: "string", Format: "byte"}
+        }
+        return &Schema{Type: "array", Items: b.schema(t.Elem())}
+    case reflect.Map:
+        return &Schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+    case reflect.Struct:
+        if t.Name() == "" {
+            return b.object(t)
+        }
+        return b.component(t)
+    }
+    return &Schema{}
+}
+
+// component registers a named struct under components/schemas, qualifying
+// the name with its package when two packages use the same one
+func (b *Builder) component(t reflect.Type) *Schema {
+    name := t.Name()
+    if existing, ok := b.types[name]; ok && existing != t {
+        name = t.String()
+    }
+    ref := &Schema{Ref: "#/components/schemas/" + name}
+    if _, ok := b.types[name]; ok {
+        return ref
+    }
+    b.types[name] = t
+    // Registered before it is described so recursive types terminate
+    b.doc.Components.Schemas[name] = &Schema{}
+    *b.doc.Components.Schemas[name] = *b.object(t)
+    return ref
+}
+
+func (b *Builder) object(t reflect.Type) *Schema {
+    schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+    b.properties(schema, t)
+    sort.Strings(schema.Required)
+    return schema
+}
+
+func (b *Builder) properties(schema *Schema, t reflect.Type) {
+    for i := 0; i < t.NumField(); i++ {
+        field := t.Field(i)
+        embedded := field.Type
+        if embedded.Kind() == reflect.Ptr {
+            embedded = embedded.Elem()
+        }
+        if field.Anonymous && embedded.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+            b.properties(schema, embedded)
+            continue
+        }
+        if !field.IsExported() {
+            continue
+        }
+        name, ok := jsonName(field)
+        if !ok {
+            continue
+        }
+        schema.Properties[name] = b.schema(field.Type)
+        if !strings.Contains(field.Tag.Get("json"), ",omitempty") && field.Type.Kind() != reflect.Ptr {
+            schema.Required = append(schema.Required, name)
+        }
+    }
+}
@@ -0,0 +1,230 @@
+// Package pagination implements keyset pagination with opaque cursors and the
+// list envelope shared by the API's list endpoints.
+package pagination
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "net/url"
+    "strings"
+
+    "backend_rental/utils/apperrors"
+    "backend_rental/utils/binding"
+    "github.com/beego/beego/v2/client/orm"
+)
+
+const (
+    DefaultLimit = 20
+    MaxLimit     = 100
+)
+
+// ErrCursorMismatch is returned when a cursor was issued for a different sort
+var ErrCursorMismatch = apperrors.Validation("cursor does not match the requested sort")
+
+// Cursor is the position after (or, when Backward, before) the row whose
+// sort key values it holds
+type Cursor struct {
+    Values   []string `json:"v"`
+    Backward bool     `json:"b,omitempty"`
+}
+
+// Encode returns the opaque form of the cursor used in URLs
+func (c Cursor) Encode() string {
+    data, _ := json.Marshal(c)
+    return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses an opaque cursor
+func Decode(s string) (*Cursor, error) {
+    data, err := base64.RawURLEncoding.DecodeString(s)
+    if err != nil {
+        return nil, apperrors.Validation("invalid cursor")
+    }
+    var cursor Cursor
+    if err := json.Unmarshal(data, &cursor); err != nil || len(cursor.Values) == 0 {
+        return nil, apperrors.Validation("invalid cursor")
+    }
+    return &cursor, nil
+}
+
+// Params are the pagination query parameters of a list request
+type Params struct {
+    Limit        int
+    Cursor       *Cursor
+    IncludeTotal bool
+}
+
+// Query is the bindable form of the pagination parameters; list requests embed
+// it. The tag limits mirror DefaultLimit and MaxLimit.
+type Query struct {
+    Limit        int    `form:"limit" default:"20" valid:"Range(1,100)"`
+    Cursor       string `form:"cursor" valid:"MaxSize(1024)"`
+    IncludeTotal bool   `form:"include_total"`
+}
+
+// Params decodes the cursor of a bound query
+func (q Query) Params() (Params, error) {
+    params := Params{Limit: q.Limit, IncludeTotal: q.IncludeTotal}
+    if params.Limit == 0 {
+        params.Limit = DefaultLimit
+    }
+    if q.Cursor != "" {
+        cursor, err := Decode(q.Cursor)
+        if err != nil {
+            return Params{}, apperrors.InvalidFields(apperrors.FieldError{Field: "cursor", Message: "is not a valid cursor"})
+        }
+        params.Cursor = cursor
+    }
+    return params, nil
+}
+
+// ParseParams reads limit, cursor and include_total from the query string
+func ParseParams(values url.Values) (Params, error) {
+    var query Query
+    if err := binding.BindValues(values, &query); err != nil {
+        return Params{}, err
+    }
+    return query.Params()
+}
+
+// Backward reports whether the request pages towards the start of the list
+func (p Params) Backward() bool {
+    return p.Cursor != nil && p.Cursor.Backward
+}
+
+// Page is the pagination block of a list response
+type Page struct {
+    NextCursor string `json:"next_cursor,omitempty"`
+    PrevCursor string `json:"prev_cursor,omitempty"`
+    Limit      int    `json:"limit"`
+    TotalCount *int64 `json:"total_count,omitempty"`
+}
+
+// Envelope is the response shape of every paginated list endpoint
+type Envelope struct {
+    Data       interface{} `json:"data"`
+    Pagination Page        `json:"pagination"`
+}
+
+// Key is one column of a keyset ordering
+type Key struct {
+    Column string
+    Desc   bool
+}
+
+// Order is a keyset ordering; its last key must be unique so rows never tie
+type Order []Key
+
+// Clause returns the SQL ORDER BY list, reversed when paging backward
+func (o Order) Clause(backward bool) string {
+    parts := make([]string, len(o))
+    for i, key := range o {
+        direction := "ASC"
+        if key.Desc != backward {
+            direction = "DESC"
+        }
+        parts[i] = key.Column + " " + direction
+    }
+    return strings.Join(parts, ", ")
+}
+
+// Fields returns the ordering as Beego ORM OrderBy expressions
+func (o Order) Fields(backward bool) []string {
+    fields := make([]string, len(o))
+    for i, key := range o {
+        if key.Desc != backward {
+            fields[i] = "-" + key.Column
+        } else {
+            fields[i] = key.Column
+        }
+    }
+    return fields
+}
+
+// Where returns the SQL predicate selecting the rows past the cursor
+func (o Order) Where(cursor *Cursor) (string, []interface{}, error) {
+    if err := o.check(cursor); err != nil {
+        return "", nil, err
+    }
+
+    var clauses []string
+    var args []interface{}
+    for i, key := range o {
+        var terms []string
+        for _, previous := range o[:i] {
+            terms = append(terms, previous.Column+" = ?")
+            args = append(args, cursor.Values[len(terms)-1])
+        }
+        terms = append(terms, key.Column+" "+o.operator(key, cursor)+" ?")
+        args = append(args, cursor.Values[i])
+        clauses = append(clauses, "("+strings.Join(terms, " AND ")+")")
+    }
+    return "(" + strings.Join(clauses, " OR ") + ")", args, nil
+}
+
+// Condition returns the Beego ORM condition selecting the rows past the cursor
+func (o Order) Condition(cursor *Cursor) (*orm.Condition, error) {
+    if err := o.check(cursor); err != nil {
+        return nil, err
+    }
+
+    cond := orm.NewCondition()
+    for i, key := range o {
+        term := orm.NewCondition()
+        for j, previous := range o[:i] {
+            term = term.And(previous.Column, cursor.Values[j])
+        }
+        lookup := "__gt"
+        if o.operator(key, cursor) == "<" {
+            lookup = "__lt"
+        }
+        term = term.And(key.Column+lookup, cursor.Values[i])
+        cond = cond.OrCond(term)
+    }
+    return cond, nil
+}
+
+func (o Order) operator(key Key, cursor *Cursor) string {
+    if key.Desc != cursor.Backward {
+        return "<"
+    }
+    return ">"
+}
+
+func (o Order) check(cursor *Cursor) error {
+    if len(cursor.Values) != len(o) {
+        return ErrCursorMismatch
+    }
+    return nil
+}
+
+// Paginate trims a window fetched with Limit+1 rows in Clause(params.Backward())
+// order and returns the rows in list order with the cursors around them
+func Paginate[T any](rows []T, params Params, keys func(T) []string) ([]T, Page) {
+    page := Page{Limit: params.Limit}
+
+    hasMore := len(rows) > params.Limit
+    if hasMore {
+        rows = rows[:params.Limit]
+    }
+    if params.Backward() {
+        for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+            rows[i], rows[j] = rows[j], rows[i]
+        }
+    }
+    if len(rows) == 0 {
+        return rows, page
+    }
+
+    hasNext, hasPrev := hasMore, params.Cursor != nil
+    if params.Backward() {
+        hasNext, hasPrev = true, hasMore
+    }
+    if hasNext {
+        page.NextCursor = Cursor{Values: keys(rows[len(rows)-1])}.Encode()
+    }
+    if hasPrev {
+        page.PrevCursor = Cursor{Values: keys(rows[0]), Backward: true}.Encode()
+    }
+    return rows, page
+}
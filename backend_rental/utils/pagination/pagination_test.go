@@ -0,0 +1,75 @@
+package pagination
+
+import (
+    "net/url"
+    "reflect"
+    "testing"
+)
+
+var byPriceDesc = Order{{Column: "price", Desc: true}, {Column: "id"}}
+
+func TestOrderWhere(t *testing.T) {
+    where, args, err := byPriceDesc.Where(&Cursor{Values: []string{"120.5", "42"}})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := "((price < ?) OR (price = ? AND id > ?))"; where != want {
+        t.Errorf("Where = %q, want %q", where, want)
+    }
+    if want := []interface{}{"120.5", "120.5", "42"}; !reflect.DeepEqual(args, want) {
+        t.Errorf("args = %v, want %v", args, want)
+    }
+
+    where, _, _ = byPriceDesc.Where(&Cursor{Values: []string{"120.5", "42"}, Backward: true})
+    if want := "((price > ?) OR (price = ? AND id < ?))"; where != want {
+        t.Errorf("backward Where = %q, want %q", where, want)
+    }
+
+    if _, _, err := byPriceDesc.Where(&Cursor{Values: []string{"1"}}); err == nil {
+        t.Error("Where accepted a cursor for a different sort")
+    }
+}
+
+func TestOrderClause(t *testing.T) {
+    if got, want := byPriceDesc.Clause(false), "price DESC, id ASC"; got != want {
+        t.Errorf("Clause(false) = %q, want %q", got, want)
+    }
+    if got, want := byPriceDesc.Clause(true), "price ASC, id DESC"; got != want {
+        t.Errorf("Clause(true) = %q, want %q", got, want)
+    }
+}
+
+func TestPaginate(t *testing.T) {
+    keys := func(n int) []string { return []string{string(rune('a' + n))} }
+
+    // First page: one extra row means there is a next page but no previous one
+    rows, page := Paginate([]int{0, 1, 2}, Params{Limit: 2}, keys)
+    if !reflect.DeepEqual(rows, []int{0, 1}) || page.NextCursor == "" || page.PrevCursor != "" {
+        t.Fatalf("first page = %v %+v", rows, page)
+    }
+
+    next, err := Decode(page.NextCursor)
+    if err != nil || !reflect.DeepEqual(next.Values, []string{"b"}) || next.Backward {
+        t.Fatalf("next cursor = %+v, %v", next, err)
+    }
+
+    // Backward from row 2 fetches rows in reverse order; no extra row means the start
+    rows, page = Paginate([]int{1, 0}, Params{Limit: 2, Cursor: &Cursor{Values: []string{"c"}, Backward: true}}, keys)
+    if !reflect.DeepEqual(rows, []int{0, 1}) || page.NextCursor == "" || page.PrevCursor != "" {
+        t.Fatalf("backward page = %v %+v", rows, page)
+    }
+}
+
+func TestParseParams(t *testing.T) {
+    values := url.Values{"limit": {"5"}, "include_total": {"true"}, "cursor": {Cursor{Values: []string{"x"}}.Encode()}}
+    params, err := ParseParams(values)
+    if err != nil || params.Limit != 5 || !params.IncludeTotal || params.Cursor == nil {
+        t.Fatalf("ParseParams = %+v, %v", params, err)
+    }
+
+    for _, bad := range []url.Values{{"limit": {"0"}}, {"limit": {"500"}}, {"cursor": {"%%%"}}} {
+        if _, err := ParseParams(bad); err == nil {
+            t.Errorf("ParseParams(%v) returned no error", bad)
+        }
+    }
+}
@@ -5,6 +5,8 @@ import (
     "context"
     "sync"
     "time"
+    "backend_rental/utils/metrics"
+    "backend_rental/utils/tracing"
     "golang.org/x/time/rate"
 )
 
@@ -29,10 +31,15 @@ func GetInstance() *APIRateLimiter {
     return instance
 }
 
-// Wait blocks until the rate limiter allows an event to occur
-func (rl *APIRateLimiter) Wait(ctx context.Context) error {
+// Wait blocks until the rate limiter allows an event to occur; the wait is
+// traced as its own span so queueing shows apart from the upstream call
+func (rl *APIRateLimiter) Wait(ctx context.Context) (err error) {
     rl.mutex.RLock()
     defer rl.mutex.RUnlock()
+    ctx, span := tracing.Start(ctx, "ratelimiter.wait")
+    defer tracing.End(span, &err)
+    start := time.Now()
+    defer func() { metrics.RateLimiterWait.Observe(time.Since(start).Seconds()) }()
     return rl.limiter.Wait(ctx)
 }
 
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+    "context"
+    "errors"
+    "strings"
+
+    "github.com/beego/beego/v2/client/orm"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/trace"
+    "gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// GormPlugin records a span for each GORM statement run with a traced
+// context, i.e. through db.WithContext(ctx)
+type GormPlugin struct{}
+
+// Name implements gorm.Plugin
+func (GormPlugin) Name() string {
+    return "tracing"
+}
+
+// Initialize implements gorm.Plugin
+func (GormPlugin) Initialize(db *gorm.DB) error {
+    callbacks := db.Callback()
+    for _, err := range []error{
+        callbacks.Create().Before("gorm:create").Register("tracing:before_create", startGormSpan("create")),
+        callbacks.Create().After("gorm:create").Register("tracing:after_create", endGormSpan),
+        callbacks.Query().Before("gorm:query").Register("tracing:before_query", startGormSpan("query")),
+        callbacks.Query().After("gorm:query").Register("tracing:after_query", endGormSpan),
+        callbacks.Update().Before("gorm:update").Register("tracing:before_update", startGormSpan("update")),
+        callbacks.Update().After("gorm:update").Register("tracing:after_update", endGormSpan),
+        callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", startGormSpan("delete")),
+        callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", endGormSpan),
+        callbacks.Row().Before("gorm:row").Register("tracing:before_row", startGormSpan("row")),
+        callbacks.Row().After("gorm:row").Register("tracing:after_row", endGormSpan),
+        callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", startGormSpan("raw")),
+        callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", endGormSpan),
+    } {
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func startGormSpan(op string) func(*gorm.DB) {
+    return func(db *gorm.DB) {
+        ctx := db.Statement.Context
+        if ctx == nil || !Traced(ctx) {
+            return
+        }
+        ctx, span := Start(ctx, "gorm."+op+" "+db.Statement.Table,
+            attribute.String("db.system", "postgresql"),
+            attribute.String("db.operation", op),
+            attribute.String("db.sql.table", db.Statement.Table))
+        db.Statement.Context = ctx
+        db.InstanceSet(gormSpanKey, span)
+    }
+}
+
+func endGormSpan(db *gorm.DB) {
+    value, ok := db.InstanceGet(gormSpanKey)
+    if !ok {
+        return
+    }
+    span := value.(trace.Span)
+    span.SetAttributes(attribute.String("db.statement", db.Statement.SQL.String()),
+        attribute.Int64("db.rows_affected", db.RowsAffected))
+    err := db.Error
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        err = nil
+    }
+    End(span, &err)
+}
+
+// ORMFilterChain records a span for each Beego ORM call made with a traced
+// context through the *WithCtx methods. QueryTable and QueryM2M only build a
+// query, so they are not recorded.
+func ORMFilterChain(next orm.Filter) orm.Filter {
+    return func(ctx context.Context, inv *orm.Invocation) []interface{} {
+        if !Traced(ctx) || inv.Method == "QueryTable" || inv.Method == "QueryM2M" ||
+            inv.Method == "Driver" || inv.Method == "DBStats" {
+            return next(ctx, inv)
+        }
+        table := inv.GetTableName()
+        name := "orm." + strings.TrimSuffix(inv.Method, "WithCtx")
+        if table != "" {
+            name += " " + table
+        }
+        ctx, span := Start(ctx, name,
+            attribute.String("db.system", "postgresql"),
+            attribute.String("db.operation", inv.Method),
+            attribute.String("db.sql.table", table))
+        res := next(ctx, inv)
+        var err error
+        if len(res) > 0 {
+            if e, ok := res[len(res)-1].(error); ok && !errors.Is(e, orm.ErrNoRows) {
+                err = e
+            }
+        }
+        End(span, &err)
+        return res
+    }
+}
@@ -0,0 +1,115 @@
+// Package tracing sets up OpenTelemetry tracing. Spans cover inbound
+// requests, service methods, ORM queries and upstream calls; they are
+// exported over OTLP/HTTP or, for local runs, printed to stdout.
+package tracing
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "strings"
+
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+    "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName identifies the API in traces
+const ServiceName = "backend_rental"
+
+// Exporters selected by the tracingexporter setting
+const (
+    ExporterNone   = "none"
+    ExporterStdout = "stdout"
+    ExporterOTLP   = "otlp"
+)
+
+// Config selects where spans go
+type Config struct {
+    // Exporter is none, stdout or otlp
+    Exporter string
+    // Endpoint is the OTLP/HTTP collector, host:port or a URL; empty uses the
+    // OTEL_EXPORTER_OTLP_ENDPOINT environment variable or localhost:4318
+    Endpoint string
+    // Insecure sends OTLP over plain HTTP
+    Insecure bool
+    // SampleRatio is the share of new traces recorded, 0 to 1; requests that
+    // arrive with a sampled parent are always recorded
+    SampleRatio float64
+}
+
+// Init installs the global tracer provider and W3C trace context propagation.
+// The returned function flushes pending spans; call it on shutdown.
+func Init(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+    otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+    var exporter sdktrace.SpanExporter
+    var err error
+    switch strings.ToLower(cfg.Exporter) {
+    case "", ExporterNone:
+        return func(context.Context) error { return nil }, nil
+    case ExporterStdout:
+        exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+    case ExporterOTLP:
+        var opts []otlptracehttp.Option
+        if strings.Contains(cfg.Endpoint, "://") {
+            opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+        } else if cfg.Endpoint != "" {
+            opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+        }
+        if cfg.Insecure {
+            opts = append(opts, otlptracehttp.WithInsecure())
+        }
+        exporter, err = otlptracehttp.New(ctx, opts...)
+    default:
+        return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to create %s trace exporter: %v", cfg.Exporter, err)
+    }
+
+    res, err := resource.Merge(resource.Default(),
+        resource.NewSchemaless(attribute.String("service.name", ServiceName)))
+    if err != nil {
+        return nil, err
+    }
+
+    provider := sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(exporter),
+        sdktrace.WithResource(res),
+        sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+    )
+    otel.SetTracerProvider(provider)
+    return provider.Shutdown, nil
+}
+
+// Start starts a span named after the operation, e.g.
+// PropertyImageService.GetPhotos, as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+    return otel.Tracer(ServiceName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records *err on the span, if any, and ends it; defer it with the
+// address of a named error result:
+//
+//	ctx, span := tracing.Start(ctx, "Service.Method")
+//	defer tracing.End(span, &err)
+func End(span trace.Span, err *error) {
+    if err != nil && *err != nil {
+        span.RecordError(*err)
+        span.SetStatus(codes.Error, (*err).Error())
+    }
+    span.End()
+}
+
+// Traced reports whether ctx carries a span; database spans are only
+// recorded inside a trace so background queries do not each start one
+func Traced(ctx context.Context) bool {
+    return trace.SpanContextFromContext(ctx).IsValid()
+}
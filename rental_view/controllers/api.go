@@ -0,0 +1,34 @@
+package controllers
+
+import (
+    "errors"
+    "net/http"
+    "sync"
+
+    "backend_rental/client"
+    "github.com/beego/beego/v2/server/web"
+)
+
+// apiClient is the client of the backend API, at the apibaseurl of app.conf;
+// it revalidates catalogue reads so unchanged responses are not downloaded again
+var apiClient = sync.OnceValue(func() *client.Client {
+    return client.New(web.AppConfig.DefaultString("apibaseurl", "http://localhost:8080"),
+        client.WithRevalidation(web.AppConfig.DefaultInt("apirevalidationsize", 500)))
+})
+
+// respondAPIError passes a failed API call on to the browser with its status
+func respondAPIError(c *web.Controller, err error) {
+    status, message := http.StatusBadGateway, err.Error()
+    var apiErr *client.Error
+    if errors.As(err, &apiErr) {
+        status = apiErr.StatusCode
+        if apiErr.Detail != "" {
+            message = apiErr.Detail
+        }
+    }
+    c.Data["json"] = map[string]interface{}{
+        "error": message,
+    }
+    c.Ctx.Output.SetStatus(status)
+    c.ServeJSON()
+}
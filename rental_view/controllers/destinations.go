@@ -2,8 +2,7 @@
 package controllers
 
 import (
-    "encoding/json"
-    "net/http"
+    "backend_rental/client"
     "github.com/beego/beego/v2/server/web"
 )
 
@@ -11,47 +10,18 @@ type DestinationsController struct {
     web.Controller
 }
 
-// Match the actual API response structure
-type PropertyListResponse struct {
-    Locations []Location `json:"locations"`
-    Pagination struct {
-        Page       int `json:"page"`
-        PageSize   int `json:"page_size"`
-        TotalCount int `json:"total_count"`
-    } `json:"pagination"`
-}
-
-type Location struct {
-    // Add fields that exist in your locations array
-    // For now we'll just include city_name
-    CityName string `json:"city_name"`
-}
-
 func (c *DestinationsController) Get() {
-    resp, err := http.Get("http://localhost:8080/v1/property/list")
+    list, err := apiClient().ListLocations(c.Ctx.Request.Context(), client.LocationListParams{
+        PageParams: client.PageParams{Limit: 100},
+    })
     if err != nil {
-        c.Data["json"] = map[string]interface{}{
-            "error": err.Error(),
-        }
-        c.Ctx.Output.SetStatus(500)
-        c.ServeJSON()
-        return
-    }
-    defer resp.Body.Close()
-
-    var propertyList PropertyListResponse
-    if err := json.NewDecoder(resp.Body).Decode(&propertyList); err != nil {
-        c.Data["json"] = map[string]interface{}{
-            "error": "Failed to parse response: " + err.Error(),
-        }
-        c.Ctx.Output.SetStatus(500)
-        c.ServeJSON()
+        respondAPIError(&c.Controller, err)
         return
     }
 
     // Convert locations to the format expected by the frontend
-    cities := make([]map[string]string, 0)
-    for _, location := range propertyList.Locations {
+    cities := make([]map[string]string, 0, len(list.Data))
+    for _, location := range list.Data {
         cities = append(cities, map[string]string{
             "city_name": location.CityName,
         })
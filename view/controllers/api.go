@@ -0,0 +1,13 @@
+package controllers
+
+import (
+    "sync"
+
+    "backend_rental/client"
+    beego "github.com/beego/beego/v2/server/web"
+)
+
+// apiClient is the client of the backend API, at the apibaseurl of app.conf
+var apiClient = sync.OnceValue(func() *client.Client {
+    return client.New(beego.AppConfig.DefaultString("apibaseurl", "http://localhost:8080"))
+})
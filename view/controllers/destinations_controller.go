@@ -1,49 +1,33 @@
 package controllers
 
 import (
+    "backend_rental/client"
     beego "github.com/beego/beego/v2/server/web"
-    "net/http"
-    "encoding/json"
 )
 
 type DestinationsController struct {
     beego.Controller
 }
 
-type PropertyResponse struct {
-    Data []struct {
-        CityName string `json:"city_name"`
-    } `json:"data"`
-}
-
 type Destination struct {
     CityName string `json:"city_name"`
 }
 
 // GetDestinations handles the /v1/destinations route
 func (c *DestinationsController) GetDestinations() {
-    // Fetch data from the /v1/property/list API here
-    resp, err := http.Get("http://localhost:8080/v1/property/list")
-    if err != nil {
-        c.Ctx.Output.SetStatus(500)
-        c.Data["json"] = map[string]string{"error": "Failed to fetch data from property API"}
-        c.ServeJSON()
-        return
-    }
-    defer resp.Body.Close()
-
-    var propertyResponse PropertyResponse
-    err = json.NewDecoder(resp.Body).Decode(&propertyResponse)
+    list, err := apiClient().ListLocations(c.Ctx.Request.Context(), client.LocationListParams{
+        PageParams: client.PageParams{Limit: 100},
+    })
     if err != nil {
         c.Ctx.Output.SetStatus(500)
-        c.Data["json"] = map[string]string{"error": "Failed to parse property API response"}
+        c.Data["json"] = map[string]string{"error": "Failed to fetch destinations from the API"}
         c.ServeJSON()
         return
     }
 
     var destinations []Destination
-    for _, data := range propertyResponse.Data {
-        destinations = append(destinations, Destination{CityName: data.CityName})
+    for _, location := range list.Data {
+        destinations = append(destinations, Destination{CityName: location.CityName})
     }
 
     c.Data["json"] = destinations